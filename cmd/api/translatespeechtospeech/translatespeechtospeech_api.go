@@ -1,129 +1,291 @@
 package main
 
 import (
+	"context"
+	"embed"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io/fs"
 	"log"
 	"net/http"
 	"os"
-	"sync"
+	"os/signal"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
 
-	"github.com/gordonklaus/portaudio"
-	"github.com/gorilla/websocket"
 	"github.com/joho/godotenv"
 	"github.com/ngothientuong/tngo-ai-svcs/internal/ai/speech"
+	"github.com/ngothientuong/tngo-ai-svcs/internal/audio"
+	"github.com/ngothientuong/tngo-ai-svcs/pkg/httpauth"
 )
 
-var (
-	mu       sync.Mutex
-	clients  = make(map[*websocket.Conn]bool)
-	upgrader = websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
-	mode     = "both" // Default mode: "both", can be "text-only" or "speech-only"
-)
+// Web interface, built into the binary so the server does not depend on its working directory
+//
+//go:embed static
+var staticFiles embed.FS
+
+// Voices used when start names a target language without a voice
+var defaultVoices = map[string]string{
+	"vi": "vi-VN-HoaiMyNeural",
+	"fr": "fr-FR-DeniseNeural",
+	"es": "es-ES-ElviraNeural",
+	"de": "de-DE-KatjaNeural",
+	"ja": "ja-JP-NanamiNeural",
+}
+
+// envOr returns the environment variable key, or fallback when it is unset
+func envOr(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return fallback
+}
 
-// ModeChangeRequest handles mode switching via WebSocket
-type ModeChangeRequest struct {
-	Mode string `json:"mode"`
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
 
 func main() {
-	// Load environment variables
-	err := godotenv.Load("/home/tngo/ngo/projects/tngo-ai-svcs/.env")
-	if err != nil {
-		log.Fatalf("Error loading .env file")
+	// Load environment variables; deployments without a .env file rely on the process environment
+	envFile := os.Getenv("TRANSLATION_SERVER_ENV_FILE")
+	if envFile == "" {
+		envFile = ".env"
+	}
+	if err := godotenv.Load(envFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Fatalf("Error loading %s: %v", envFile, err)
 	}
 
-	// Load API keys separately
+	// Flags override the environment
+	addr := flag.String("addr", envOr("TRANSLATION_SERVER_ADDR", ":8080"), "Listen address")
+	tlsCert := flag.String("tls-cert", os.Getenv("TRANSLATION_SERVER_TLS_CERT"), "TLS certificate file; serves HTTPS and WSS together with -tls-key")
+	tlsKey := flag.String("tls-key", os.Getenv("TRANSLATION_SERVER_TLS_KEY"), "TLS private key file")
+	allowedOrigins := flag.String("allowed-origins", os.Getenv("TRANSLATION_SERVER_ALLOWED_ORIGINS"), "Comma-separated WebSocket origins, * for any; same host only when empty")
+	apiKeys := flag.String("api-keys", os.Getenv("TRANSLATION_SERVER_API_KEYS"), "Comma-separated API keys accepted on /start, /stop, /ws, /status and /devices")
+	jwtSecret := flag.String("jwt-secret", os.Getenv("TRANSLATION_SERVER_JWT_SECRET"), "HS256 secret of bearer tokens accepted on /start, /stop, /ws, /status and /devices")
+	jwtIssuer := flag.String("jwt-issuer", os.Getenv("TRANSLATION_SERVER_JWT_ISSUER"), "Required JWT issuer (optional)")
+	jwtAudience := flag.String("jwt-audience", os.Getenv("TRANSLATION_SERVER_JWT_AUDIENCE"), "Required JWT audience (optional)")
+	noAuth := flag.Bool("no-auth", os.Getenv("TRANSLATION_SERVER_NO_AUTH") == "true", "Serve /start, /stop, /ws, /status and /devices without authentication, for local development only")
+	inputDevice := flag.String("input-device", os.Getenv("TRANSLATION_SERVER_INPUT_DEVICE"), "PortAudio capture device (index, name or \"default\") for sessions without browser audio; the SDK default microphone when empty")
+	outputDevice := flag.String("output-device", os.Getenv("TRANSLATION_SERVER_OUTPUT_DEVICE"), "PortAudio device (index, name or \"default\") that also plays synthesized translations")
+	shutdownTimeout := flag.Duration("shutdown-timeout", 15*time.Second, "Time allowed for active sessions to stop on shutdown")
+	flag.Parse()
+
+	// Load API keys
 	speechKey := os.Getenv("SPEECH_KEY")
 	speechRegion := os.Getenv("SPEECH_REGION")
-	translationKey := os.Getenv("TRANSLATION_KEY")
-	translationEndpoint := os.Getenv("TRANSLATION_ENDPOINT")
-
-	err = portaudio.Initialize()
-	if err != nil {
-		log.Fatalf("Error initializing PortAudio: %v", err)
+	if speechKey == "" || speechRegion == "" {
+		log.Fatalf("Missing required credentials: SPEECH_KEY and SPEECH_REGION")
 	}
-	defer portaudio.Terminate()
-
-	// List available audio devices
-	devices, err := portaudio.Devices()
-	if err != nil {
-		log.Fatalf("Error getting audio devices: %v", err)
+	if (*tlsCert == "") != (*tlsKey == "") {
+		log.Fatalf("Both -tls-cert and -tls-key are required for TLS")
 	}
 
-	fmt.Println("Available Audio Devices:")
-	for i, dev := range devices {
-		fmt.Printf("%d: %s (Input: %d, Output: %d)\n", i, dev.Name, dev.MaxInputChannels, dev.MaxOutputChannels)
+	auth := &httpauth.Authenticator{APIKeys: splitList(*apiKeys), Disabled: *noAuth}
+	if *jwtSecret != "" {
+		auth.JWT = &httpauth.JWTValidator{
+			Secret:   []byte(*jwtSecret),
+			Issuer:   *jwtIssuer,
+			Audience: *jwtAudience,
+			Leeway:   30 * time.Second,
+		}
+	}
+	if err := auth.Validate(); err != nil {
+		log.Fatalf("Invalid authentication: %v; set -api-keys or -jwt-secret, or -no-auth to serve without authentication", err)
+	}
+	if !auth.Enabled() {
+		log.Println("⚠️ Authentication is disabled (-no-auth), /start, /stop, /ws, /status and /devices are open to everyone")
 	}
 
-	// Serve Web Interface
-	fs := http.FileServer(http.Dir("./static"))
-	http.Handle("/", fs)
+	// Server microphones and speakers are optional, browser clients stream their own audio
+	devices := []*audio.Device{}
+	if err := audio.Initialize(); err != nil {
+		log.Printf("PortAudio unavailable, only browser audio is supported: %v", err)
+		if *inputDevice != "" || *outputDevice != "" {
+			log.Fatalf("Audio devices require PortAudio")
+		}
+	} else {
+		defer audio.Terminate()
 
-	// WebSocket handler for real-time updates
-	http.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
-		conn, err := upgrader.Upgrade(w, r, nil)
+		// List available audio devices
+		devices, err = audio.Devices()
 		if err != nil {
-			log.Println("WebSocket upgrade failed:", err)
-			return
+			log.Printf("Error getting audio devices: %v", err)
 		}
-		defer conn.Close()
 
-		mu.Lock()
-		clients[conn] = true
-		mu.Unlock()
+		fmt.Println("Available Audio Devices:")
+		for _, dev := range devices {
+			fmt.Printf("%d: %s [%s] (Input: %d %v, Output: %d %v)\n", dev.Index, dev.Name, dev.HostAPI, dev.MaxInputChannels, dev.InputSampleRates, dev.MaxOutputChannels, dev.OutputSampleRates)
+		}
 
-		// Keep connection alive
-		for {
-			_, message, err := conn.ReadMessage()
-			if err != nil {
-				mu.Lock()
-				delete(clients, conn)
-				mu.Unlock()
-				break
+		// Fail at startup rather than on the first session
+		if *inputDevice != "" && *inputDevice != "default" {
+			if _, err := audio.FindInputDevice(*inputDevice); err != nil {
+				log.Fatalf("Invalid input device: %v", err)
 			}
-
-			// Handle mode change requests from frontend
-			var request ModeChangeRequest
-			if err := json.Unmarshal(message, &request); err == nil && request.Mode != "" {
-				mu.Lock()
-				mode = request.Mode
-				mu.Unlock()
-				log.Println("Mode changed to:", mode)
+		}
+		if *outputDevice != "" && *outputDevice != "default" {
+			if _, err := audio.FindOutputDevice(*outputDevice); err != nil {
+				log.Fatalf("Invalid output device: %v", err)
 			}
 		}
-	})
+	}
 
-	// Start speech-to-speech translation
-	http.HandleFunc("/start", func(w http.ResponseWriter, r *http.Request) {
-		mu.Lock()
-		defer mu.Unlock()
+	mux := http.NewServeMux()
 
-		go func() {
-			err := speech.TranslateSpeechFromMicrophone(speechKey, speechRegion, translationKey, translationEndpoint, "vi", mode)
-			if err != nil {
-				log.Printf("Translation failed: %v", err)
-			}
-		}()
+	// Serve Web Interface
+	static, err := fs.Sub(staticFiles, "static")
+	if err != nil {
+		log.Fatalf("Error loading web interface: %v", err)
+	}
+	mux.Handle("/", http.FileServer(http.FS(static)))
 
-		fmt.Fprintln(w, "Translation session started")
+	// Every WebSocket connection owns its session, see docs/TranslationWebSocketProtocol.md
+	sessions := speech.NewTranslationSessionManager()
+	translationServer := speech.NewTranslationServer(sessions, speech.TranslationServerConfig{
+		SpeechKey:     speechKey,
+		SpeechRegion:  speechRegion,
+		DefaultVoices: defaultVoices,
+		InputDevice:   *inputDevice,
+		OutputDevice:  *outputDevice,
+		CheckOrigin:   httpauth.OriginChecker(splitList(*allowedOrigins)),
 	})
+	mux.Handle("/ws", auth.Middleware(translationServer))
 
-	// Stop speech translation
-	http.HandleFunc("/stop", func(w http.ResponseWriter, r *http.Request) {
-		mu.Lock()
-		defer mu.Unlock()
+	// Start a session on the server microphone, e.g. /start?to=vi,fr&mode=both&voice.fr=fr-FR-HenriNeural;
+	// browsers that stream their own audio use the start message on /ws instead
+	mux.Handle("/start", auth.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		languages := []string{"vi"}
+		if to := splitList(query.Get("to")); len(to) > 0 {
+			languages = to
+		}
+		voices := make(map[string]string)
+		for _, language := range languages {
+			if voice := query.Get("voice." + language); voice != "" {
+				voices[language] = voice
+			} else if voice, ok := defaultVoices[language]; ok {
+				voices[language] = voice
+			}
+		}
 
-		// Send stop signal to recognizer
-		err := speech.StopSpeechRecognition()
+		session, err := sessions.Start(speech.TranslationSessionConfig{
+			SpeechKey:       speechKey,
+			SpeechRegion:    speechRegion,
+			SourceLanguage:  query.Get("from"),
+			TargetLanguages: languages,
+			Voices:          voices,
+			Mode:            speech.TranslationMode(query.Get("mode")),
+			InputDevice:     *inputDevice,
+			OutputDevice:    *outputDevice,
+		})
 		if err != nil {
-			http.Error(w, "Failed to stop recognition", http.StatusInternalServerError)
+			log.Printf("Translation failed: %v", err)
+			http.Error(w, "Failed to start translation", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(session.Status())
+	})))
+
+	// Stop a session, e.g. /stop?session=<id>
+	mux.Handle("/stop", auth.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := sessions.Stop(r.URL.Query().Get("session")); err != nil {
+			http.Error(w, fmt.Sprintf("Failed to stop translation: %v", err), http.StatusNotFound)
 			return
 		}
 
 		fmt.Fprintln(w, "Translation session stopped")
+	})))
+
+	// Report one session, or all sessions when no ID is given
+	mux.Handle("/status", auth.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id := r.URL.Query().Get("session")
+		if id == "" {
+			json.NewEncoder(w).Encode(sessions.List())
+			return
+		}
+		status, err := sessions.Status(id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(status)
+	})))
+
+	// List the server's audio devices
+	mux.Handle("/devices", auth.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(devices)
+	})))
+
+	// Liveness: the process is serving requests
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
 	})
 
-	log.Println("Server started on :8080")
-	log.Fatal(http.ListenAndServe("0.0.0.0:8080", nil))
+	// Readiness: new sessions are accepted until shutdown begins
+	var draining atomic.Bool
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if draining.Load() {
+			http.Error(w, "shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ready")
+	})
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       120 * time.Second,
+	}
+	// http.Server.Shutdown leaves hijacked WebSockets open, closing them stops their sessions
+	server.RegisterOnShutdown(translationServer.Shutdown)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if *tlsCert != "" {
+			log.Printf("Server started on %s (TLS)", *addr)
+			serveErr <- server.ListenAndServeTLS(*tlsCert, *tlsKey)
+		} else {
+			log.Printf("Server started on %s", *addr)
+			serveErr <- server.ListenAndServe()
+		}
+	}()
+
+	// Stop on Ctrl+C or SIGTERM from the orchestrator
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Server failed: %v", err)
+		}
+	case <-ctx.Done():
+		log.Println("Shutting down...")
+	}
+
+	draining.Store(true)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Error shutting down server: %v", err)
+	}
+	sessions.StopAll()
+	log.Println("Server stopped")
 }
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/ngothientuong/tngo-ai-svcs/internal/ai/speech"
+	"github.com/ngothientuong/tngo-ai-svcs/internal/ai/translator"
+)
+
+func main() {
+	// Load environment variables
+	err := godotenv.Load("/home/tngo/ngo/projects/tngo-ai-svcs/.env")
+	if err != nil {
+		log.Fatalf("Error loading .env file")
+	}
+
+	// Get API credentials
+	subscriptionKey := os.Getenv("SPEECH_KEY")
+	region := os.Getenv("SPEECH_REGION")
+
+	// Recognition options from the environment, overridden by flags
+	recognizerOptions, err := speech.RecognizerOptionsFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid recognition options: %v", err)
+	}
+	recognizerOptions.RegisterFlags(flag.CommandLine)
+
+	// Parse command-line arguments
+	filePath := flag.String("file", "", "Path to the audio file to caption (omit to caption the microphone in real time)")
+	outputPath := flag.String("output", "", "Path to the subtitle file, translated tracks are written next to it as <name>.<lang>.<ext>")
+	format := flag.String("format", "srt", "Subtitle format (srt, vtt)")
+	maxLineLength := flag.Int("max-line-length", 42, "Maximum characters per caption line")
+	linesPerCue := flag.Int("lines", 2, "Maximum lines per caption cue")
+	minDuration := flag.Duration("min-duration", 0, "Minimum cue duration such as 1s (optional)")
+	maxDuration := flag.Duration("max-duration", 0, "Maximum cue duration such as 7s (optional)")
+	maskProfanity := flag.Bool("mask-profanity", false, "Replace words the service masked, and -profanity-words, entirely with asterisks")
+	profanityWords := flag.String("profanity-words", "", "Comma-separated words masked by -mask-profanity in addition to those the service masked (optional)")
+	translateTo := flag.String("translate", "", "Comma-separated target languages for translated subtitle tracks (optional)")
+	flag.Parse()
+
+	// Validate input
+	if subscriptionKey == "" || region == "" {
+		log.Fatalf("Missing required credentials")
+	}
+	if *format != "srt" && *format != "vtt" {
+		log.Fatalf("Invalid format specified. Example usage: go run captioning_cmd.go -file ./sample.wav -output ./sample.srt -format srt")
+	}
+
+	captionOptions := &speech.CaptionOptions{
+		MaxLineLength:  *maxLineLength,
+		LinesPerCue:    *linesPerCue,
+		MinCueDuration: *minDuration,
+		MaxCueDuration: *maxDuration,
+		MaskProfanity:  *maskProfanity,
+	}
+	for _, word := range strings.Split(*profanityWords, ",") {
+		if word = strings.TrimSpace(word); word != "" {
+			captionOptions.ProfanityWords = append(captionOptions.ProfanityWords, word)
+		}
+	}
+	if len(captionOptions.ProfanityWords) > 0 && !captionOptions.MaskProfanity {
+		log.Fatalf("-profanity-words requires -mask-profanity")
+	}
+	if err := recognizerOptions.Validate(); err != nil {
+		log.Fatalf("Invalid recognition options: %v", err)
+	}
+
+	// Real-time mode: caption the microphone until Ctrl+C
+	if *filePath == "" {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		recognizer, err := speech.TranscribeFromMicrophone(ctx, subscriptionKey, region, recognizerOptions)
+		if err != nil {
+			log.Fatalf("Speech recognition failed: %v", err)
+		}
+		for event := range speech.StreamCaptions(recognizer, captionOptions) {
+			if event.Partial {
+				fmt.Printf("\r🔵 %s", strings.ReplaceAll(event.Cue.Text(), "\n", " | "))
+				continue
+			}
+			fmt.Printf("\r✅ [%s - %s] %s\n", event.Cue.Start, event.Cue.End, strings.ReplaceAll(event.Cue.Text(), "\n", " | "))
+		}
+		if err := recognizer.Wait(); err != nil {
+			log.Fatalf("Speech recognition failed: %v", err)
+		}
+		return
+	}
+
+	// Post-processed mode: caption the whole file
+	if *outputPath == "" {
+		log.Fatalf("Missing required parameters. Example usage: go run captioning_cmd.go -file ./sample.wav -output ./sample.srt -format srt -translate fr,vi")
+	}
+	transcript, err := speech.TranscribeFile(subscriptionKey, region, *filePath, recognizerOptions)
+	if err != nil {
+		log.Fatalf("Speech recognition failed: %v", err)
+	}
+	cues := speech.BuildCues(transcript.Segments, captionOptions)
+	if err := writeSubtitles(*outputPath, *format, cues); err != nil {
+		log.Fatalf("Error writing subtitles: %v", err)
+	}
+	fmt.Printf("Subtitles saved to %s\n", *outputPath)
+
+	if *translateTo == "" {
+		return
+	}
+
+	endpoint := os.Getenv("TRANSLATOR_ENDPOINT")
+	key := os.Getenv("TRANSLATOR_KEY")
+	apiVersion := os.Getenv("TRANSLATOR_API_VERSION")
+	translatorRegion := os.Getenv("TRANSLATOR_REGION")
+	if endpoint == "" || key == "" || apiVersion == "" || translatorRegion == "" {
+		log.Fatalf("Missing required translator credentials")
+	}
+	client := translator.NewTranslatorClient(endpoint, key, translatorRegion, apiVersion)
+
+	// Identified languages vary between cues, so Translator detects them instead
+	from := recognizerOptions.Language
+	if len(recognizerOptions.CandidateLanguages) > 0 {
+		from = ""
+	}
+	tracks, err := speech.TranslateCues(client, cues, from, strings.Split(*translateTo, ","), captionOptions)
+	if err != nil {
+		log.Fatalf("Error translating subtitles: %v", err)
+	}
+	for lang, trackCues := range tracks {
+		ext := filepath.Ext(*outputPath)
+		trackPath := fmt.Sprintf("%s.%s%s", strings.TrimSuffix(*outputPath, ext), lang, ext)
+		if err := writeSubtitles(trackPath, *format, trackCues); err != nil {
+			log.Fatalf("Error writing %s subtitles: %v", lang, err)
+		}
+		fmt.Printf("Subtitles (%s) saved to %s\n", lang, trackPath)
+	}
+}
+
+// writeSubtitles writes cues to path in the requested format; a failed close is reported, since it may lose the end of the file
+func writeSubtitles(path, format string, cues []speech.Cue) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	var write func(io.Writer, []speech.Cue) error = speech.WriteSRT
+	if format == "vtt" {
+		write = speech.WriteWebVTT
+	}
+	if err := write(file, cues); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
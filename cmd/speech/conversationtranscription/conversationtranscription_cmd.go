@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/ngothientuong/tngo-ai-svcs/internal/ai/speech"
+	"github.com/ngothientuong/tngo-ai-svcs/internal/audio/audioutil"
+)
+
+func main() {
+	// Load environment variables from .env file
+	err := godotenv.Load("/home/tngo/ngo/projects/tngo-ai-svcs/.env")
+	if err != nil {
+		log.Fatalf("Error loading .env file: %v", err)
+	}
+
+	// Get the endpoint, key, region, and API version from environment variables
+	endpoint := os.Getenv("SPEECH_ENDPOINT")
+	key := os.Getenv("SPEECH_KEY")
+	region := os.Getenv("SPEECH_REGION")
+	apiVersion := os.Getenv("SPEECH_TO_TEXT_API_VERSION")
+
+	// Parse command-line arguments
+	filePath := flag.String("file", "", "Path to the recording; reads from stdin when empty")
+	stream := flag.Bool("stream", false, "Treat the input as a live WAV stream and print each chunk as soon as it is diarized")
+	chunk := flag.Duration("chunk", 30*time.Second, "Audio per request with -stream; speaker IDs are only consistent within a chunk")
+	format := flag.String("format", "md", "Output format: json, md, txt")
+	output := flag.String("output", "", "Output file; writes to stdout when empty")
+	locales := flag.String("locales", "en-US", "Comma-separated candidate locales")
+	maxSpeakers := flag.Int("max-speakers", 2, "Maximum number of speakers")
+	maxGap := flag.Duration("max-gap", 2*time.Second, "Merge same-speaker segments separated by at most this gap")
+	flag.Parse()
+
+	// Validate input
+	if endpoint == "" || key == "" || apiVersion == "" {
+		log.Fatalf("Missing required credentials")
+	}
+
+	transcriber := speech.NewConversationTranscriber(endpoint, key, region, apiVersion, &speech.ConversationOptions{
+		Locales:       strings.Split(*locales, ","),
+		MaxSpeakers:   *maxSpeakers,
+		MaxGap:        *maxGap,
+		ChunkDuration: *chunk,
+	})
+
+	var conversation *speech.Conversation
+	switch {
+	case *stream:
+		conversation, err = transcribeStream(transcriber, *filePath)
+	case *filePath != "":
+		conversation, err = transcriber.TranscribeFile(*filePath)
+	default:
+		conversation, err = transcriber.TranscribeRecording(os.Stdin, "stdin")
+	}
+	if err != nil {
+		log.Fatalf("Conversation transcription failed: %v", err)
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			log.Fatalf("Error creating output file: %v", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	switch *format {
+	case "json":
+		err = conversation.WriteJSON(out)
+	case "md":
+		err = conversation.WriteMarkdown(out)
+	case "txt":
+		err = conversation.WriteText(out)
+	default:
+		log.Fatalf("Unknown format: %s", *format)
+	}
+	if err != nil {
+		log.Fatalf("Error writing transcript: %v", err)
+	}
+}
+
+// transcribeStream diarizes a WAV stream from path or stdin chunk by chunk, printing utterances to stderr as they arrive
+func transcribeStream(transcriber *speech.ConversationTranscriber, path string) (*speech.Conversation, error) {
+	var input io.Reader = os.Stdin
+	name := "stdin"
+	if path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open audio file: %v", err)
+		}
+		defer file.Close()
+		input, name = file, path
+	}
+
+	header, err := audioutil.ReadWAVHeader(input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ctrl+C stops after the chunk being transcribed
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return transcriber.TranscribeStream(ctx, input, header.Format, name, func(chunk speech.ConversationChunk) {
+		for _, utterance := range chunk.Utterances {
+			fmt.Fprintf(os.Stderr, "[%s] %s: %s\n", utterance.Start.Round(time.Second), speech.SpeakerLabel(utterance.Speaker), utterance.Text)
+		}
+	})
+}
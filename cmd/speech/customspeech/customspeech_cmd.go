@@ -0,0 +1,331 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/ngothientuong/tngo-ai-svcs/internal/ai/speech"
+)
+
+const usage = `Usage: customspeech <command> [flags]
+
+Commands:
+  datasets [-kind kind]                     List datasets
+  upload -name n -locale l -kind k (-file path | -url url) [-wait d]
+                                            Upload or import a dataset (Language, LanguageMarkdown, Pronunciation, Acoustic)
+  basemodels [-locale l]                    List the base models of a locale
+  models [-locale l]                        List custom models
+  train -name n -locale l -datasets id,id [-base-model id] [-wait d]
+                                            Train a custom model, from the latest base model by default
+  evaluate -name n -locale l -model1 id -model2 id -dataset id [-wait d]
+                                            Compare the word error rate of two models on an Acoustic dataset
+  deploy -name n -locale l -model id [-logging] [-wait d]
+                                            Deploy a model; recognize with it using -endpoint-id in speechtotextfile
+  endpoints                                 List endpoints
+  delete <dataset|model|evaluation|endpoint> <id>
+                                            Delete an entity
+
+Model IDs may be custom or base model IDs.`
+
+func main() {
+	// Load environment variables from .env file
+	err := godotenv.Load("/home/tngo/ngo/projects/tngo-ai-svcs/.env")
+	if err != nil {
+		log.Fatalf("Error loading .env file: %v", err)
+	}
+
+	// Get the endpoint, key, region, and API version from environment variables
+	endpoint := os.Getenv("SPEECH_ENDPOINT")
+	key := os.Getenv("SPEECH_KEY")
+	region := os.Getenv("SPEECH_REGION")
+	apiVersion := os.Getenv("SPEECH_TO_TEXT_API_VERSION")
+
+	flag.Usage = func() { fmt.Fprintln(os.Stderr, usage) }
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Validate input
+	if endpoint == "" || key == "" || apiVersion == "" {
+		log.Fatalf("Missing required credentials")
+	}
+
+	// Create a new CustomSpeechClient
+	client := speech.NewCustomSpeechClient(endpoint, key, region, apiVersion)
+
+	command, args := flag.Arg(0), flag.Args()[1:]
+	switch command {
+	case "datasets":
+		listDatasets(client, args)
+	case "upload":
+		uploadDataset(client, args)
+	case "basemodels":
+		listModels(client, args, true)
+	case "models":
+		listModels(client, args, false)
+	case "train":
+		trainModel(client, args)
+	case "evaluate":
+		evaluateModels(client, args)
+	case "deploy":
+		deployModel(client, args)
+	case "endpoints":
+		listEndpoints(client)
+	case "delete":
+		deleteEntity(client, args)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func listDatasets(client *speech.CustomSpeechClient, args []string) {
+	flags := flag.NewFlagSet("datasets", flag.ExitOnError)
+	kind := flags.String("kind", "", "Only list datasets of this kind")
+	flags.Parse(args)
+
+	datasets, err := client.ListDatasets(*kind)
+	if err != nil {
+		log.Fatalf("Error listing datasets: %v", err)
+	}
+	for _, dataset := range datasets {
+		fmt.Printf("ID: %s, Name: %s, Kind: %s, Locale: %s, Status: %s\n", dataset.ID(), dataset.DisplayName, dataset.Kind, dataset.Locale, dataset.Status)
+	}
+}
+
+func uploadDataset(client *speech.CustomSpeechClient, args []string) {
+	flags := flag.NewFlagSet("upload", flag.ExitOnError)
+	name := flags.String("name", "", "Dataset display name")
+	description := flags.String("description", "", "Dataset description")
+	locale := flags.String("locale", "en-US", "Dataset locale")
+	kind := flags.String("kind", speech.DatasetKindLanguage, "Dataset kind: Language, LanguageMarkdown, Pronunciation, Acoustic")
+	filePath := flags.String("file", "", "Local .txt, .md or .zip file to upload")
+	contentURL := flags.String("url", "", "Public or SAS URL of the dataset, instead of -file")
+	wait := flags.Duration("wait", 0, "Wait up to this long for the dataset to be processed, e.g. 10m")
+	flags.Parse(args)
+
+	if *name == "" || (*filePath == "") == (*contentURL == "") {
+		log.Fatalf("-name and either -file or -url are required")
+	}
+
+	request := speech.DatasetRequest{DisplayName: *name, Description: *description, Locale: *locale, Kind: *kind, ContentURL: *contentURL}
+	var dataset *speech.CustomSpeechDataset
+	var err error
+	if *filePath != "" {
+		dataset, err = client.UploadDataset(request, *filePath)
+	} else {
+		dataset, err = client.CreateDataset(request)
+	}
+	if err != nil {
+		log.Fatalf("Error creating dataset: %v", err)
+	}
+	fmt.Printf("Dataset created: ID: %s, Status: %s\n", dataset.ID(), dataset.Status)
+
+	if *wait > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), *wait)
+		defer cancel()
+		dataset, err = client.WaitForDataset(ctx, dataset.ID(), 10*time.Second)
+		if err != nil {
+			log.Fatalf("Error waiting for dataset: %v", err)
+		}
+		fmt.Printf("✅ Dataset %s is ready: %d line(s) accepted, %d rejected\n", dataset.ID(), dataset.Properties.AcceptedLineCount, dataset.Properties.RejectedLineCount)
+	}
+}
+
+func listModels(client *speech.CustomSpeechClient, args []string, base bool) {
+	flags := flag.NewFlagSet("models", flag.ExitOnError)
+	locale := flags.String("locale", "", "Only list models of this locale")
+	flags.Parse(args)
+
+	var models []speech.CustomSpeechModel
+	var err error
+	if base {
+		models, err = client.ListBaseModels(*locale)
+	} else {
+		models, err = client.ListModels(*locale)
+	}
+	if err != nil {
+		log.Fatalf("Error listing models: %v", err)
+	}
+	for _, model := range models {
+		fmt.Printf("ID: %s, Name: %s, Locale: %s, Status: %s, Created: %s, Adaptation: %s, Expires: %s\n",
+			model.ID(), model.DisplayName, model.Locale, model.Status, model.CreatedDateTime,
+			strings.Join(model.Properties.Features.SupportsAdaptationsWith, "/"), model.Properties.DeprecationDates.TranscriptionDateTime)
+	}
+}
+
+func trainModel(client *speech.CustomSpeechClient, args []string) {
+	flags := flag.NewFlagSet("train", flag.ExitOnError)
+	name := flags.String("name", "", "Model display name")
+	description := flags.String("description", "", "Model description")
+	locale := flags.String("locale", "en-US", "Model locale")
+	datasetIDs := flags.String("datasets", "", "Comma-separated dataset IDs")
+	baseModelID := flags.String("base-model", "", "Base model ID, the latest base model of the locale when empty")
+	wait := flags.Duration("wait", 0, "Wait up to this long for training to finish, e.g. 2h")
+	flags.Parse(args)
+
+	if *name == "" || *datasetIDs == "" {
+		log.Fatalf("-name and -datasets are required")
+	}
+
+	request := speech.CustomSpeechModelRequest{DisplayName: *name, Description: *description, Locale: *locale}
+	for _, datasetID := range strings.Split(*datasetIDs, ",") {
+		dataset, err := client.GetDataset(strings.TrimSpace(datasetID))
+		if err != nil {
+			log.Fatalf("Error getting dataset: %v", err)
+		}
+		request.Datasets = append(request.Datasets, dataset.Reference())
+	}
+	if *baseModelID != "" {
+		baseModel, err := client.GetBaseModel(*baseModelID)
+		if err != nil {
+			log.Fatalf("Error getting base model: %v", err)
+		}
+		reference := baseModel.Reference()
+		request.BaseModel = &reference
+	}
+
+	model, err := client.TrainModel(request)
+	if err != nil {
+		log.Fatalf("Error training model: %v", err)
+	}
+	fmt.Printf("Training started: ID: %s\n", model.ID())
+
+	if *wait > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), *wait)
+		defer cancel()
+		model, err = client.WaitForModel(ctx, model.ID(), 0)
+		if err != nil {
+			log.Fatalf("Error waiting for model: %v", err)
+		}
+		fmt.Printf("✅ Model %s is trained\n", model.ID())
+	}
+}
+
+func evaluateModels(client *speech.CustomSpeechClient, args []string) {
+	flags := flag.NewFlagSet("evaluate", flag.ExitOnError)
+	name := flags.String("name", "", "Evaluation display name")
+	locale := flags.String("locale", "en-US", "Evaluation locale")
+	model1ID := flags.String("model1", "", "First model ID, usually the base model")
+	model2ID := flags.String("model2", "", "Second model ID, usually the custom model")
+	datasetID := flags.String("dataset", "", "Acoustic dataset ID not used for training")
+	wait := flags.Duration("wait", 0, "Wait up to this long for the evaluation to finish, e.g. 30m")
+	flags.Parse(args)
+
+	if *name == "" || *model1ID == "" || *model2ID == "" || *datasetID == "" {
+		log.Fatalf("-name, -model1, -model2 and -dataset are required")
+	}
+
+	dataset, err := client.GetDataset(*datasetID)
+	if err != nil {
+		log.Fatalf("Error getting dataset: %v", err)
+	}
+	evaluation, err := client.CreateEvaluation(speech.EvaluationRequest{
+		DisplayName: *name,
+		Locale:      *locale,
+		Model1:      modelReference(client, *model1ID),
+		Model2:      modelReference(client, *model2ID),
+		Dataset:     dataset.Reference(),
+	})
+	if err != nil {
+		log.Fatalf("Error creating evaluation: %v", err)
+	}
+	fmt.Printf("Evaluation started: ID: %s\n", evaluation.ID())
+
+	if *wait > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), *wait)
+		defer cancel()
+		evaluation, err = client.WaitForEvaluation(ctx, evaluation.ID(), 0)
+		if err != nil {
+			log.Fatalf("Error waiting for evaluation: %v", err)
+		}
+		fmt.Printf("✅ %s\n", evaluation.Summary())
+	}
+}
+
+func deployModel(client *speech.CustomSpeechClient, args []string) {
+	flags := flag.NewFlagSet("deploy", flag.ExitOnError)
+	name := flags.String("name", "", "Endpoint display name")
+	locale := flags.String("locale", "en-US", "Endpoint locale")
+	modelID := flags.String("model", "", "Custom model ID")
+	logging := flags.Bool("logging", false, "Keep the audio and transcripts sent to the endpoint")
+	wait := flags.Duration("wait", 0, "Wait up to this long for the deployment, e.g. 30m")
+	flags.Parse(args)
+
+	if *name == "" || *modelID == "" {
+		log.Fatalf("-name and -model are required")
+	}
+
+	request := speech.CustomSpeechEndpointRequest{DisplayName: *name, Locale: *locale, Model: modelReference(client, *modelID)}
+	request.Properties.LoggingEnabled = *logging
+	endpoint, err := client.CreateEndpoint(request)
+	if err != nil {
+		log.Fatalf("Error creating endpoint: %v", err)
+	}
+	fmt.Printf("Deployment started: endpoint ID: %s\n", endpoint.ID())
+
+	if *wait > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), *wait)
+		defer cancel()
+		endpoint, err = client.WaitForEndpoint(ctx, endpoint.ID(), 0)
+		if err != nil {
+			log.Fatalf("Error waiting for endpoint: %v", err)
+		}
+		fmt.Printf("✅ Endpoint %s is ready, set SPEECH_CUSTOM_ENDPOINT_ID=%s to recognize with it\n", endpoint.ID(), endpoint.ID())
+	}
+}
+
+func listEndpoints(client *speech.CustomSpeechClient) {
+	endpoints, err := client.ListEndpoints()
+	if err != nil {
+		log.Fatalf("Error listing endpoints: %v", err)
+	}
+	for _, endpoint := range endpoints {
+		fmt.Printf("ID: %s, Name: %s, Locale: %s, Status: %s, Model: %s\n", endpoint.ID(), endpoint.DisplayName, endpoint.Locale, endpoint.Status, endpoint.Model.Self)
+	}
+}
+
+func deleteEntity(client *speech.CustomSpeechClient, args []string) {
+	if len(args) != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch kind, id := args[0], args[1]; kind {
+	case "dataset":
+		err = client.DeleteDataset(id)
+	case "model":
+		err = client.DeleteModel(id)
+	case "evaluation":
+		err = client.DeleteEvaluation(id)
+	case "endpoint":
+		err = client.DeleteEndpoint(id)
+	default:
+		log.Fatalf("Unknown entity: %s", kind)
+	}
+	if err != nil {
+		log.Fatalf("Error deleting %s: %v", args[0], err)
+	}
+	fmt.Printf("%s %s deleted\n", args[0], args[1])
+}
+
+// modelReference resolves a custom model ID, falling back to a base model ID
+func modelReference(client *speech.CustomSpeechClient, modelID string) speech.EntityReference {
+	model, err := client.GetModel(modelID)
+	if err != nil {
+		model, err = client.GetBaseModel(modelID)
+	}
+	if err != nil {
+		log.Fatalf("Error getting model %s: %v", modelID, err)
+	}
+	return model.Reference()
+}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/ngothientuong/tngo-ai-svcs/internal/ai/speech"
+)
+
+/*
+customvoice runs the professional voice workflow end to end:
+project -> consent -> training set -> model -> endpoint
+Each step is recorded in a state file when it is submitted and again when it completes, so an interrupted run
+(Ctrl+C, timeout, failure) resumes where it stopped: completed steps are skipped and submitted ones are only awaited.
+A step whose resource already exists, such as after a crash between submitting it and saving the state, reuses it.
+*/
+
+// Workflow steps in order
+const (
+	stepProject     = "project"
+	stepConsent     = "consent"
+	stepTrainingSet = "trainingSet"
+	stepModel       = "model"
+	stepEndpoint    = "endpoint"
+)
+
+// Step progress recorded in the state file
+const (
+	stepSubmitted = "submitted"
+	stepCompleted = "completed"
+)
+
+// workflowState is persisted after every change so the workflow can resume
+type workflowState struct {
+	ProjectID     string            `json:"projectId"`
+	ConsentID     string            `json:"consentId"`
+	TrainingSetID string            `json:"trainingSetId"`
+	ModelID       string            `json:"modelId"`
+	EndpointID    string            `json:"endpointId"`
+	Steps         map[string]string `json:"steps"`
+	UpdatedAt     time.Time         `json:"updatedAt"`
+
+	path string
+}
+
+// workflow holds the clients and settings shared by the steps
+type workflow struct {
+	client   *speech.CustomVoiceClient
+	state    *workflowState
+	interval time.Duration
+
+	locale          string
+	voiceTalentName string
+	companyName     string
+	consentAudioURL string
+	upload          speech.TrainingSetUpload
+	voiceName       string
+	recipe          string
+	endpointKind    string
+}
+
+func main() {
+	// Load environment variables from .env file
+	err := godotenv.Load("/home/tngo/ngo/projects/tngo-ai-svcs/.env")
+	if err != nil {
+		log.Fatalf("Error loading .env file: %v", err)
+	}
+
+	// Get the endpoint, key, region, and API version from environment variables
+	endpoint := os.Getenv("SPEECH_ENDPOINT")
+	key := os.Getenv("SPEECH_KEY")
+	region := os.Getenv("SPEECH_REGION")
+	apiVersion := os.Getenv("SPEECH_TO_TEXT_API_VERSION")
+
+	// Parse command-line arguments
+	statePath := flag.String("state", "customvoice-state.json", "Workflow state file used to resume")
+	projectID := flag.String("project", "tuong-custom-voice-project", "Project ID")
+	consentID := flag.String("consent", "tuong-consent", "Consent ID")
+	trainingSetID := flag.String("training-set", "tuong-training-set", "Training set ID")
+	modelID := flag.String("model", "tuong-model", "Model ID")
+	endpointID := flag.String("endpoint", "tuong-endpoint", "Endpoint ID")
+	locale := flag.String("locale", "en-US", "Voice locale")
+	voiceTalentName := flag.String("voice-talent", "", "Voice talent name, as spoken in the consent statement")
+	companyName := flag.String("company", "", "Company name, as spoken in the consent statement")
+	consentAudioURL := flag.String("consent-audio-url", "", "Public or SAS URL of the recorded consent statement")
+	audiosURL := flag.String("audios-url", "", "Blob container SAS URL of the training recordings")
+	scriptsURL := flag.String("scripts-url", "", "Blob container SAS URL of the scripts; defaults to -audios-url")
+	prefix := flag.String("prefix", "", "Blob name prefix of the recordings and scripts")
+	voiceName := flag.String("voice-name", "TuongNeural", "Voice name used in SSML, must end with Neural")
+	recipe := flag.String("recipe", speech.ModelRecipeDefault, "Training recipe: Default, CrossLingual, MultiStyle")
+	endpointKind := flag.String("endpoint-kind", "", "Endpoint kind: HighPerformance or FastResume")
+	interval := flag.Duration("interval", speech.DefaultCustomVoicePollInterval, "Status polling interval")
+	timeout := flag.Duration("timeout", 0, "Stop waiting after this long and resume later (0 waits until done)")
+	flag.Parse()
+
+	// Validate input
+	if endpoint == "" || key == "" || apiVersion == "" {
+		log.Fatalf("Missing required credentials")
+	}
+
+	state, err := loadState(*statePath, workflowState{
+		ProjectID:     *projectID,
+		ConsentID:     *consentID,
+		TrainingSetID: *trainingSetID,
+		ModelID:       *modelID,
+		EndpointID:    *endpointID,
+	})
+	if err != nil {
+		log.Fatalf("Error loading workflow state: %v", err)
+	}
+
+	w := &workflow{
+		client:          speech.NewCustomVoiceClient(endpoint, key, region, apiVersion),
+		state:           state,
+		interval:        *interval,
+		locale:          *locale,
+		voiceTalentName: *voiceTalentName,
+		companyName:     *companyName,
+		consentAudioURL: *consentAudioURL,
+		voiceName:       *voiceName,
+		recipe:          *recipe,
+		endpointKind:    *endpointKind,
+	}
+	w.upload = speech.TrainingSetUpload{
+		Kind:   speech.TrainingSetKindAudioAndScript,
+		Audios: speech.AzureBlobContentSource{ContainerURL: *audiosURL, Prefix: *prefix},
+	}
+	scripts := *scriptsURL
+	if scripts == "" {
+		scripts = *audiosURL
+	}
+	w.upload.Scripts = &speech.AzureBlobContentSource{ContainerURL: scripts, Prefix: *prefix}
+
+	// Ctrl+C and -timeout stop waiting; the next run picks up from the state file
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	steps := []struct {
+		name   string
+		submit func() error
+		wait   func(ctx context.Context) error
+	}{
+		{stepProject, w.createProject, nil},
+		{stepConsent, w.createConsent, w.waitForConsent},
+		{stepTrainingSet, w.createTrainingSet, w.waitForTrainingSet},
+		{stepModel, w.createModel, w.waitForModel},
+		{stepEndpoint, w.createEndpoint, w.waitForEndpoint},
+	}
+	for _, step := range steps {
+		if state.Steps[step.name] == stepCompleted {
+			fmt.Printf("⏭  %s already completed\n", step.name)
+			continue
+		}
+
+		if state.Steps[step.name] != stepSubmitted {
+			fmt.Printf("🚀 Submitting %s\n", step.name)
+			if err := step.submit(); err != nil {
+				log.Fatalf("Error submitting %s: %v", step.name, err)
+			}
+			if err := state.mark(step.name, stepSubmitted); err != nil {
+				log.Fatalf("Error saving workflow state: %v", err)
+			}
+		}
+
+		if step.wait != nil {
+			fmt.Printf("⏳ Waiting for %s\n", step.name)
+			if err := step.wait(ctx); err != nil {
+				if ctx.Err() != nil {
+					fmt.Printf("⏸  Stopped while waiting for %s, run again to resume\n", step.name)
+					return
+				}
+				log.Fatalf("Error waiting for %s: %v", step.name, err)
+			}
+		}
+		if err := state.mark(step.name, stepCompleted); err != nil {
+			log.Fatalf("Error saving workflow state: %v", err)
+		}
+		fmt.Printf("✅ %s completed\n", step.name)
+	}
+
+	fmt.Printf("🎉 Voice %s is deployed on endpoint %s\n", w.voiceName, state.EndpointID)
+}
+
+func (w *workflow) createProject() error {
+	exists, err := w.client.CheckProjectExists(w.state.ProjectID)
+	if err != nil || exists {
+		return err
+	}
+	_, err = w.client.CreateProject(w.state.ProjectID, speech.ProjectKindProfessionalVoice, "Professional voice created by the customvoice workflow")
+	return err
+}
+
+func (w *workflow) createConsent() error {
+	exists, err := w.client.CheckConsentExists(w.state.ConsentID)
+	if err != nil || exists {
+		return err
+	}
+	if w.voiceTalentName == "" || w.companyName == "" || w.consentAudioURL == "" {
+		return errors.New("-voice-talent, -company and -consent-audio-url are required to create the consent")
+	}
+	_, err = w.client.CreateConsent(w.state.ConsentID, "Consent of the voice talent", w.state.ProjectID, w.voiceTalentName, w.companyName, w.consentAudioURL, w.locale)
+	return err
+}
+
+func (w *workflow) waitForConsent(ctx context.Context) error {
+	_, err := w.client.WaitForConsent(ctx, w.state.ConsentID, w.interval)
+	return err
+}
+
+func (w *workflow) createTrainingSet() error {
+	trainingSet, found, err := w.client.LookupTrainingSet(w.state.TrainingSetID)
+	if err != nil {
+		return err
+	}
+	// Data already uploaded, or still being imported, is not uploaded again
+	if found && (trainingSet.Status == speech.CustomVoiceStatusRunning || trainingSet.Properties != nil && trainingSet.Properties.UtteranceCount > 0) {
+		fmt.Printf("   Reusing training set %s\n", w.state.TrainingSetID)
+		return nil
+	}
+	if w.upload.Audios.ContainerURL == "" {
+		return errors.New("-audios-url is required to upload the training data")
+	}
+	if !found {
+		if _, err := w.client.CreateTrainingSet(w.state.TrainingSetID, w.state.ProjectID, w.locale, "Recordings uploaded by the customvoice workflow"); err != nil {
+			return err
+		}
+	}
+	operationID, err := w.client.UploadTrainingSetData(w.state.TrainingSetID, w.upload)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("   Upload operation: %s\n", operationID)
+	return nil
+}
+
+func (w *workflow) waitForTrainingSet(ctx context.Context) error {
+	_, err := w.client.WaitForTrainingSet(ctx, w.state.TrainingSetID, w.interval)
+	return err
+}
+
+func (w *workflow) createModel() error {
+	_, found, err := w.client.LookupModel(w.state.ModelID)
+	if err != nil || found {
+		return err
+	}
+	_, err = w.client.CreateModel(speech.Model{
+		ID:            w.state.ModelID,
+		VoiceName:     w.voiceName,
+		Description:   "Model trained by the customvoice workflow",
+		Recipe:        speech.ModelRecipe{Kind: w.recipe},
+		ProjectID:     w.state.ProjectID,
+		ConsentID:     w.state.ConsentID,
+		TrainingSetID: w.state.TrainingSetID,
+	})
+	return err
+}
+
+func (w *workflow) waitForModel(ctx context.Context) error {
+	model, err := w.client.WaitForModel(ctx, w.state.ModelID, w.interval)
+	if err == nil {
+		fmt.Printf("   Trained with recipe %s %s, engine %s\n", model.Recipe.Kind, model.Recipe.Version, model.EngineVersion)
+	}
+	return err
+}
+
+func (w *workflow) createEndpoint() error {
+	_, found, err := w.client.LookupEndpoint(w.state.EndpointID)
+	if err != nil || found {
+		return err
+	}
+	_, err = w.client.CreateEndpoint(w.state.EndpointID, w.state.ProjectID, w.state.ModelID, w.endpointKind, "Endpoint deployed by the customvoice workflow")
+	return err
+}
+
+func (w *workflow) waitForEndpoint(ctx context.Context) error {
+	endpoint, err := w.client.WaitForEndpoint(ctx, w.state.EndpointID, w.interval)
+	if err != nil {
+		return err
+	}
+	if endpoint.Status == speech.CustomVoiceStatusDisabled {
+		return fmt.Errorf("endpoint %s is suspended, resume it before running the workflow", w.state.EndpointID)
+	}
+	return nil
+}
+
+// loadState reads the state file, or starts a new state with the IDs from the flags when it does not exist
+func loadState(path string, initial workflowState) (*workflowState, error) {
+	state := &initial
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		// Resource IDs come from the state file so a resumed run targets what was already created
+		if err := json.Unmarshal(data, state); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %v", path, err)
+		}
+		fmt.Printf("📄 Resuming from %s (last updated %s)\n", path, state.UpdatedAt.Local().Format(time.DateTime))
+	}
+	if state.Steps == nil {
+		state.Steps = make(map[string]string)
+	}
+	state.path = path
+	return state, nil
+}
+
+// mark records the progress of a step and saves the state file
+func (s *workflowState) mark(step, progress string) error {
+	s.Steps[step] = progress
+	s.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0644)
+}
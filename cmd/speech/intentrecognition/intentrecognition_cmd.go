@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/ngothientuong/tngo-ai-svcs/internal/ai/speech"
+)
+
+// patternFlags collects repeated -intent flags
+type patternFlags []string
+
+func (p *patternFlags) String() string {
+	return strings.Join(*p, ", ")
+}
+
+func (p *patternFlags) Set(value string) error {
+	*p = append(*p, value)
+	return nil
+}
+
+func main() {
+	// Load environment variables
+	err := godotenv.Load("/home/tngo/ngo/projects/tngo-ai-svcs/.env")
+	if err != nil {
+		log.Fatalf("Error loading .env file")
+	}
+
+	// Get API credentials
+	subscriptionKey := os.Getenv("SPEECH_KEY")
+	region := os.Getenv("SPEECH_REGION")
+
+	// Recognition options from the environment, overridden by flags
+	options, err := speech.RecognizerOptionsFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid recognition options: %v", err)
+	}
+	options.RegisterFlags(flag.CommandLine)
+
+	// Parse command-line arguments
+	var intents patternFlags
+	flag.Var(&intents, "intent", "Intent pattern as id=pattern, repeatable (e.g. \"GoToFloor=(Take | Bring) me [to] floor {floor}\")")
+	filePath := flag.String("file", "", "Path to an audio file; uses the microphone when empty")
+	keywordModel := flag.String("keyword-model", "", "Path to a .table keyword model; only utterances after the wake word are matched")
+	flag.Parse()
+
+	// Validate input
+	if subscriptionKey == "" || region == "" {
+		log.Fatalf("Missing required credentials")
+	}
+	if len(intents) == 0 {
+		intents = patternFlags{
+			"GoToFloor=(Take | Bring) me [to] floor {floor} [please]",
+			"TurnOn=Turn on [the] {device}",
+			"TurnOff=Turn off [the] {device}",
+		}
+	}
+
+	input := speech.MicrophoneInput("")
+	if *filePath != "" {
+		input = speech.FileInput(*filePath)
+	}
+
+	options.KeywordModelPath = *keywordModel
+	if err := options.Validate(); err != nil {
+		log.Fatalf("Invalid recognition options: %v", err)
+	}
+	recognizer, err := speech.NewIntentRecognizer(subscriptionKey, region, input, options)
+	if err != nil {
+		log.Fatalf("Error creating intent recognizer: %v", err)
+	}
+	defer recognizer.Stop()
+
+	if err := recognizer.AddEntity(speech.IntentEntity{Name: "floor", Type: speech.EntityInteger}); err != nil {
+		log.Fatalf("Error adding entity: %v", err)
+	}
+	for _, intent := range intents {
+		id, pattern, ok := strings.Cut(intent, "=")
+		if !ok {
+			log.Fatalf("Invalid intent %q, expected id=pattern", intent)
+		}
+		if err := recognizer.AddIntent(strings.TrimSpace(id), strings.TrimSpace(pattern)); err != nil {
+			log.Fatalf("Error adding intent: %v", err)
+		}
+	}
+
+	// Stop the session on Ctrl+C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := recognizer.Start(ctx); err != nil {
+		log.Fatalf("Intent recognition failed: %v", err)
+	}
+
+	if *keywordModel != "" {
+		fmt.Println("🎤 Waiting for the keyword...")
+	} else {
+		fmt.Println("🎤 Listening for intents...")
+	}
+
+	keywords, results := recognizer.Recognizer().Keywords(), recognizer.Intents()
+	for keywords != nil || results != nil {
+		select {
+		case event, ok := <-keywords:
+			if !ok {
+				keywords = nil
+				continue
+			}
+			fmt.Printf("🔑 Keyword: %s\n", event.Keyword)
+		case event, ok := <-results:
+			if !ok {
+				results = nil
+				continue
+			}
+			if event.IntentID == "" {
+				fmt.Printf("❔ No intent: %s\n", event.Text)
+				continue
+			}
+			fmt.Printf("🎯 Intent: %s (%s)\n", event.IntentID, event.Text)
+			for name, value := range event.Entities {
+				fmt.Printf("   %s = %s\n", name, value)
+			}
+		}
+	}
+
+	if err := recognizer.Wait(); err != nil {
+		log.Fatalf("Intent recognition failed: %v", err)
+	}
+}
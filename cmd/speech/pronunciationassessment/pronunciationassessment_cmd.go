@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/ngothientuong/tngo-ai-svcs/internal/ai/speech"
+)
+
+func main() {
+	// Load environment variables
+	err := godotenv.Load("/home/tngo/ngo/projects/tngo-ai-svcs/.env")
+	if err != nil {
+		log.Fatalf("Error loading .env file")
+	}
+
+	// Get API credentials
+	subscriptionKey := os.Getenv("SPEECH_KEY")
+	region := os.Getenv("SPEECH_REGION")
+
+	// Parse command-line arguments
+	filePath := flag.String("file", "", "Path to the WAV recording")
+	reference := flag.String("reference", "", "Reference text the speaker reads (empty for unscripted assessment)")
+	language := flag.String("language", "en-US", "Recognition locale")
+	grading := flag.String("grading", string(speech.GradingSystemHundredMark), "Grading system: FivePoint, HundredMark")
+	granularity := flag.String("granularity", string(speech.GranularityPhoneme), "Granularity: Phoneme, Word, FullText")
+	prosody := flag.Bool("prosody", false, "Enable prosody assessment (en-US only)")
+	miscue := flag.Bool("miscue", true, "Flag omitted and inserted words against the reference text")
+	jsonOutput := flag.Bool("json", false, "Print the report as JSON")
+	flag.Parse()
+
+	// Validate input
+	if subscriptionKey == "" || region == "" {
+		log.Fatalf("Missing required credentials")
+	}
+	if *filePath == "" {
+		log.Fatalf("Missing required parameters. Example usage: go run pronunciationassessment_cmd.go -file ./reading.wav -reference \"Good morning\"")
+	}
+
+	config := speech.PronunciationAssessmentConfig{
+		ReferenceText: *reference,
+		GradingSystem: speech.GradingSystem(*grading),
+		Granularity:   speech.Granularity(*granularity),
+		EnableMiscue:  *miscue && *reference != "",
+		EnableProsody: *prosody,
+	}
+	report, err := speech.AssessPronunciation(subscriptionKey, region, speech.FileInput(*filePath), config, &speech.RecognizerOptions{Language: *language})
+	if err != nil {
+		log.Fatalf("Pronunciation assessment failed: %v", err)
+	}
+
+	if *jsonOutput {
+		result, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			log.Fatalf("Failed to marshal report: %v", err)
+		}
+		fmt.Printf("Result: %s\n", result)
+		return
+	}
+
+	fmt.Println("📊 Pronunciation assessment")
+	fmt.Printf("Pronunciation: %.1f\n", report.Scores.Pronunciation)
+	fmt.Printf("Accuracy:      %.1f\n", report.Scores.Accuracy)
+	fmt.Printf("Fluency:       %.1f\n", report.Scores.Fluency)
+	fmt.Printf("Completeness:  %.1f\n", report.Scores.Completeness)
+	if *prosody {
+		fmt.Printf("Prosody:       %.1f\n", report.Scores.Prosody)
+	}
+
+	for _, utterance := range report.Utterances {
+		fmt.Printf("\n🗣 %s\n", utterance.Text)
+		for _, word := range utterance.Words {
+			marker := "✅"
+			if word.ErrorType != "" && word.ErrorType != "None" {
+				marker = "❌"
+			}
+			fmt.Printf("  %s %-15s %5.1f %s\n", marker, word.Word, word.Accuracy, word.ErrorType)
+			if len(word.Phonemes) > 0 {
+				phonemes := make([]string, 0, len(word.Phonemes))
+				for _, phoneme := range word.Phonemes {
+					phonemes = append(phonemes, fmt.Sprintf("%s:%.0f", phoneme.Phoneme, phoneme.Accuracy))
+				}
+				fmt.Printf("      %s\n", strings.Join(phonemes, " "))
+			}
+		}
+	}
+}
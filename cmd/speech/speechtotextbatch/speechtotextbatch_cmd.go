@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/ngothientuong/tngo-ai-svcs/internal/ai/speech"
+)
+
+func main() {
+	// Load environment variables from .env file
+	err := godotenv.Load("/home/tngo/ngo/projects/tngo-ai-svcs/.env")
+	if err != nil {
+		log.Fatalf("Error loading .env file: %v", err)
+	}
+
+	// Get the endpoint, key, and API version from environment variables
+	endpoint := os.Getenv("SPEECH_ENDPOINT")
+	key := os.Getenv("SPEECH_KEY")
+	apiVersion := os.Getenv("SPEECH_TO_TEXT_API_VERSION")
+
+	// Parse command-line arguments
+	action := flag.String("action", "submit", "Action to perform: submit, status, download, delete")
+	id := flag.String("id", "", "Transcription ID (status, download, delete)")
+	urls := flag.String("urls", "", "Comma-separated audio content URLs (submit)")
+	container := flag.String("container", "", "Blob container URL with SAS token (submit)")
+	locale := flag.String("locale", "en-US", "Recognition locale")
+	name := flag.String("name", "Batch transcription", "Display name of the transcription")
+	diarization := flag.Bool("diarization", false, "Enable speaker diarization")
+	maxSpeakers := flag.Int("max-speakers", 0, "Maximum number of speakers when diarization is enabled")
+	wordTimestamps := flag.Bool("word-timestamps", false, "Include word-level timestamps")
+	punctuation := flag.String("punctuation", speech.PunctuationModeDictatedAndAutomatic, "Punctuation mode: None, Dictated, Automatic, DictatedAndAutomatic")
+	profanity := flag.String("profanity", speech.ProfanityFilterModeMasked, "Profanity filter mode: None, Masked, Removed, Tags")
+	wait := flag.Bool("wait", false, "Wait for the transcription to finish and download the results (submit)")
+	output := flag.String("output", "", "Write downloaded transcripts to this file instead of stdout")
+	flag.Parse()
+
+	// Validate input
+	if endpoint == "" || key == "" || apiVersion == "" {
+		log.Fatalf("Missing required credentials")
+	}
+
+	client := speech.NewBatchTranscriptionClient(endpoint, key, apiVersion)
+
+	switch *action {
+	case "submit":
+		request := speech.TranscriptionRequest{
+			DisplayName:         *name,
+			Locale:              *locale,
+			ContentContainerURL: *container,
+			Properties: speech.TranscriptionProperties{
+				WordLevelTimestampsEnabled: *wordTimestamps,
+				PunctuationMode:            *punctuation,
+				ProfanityFilterMode:        *profanity,
+			},
+		}
+		if *urls != "" {
+			request.ContentURLs = strings.Split(*urls, ",")
+		}
+		if *diarization {
+			request.Properties.Diarization = &speech.DiarizationProperties{Enabled: true, MaxSpeakers: *maxSpeakers}
+		}
+
+		transcription, err := client.CreateTranscription(request)
+		if err != nil {
+			log.Fatalf("Error creating transcription: %v", err)
+		}
+		fmt.Printf("Transcription submitted: ID: %s, Status: %s\n", transcription.ID(), transcription.Status)
+
+		if !*wait {
+			return
+		}
+		transcription, err = client.WaitForTranscription(context.Background(), transcription.ID(), 10*time.Second)
+		if err != nil {
+			log.Fatalf("Error waiting for transcription: %v", err)
+		}
+		download(client, transcription.ID(), *output)
+	case "status":
+		if *id == "" {
+			log.Fatalf("Missing required parameter: -id")
+		}
+		transcription, err := client.GetTranscription(*id)
+		if err != nil {
+			log.Fatalf("Error getting transcription: %v", err)
+		}
+		fmt.Printf("ID: %s, Name: %s, Status: %s, Last action: %s\n", transcription.ID(), transcription.DisplayName, transcription.Status, transcription.LastActionDateTime)
+		if transcription.Properties.Error != nil {
+			fmt.Printf("Error: %s - %s\n", transcription.Properties.Error.Code, transcription.Properties.Error.Message)
+		}
+	case "download":
+		if *id == "" {
+			log.Fatalf("Missing required parameter: -id")
+		}
+		download(client, *id, *output)
+	case "delete":
+		if *id == "" {
+			log.Fatalf("Missing required parameter: -id")
+		}
+		if err := client.DeleteTranscription(*id); err != nil {
+			log.Fatalf("Error deleting transcription: %v", err)
+		}
+		fmt.Printf("Transcription %s deleted\n", *id)
+	default:
+		log.Fatalf("Unknown action: %s", *action)
+	}
+}
+
+// download fetches the result files of a transcription and prints them as typed transcripts
+func download(client *speech.BatchTranscriptionClient, id, output string) {
+	results, err := client.GetTranscriptionResults(id)
+	if err != nil {
+		log.Fatalf("Error downloading transcription results: %v", err)
+	}
+
+	transcripts := make(map[string]*speech.Transcript, len(results))
+	for _, result := range results {
+		transcripts[result.Source] = result.Transcript()
+	}
+
+	data, err := json.MarshalIndent(transcripts, "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to marshal transcripts: %v", err)
+	}
+
+	if output == "" {
+		fmt.Printf("Result: %s\n", data)
+		return
+	}
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		log.Fatalf("Error writing transcripts: %v", err)
+	}
+	fmt.Printf("Transcripts written to %s\n", output)
+}
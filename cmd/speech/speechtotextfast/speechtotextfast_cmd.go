@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/ngothientuong/tngo-ai-svcs/internal/ai/speech"
+)
+
+func main() {
+	// Load environment variables from .env file
+	err := godotenv.Load("/home/tngo/ngo/projects/tngo-ai-svcs/.env")
+	if err != nil {
+		log.Fatalf("Error loading .env file: %v", err)
+	}
+
+	// Get the endpoint, key, region, and API version from environment variables
+	endpoint := os.Getenv("SPEECH_ENDPOINT")
+	key := os.Getenv("SPEECH_KEY")
+	region := os.Getenv("SPEECH_REGION")
+	apiVersion := os.Getenv("SPEECH_TO_TEXT_API_VERSION")
+
+	// Parse command-line arguments
+	filePath := flag.String("file", "", "Path to the audio file")
+	locales := flag.String("locales", "en-US", "Comma-separated candidate locales")
+	diarization := flag.Bool("diarization", false, "Enable speaker diarization")
+	maxSpeakers := flag.Int("max-speakers", 2, "Maximum number of speakers when diarization is enabled")
+	channels := flag.String("channels", "", "Comma-separated stereo channels to transcribe separately, such as 0,1")
+	profanity := flag.String("profanity", speech.ProfanityFilterModeMasked, "Profanity filter mode: None, Masked, Removed, Tags")
+	flag.Parse()
+
+	// Validate input; SPEECH_REGION alone selects the regional endpoint
+	if (endpoint == "" && region == "") || key == "" || apiVersion == "" {
+		log.Fatalf("Missing required credentials")
+	}
+	if *filePath == "" {
+		log.Fatalf("Missing required parameters. Example usage: go run speechtotextfast_cmd.go -file ./sample.wav -diarization")
+	}
+
+	definition := speech.FastTranscriptionDefinition{
+		Locales:             strings.Split(*locales, ","),
+		ProfanityFilterMode: *profanity,
+	}
+	if *diarization {
+		definition.Diarization = &speech.FastTranscriptionDiarization{Enabled: true, MaxSpeakers: *maxSpeakers}
+	}
+	if *channels != "" {
+		for _, channel := range strings.Split(*channels, ",") {
+			value, err := strconv.Atoi(strings.TrimSpace(channel))
+			if err != nil {
+				log.Fatalf("Invalid channel %q: %v", channel, err)
+			}
+			definition.Channels = append(definition.Channels, value)
+		}
+	}
+
+	client := speech.NewFastTranscriptionClient(endpoint, key, region, apiVersion)
+	result, err := client.TranscribeFile(*filePath, definition)
+	if err != nil {
+		log.Fatalf("Fast transcription failed: %v", err)
+	}
+
+	fmt.Printf("Text: %s\n", result.Text())
+	for _, phrase := range result.Phrases {
+		fmt.Printf("[%d ms] channel %d, speaker %d: %s\n", phrase.OffsetMilliseconds, phrase.Channel, phrase.Speaker, phrase.Text)
+	}
+
+	data, err := json.MarshalIndent(result.Transcript(), "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to marshal transcript: %v", err)
+	}
+	fmt.Printf("Result: %s\n", data)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+	"github.com/ngothientuong/tngo-ai-svcs/internal/ai/speech"
+	"github.com/ngothientuong/tngo-ai-svcs/internal/audio/audioutil"
+)
+
+func main() {
+	// Load environment variables
+	err := godotenv.Load("/home/tngo/ngo/projects/tngo-ai-svcs/.env")
+	if err != nil {
+		log.Fatalf("Error loading .env file")
+	}
+
+	// Get API credentials
+	subscriptionKey := os.Getenv("SPEECH_KEY")
+	region := os.Getenv("SPEECH_REGION")
+
+	// Recognition options from the environment, overridden by flags
+	options, err := speech.RecognizerOptionsFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid recognition options: %v", err)
+	}
+	options.RegisterFlags(flag.CommandLine)
+
+	// Parse command-line arguments
+	filePath := flag.String("file", "", "Path to the audio file (wav, pcm, mp3, ogg, opus, flac, alaw, mulaw)")
+	format := flag.String("format", "", "Audio format override when reading from stdin or an unknown extension (wav, pcm, mp3, ogg-opus, flac, alaw, mulaw, any)")
+	vad := flag.Bool("vad", false, "Skip silence with voice activity detection (wav and pcm input only)")
+	storePath := flag.String("store", os.Getenv("SPEECH_TRANSCRIPT_STORE"), "Save the transcript to this transcript store file (optional)")
+	flag.Parse()
+
+	// Validate input
+	if subscriptionKey == "" || region == "" {
+		log.Fatalf("Missing required credentials")
+	}
+	if err := options.Validate(); err != nil {
+		log.Fatalf("Invalid recognition options: %v", err)
+	}
+
+	if *vad {
+		options.VoiceActivity = &audioutil.VADOptions{}
+	}
+
+	var transcript *speech.Transcript
+	switch {
+	case *filePath != "" && *format == "":
+		transcript, err = speech.TranscribeFile(subscriptionKey, region, *filePath, options)
+	case *filePath != "":
+		file, openErr := os.Open(*filePath)
+		if openErr != nil {
+			log.Fatalf("Error opening audio file: %v", openErr)
+		}
+		defer file.Close()
+		transcript, err = speech.TranscribeReader(subscriptionKey, region, file, speech.AudioInputFormat(*format), options)
+	case *format != "":
+		transcript, err = speech.TranscribeReader(subscriptionKey, region, os.Stdin, speech.AudioInputFormat(*format), options)
+	default:
+		log.Fatalf("Missing required parameters. Example usage: go run speechtotextfile_cmd.go -file ./sample.wav or cat sample.mp3 | go run speechtotextfile_cmd.go -format mp3")
+	}
+	if err != nil {
+		log.Fatalf("Speech recognition failed: %v", err)
+	}
+
+	if *storePath != "" {
+		store, err := speech.OpenTranscriptStore(*storePath)
+		if err != nil {
+			log.Fatalf("Error opening transcript store: %v", err)
+		}
+		defer store.Close()
+
+		source := *filePath
+		if source == "" {
+			source = "stdin"
+		}
+		sessionID, err := store.SaveTranscript(source, options.Language, transcript)
+		if err != nil {
+			log.Fatalf("Error saving transcript: %v", err)
+		}
+		fmt.Printf("💾 Saved transcript as session %s in %s\n", sessionID, store.Path())
+	}
+
+	result, err := json.MarshalIndent(transcript, "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to marshal transcript: %v", err)
+	}
+	fmt.Printf("Result: %s\n", result)
+}
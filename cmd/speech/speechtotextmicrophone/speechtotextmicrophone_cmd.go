@@ -1,8 +1,12 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"log"
 	"os"
+	"os/signal"
 
 	"github.com/joho/godotenv"
 	"github.com/ngothientuong/tngo-ai-svcs/internal/ai/speech"
@@ -19,14 +23,68 @@ func main() {
 	subscriptionKey := os.Getenv("SPEECH_KEY")
 	region := os.Getenv("SPEECH_REGION")
 
+	// Recognition options from the environment, overridden by flags
+	options, err := speech.RecognizerOptionsFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid recognition options: %v", err)
+	}
+	options.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
 	// Validate input
 	if subscriptionKey == "" || region == "" {
 		log.Fatalf("Missing required credentials")
 	}
+	if err := options.Validate(); err != nil {
+		log.Fatalf("Invalid recognition options: %v", err)
+	}
+
+	// Stop the session on Ctrl+C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
 	// Start transcription from microphone
-	err = speech.TranscribeFromMicrophone(subscriptionKey, region)
+	recognizer, err := speech.TranscribeFromMicrophone(ctx, subscriptionKey, region, options)
 	if err != nil {
 		log.Fatalf("Speech recognition failed: %v", err)
 	}
+
+	fmt.Println("🎤 Transcribing from microphone...")
+	printEvents(recognizer)
+
+	if err := recognizer.Wait(); err != nil {
+		log.Fatalf("Speech recognition failed: %v", err)
+	}
+}
+
+// printEvents prints recognition events until the session ends
+func printEvents(recognizer *speech.Recognizer) {
+	partial, final, canceled := recognizer.Partial(), recognizer.Final(), recognizer.Canceled()
+	for partial != nil || final != nil || canceled != nil {
+		select {
+		case event, ok := <-partial:
+			if !ok {
+				partial = nil
+				continue
+			}
+			fmt.Printf("🔵 Partial: %s\n", event.Segment.Text)
+		case event, ok := <-final:
+			if !ok {
+				final = nil
+				continue
+			}
+			if event.Segment.Language != "" {
+				fmt.Printf("✅ Final [%s]: %s\n", event.Segment.Language, event.Segment.Text)
+				continue
+			}
+			fmt.Printf("✅ Final: %s\n", event.Segment.Text)
+		case event, ok := <-canceled:
+			if !ok {
+				canceled = nil
+				continue
+			}
+			fmt.Printf("❌ Canceled: %s %s\n", event.Reason, event.ErrorDetails)
+		}
+	}
+	fmt.Println("🔴 Session stopped")
 }
@@ -1,12 +1,16 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
 	"log"
 	"os"
+	"os/signal"
 
 	"github.com/joho/godotenv"
 	"github.com/ngothientuong/tngo-ai-svcs/internal/ai/speech"
+	"github.com/ngothientuong/tngo-ai-svcs/internal/audio/audioutil"
 )
 
 func main() {
@@ -20,19 +24,96 @@ func main() {
 	subscriptionKey := os.Getenv("SPEECH_KEY")
 	region := os.Getenv("SPEECH_REGION")
 
+	// Recognition options from the environment, overridden by flags
+	options, err := speech.RecognizerOptionsFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid recognition options: %v", err)
+	}
+	options.RegisterFlags(flag.CommandLine)
+
 	// Parse command-line arguments
 	streamURL := flag.String("url", "", "Live stream video URL (YouTube, RTSP, HLS)")
-	format := flag.String("format", "mp4", "Video format (mp4, mkv, youtube, rtsp, hls)")
+	format := flag.String("format", "mp4", "Stream format (mp4, mkv, youtube, rtsp, hls, or wav for PCM WAV over HTTP without FFmpeg)")
+	vad := flag.Bool("vad", false, "Only send speech to the service, skipping silence with voice activity detection")
+	storePath := flag.String("store", os.Getenv("SPEECH_TRANSCRIPT_STORE"), "Save final results to this transcript store file (optional)")
 	flag.Parse()
 
 	// Validate input
 	if subscriptionKey == "" || region == "" || *streamURL == "" {
 		log.Fatalf("Missing required credentials or stream URL")
 	}
+	if err := options.Validate(); err != nil {
+		log.Fatalf("Invalid recognition options: %v", err)
+	}
+
+	// Stop the session on Ctrl+C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if *vad {
+		options.VoiceActivity = &audioutil.VADOptions{}
+	}
 
 	// Start transcription from live video stream
-	err = speech.TranscribeFromLiveStream(subscriptionKey, region, *streamURL, *format)
+	recognizer, err := speech.TranscribeFromLiveStream(ctx, subscriptionKey, region, *streamURL, *format, options)
 	if err != nil {
 		log.Fatalf("Speech recognition failed: %v", err)
 	}
+
+	fmt.Println("🎤 Transcribing live stream...")
+	final := recognizer.Final()
+	if *storePath != "" {
+		store, err := speech.OpenTranscriptStore(*storePath)
+		if err != nil {
+			log.Fatalf("Error opening transcript store: %v", err)
+		}
+		defer store.Close()
+
+		var sessionID string
+		sessionID, final, err = store.Record(recognizer, *streamURL, options.Language)
+		if err != nil {
+			log.Fatalf("Error recording transcript: %v", err)
+		}
+		fmt.Printf("💾 Saving transcript as session %s in %s\n", sessionID, store.Path())
+	}
+	printEvents(recognizer, final)
+
+	if stats, ok := recognizer.VoiceActivityStats(); ok {
+		fmt.Printf("🔇 Voice activity: %s\n", stats)
+	}
+	if err := recognizer.Wait(); err != nil {
+		log.Fatalf("Speech recognition failed: %v", err)
+	}
+}
+
+// printEvents prints recognition events until the session ends; final is the recognizer's or the store's channel
+func printEvents(recognizer *speech.Recognizer, final <-chan speech.RecognitionEvent) {
+	partial, canceled := recognizer.Partial(), recognizer.Canceled()
+	for partial != nil || final != nil || canceled != nil {
+		select {
+		case event, ok := <-partial:
+			if !ok {
+				partial = nil
+				continue
+			}
+			fmt.Printf("🔵 Partial: %s\n", event.Segment.Text)
+		case event, ok := <-final:
+			if !ok {
+				final = nil
+				continue
+			}
+			if event.Segment.Language != "" {
+				fmt.Printf("✅ Final [%s]: %s\n", event.Segment.Language, event.Segment.Text)
+				continue
+			}
+			fmt.Printf("✅ Final: %s\n", event.Segment.Text)
+		case event, ok := <-canceled:
+			if !ok {
+				canceled = nil
+				continue
+			}
+			fmt.Printf("❌ Canceled: %s %s\n", event.Reason, event.ErrorDetails)
+		}
+	}
+	fmt.Println("🔴 Session stopped")
 }
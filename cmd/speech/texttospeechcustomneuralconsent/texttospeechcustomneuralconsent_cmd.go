@@ -22,8 +22,8 @@ func main() {
     region := os.Getenv("SPEECH_REGION")
     apiVersion := os.Getenv("SPEECH_TO_TEXT_API_VERSION")
 
-    // Create a new TextToSpeechCustomConsentClient
-    client := speech.NewTextToSpeechCustomConsentClient(endpoint, key, region, apiVersion)
+    // Create a new CustomVoiceClient
+    client := speech.NewCustomVoiceClient(endpoint, key, region, apiVersion)
 
     // Define the consent details
     consentID := "jessica-consent"
@@ -49,7 +49,7 @@ func main() {
         fmt.Printf("Consent created: %+v\n", consent)
     } else {
         // List consents if it exists
-        consents, err := client.ListConsents()
+        consents, err := client.ListConsents(projectID)
         if err != nil {
             log.Fatalf("Error listing consents: %v", err)
         }
@@ -22,12 +22,12 @@ func main() {
 	region := os.Getenv("SPEECH_REGION")
 	apiVersion := os.Getenv("SPEECH_TO_TEXT_API_VERSION")
 
-	// Create a new TextToSpeechCustomNeuralClient
-	client := speech.NewTextToSpeechCustomNeuralClient(endpoint, key, region, apiVersion)
+	// Create a new CustomVoiceClient
+	client := speech.NewCustomVoiceClient(endpoint, key, region, apiVersion)
 
 	// Define the project details
 	projectID := "tuong-custom-voice-project"
-	kind := "ProfessionalVoice"
+	kind := speech.ProjectKindProfessionalVoice
 	description := "This is a custom voice project for creating a professional voice."
 
 	// Check if the project exists
@@ -54,11 +54,11 @@ func main() {
 			fmt.Printf("ID: %s, Kind: %s, Description: %s\n", project.ID, project.Kind, project.Description)
 		}
 
-		// Get the project ID if it exists
-		projectID, err := client.GetProjectID(kind)
+		// Get the project if it exists
+		project, err := client.GetProject(projectID)
 		if err != nil {
-			log.Fatalf("Error getting project ID: %v", err)
+			log.Fatalf("Error getting project: %v", err)
 		}
-		fmt.Printf("Project already exists with ID: %s\n", projectID)
+		fmt.Printf("Project already exists: %+v\n", project)
 	}
 }
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/ngothientuong/tngo-ai-svcs/internal/ai/speech"
+)
+
+func main() {
+	// Load environment variables from .env file
+	err := godotenv.Load("/home/tngo/ngo/projects/tngo-ai-svcs/.env")
+	if err != nil {
+		log.Fatalf("Error loading .env file: %v", err)
+	}
+
+	// Get the endpoint, key, region, and API version from environment variables
+	endpoint := os.Getenv("SPEECH_ENDPOINT")
+	key := os.Getenv("SPEECH_KEY")
+	region := os.Getenv("SPEECH_REGION")
+	apiVersion := os.Getenv("SPEECH_TO_TEXT_API_VERSION")
+
+	// Parse command-line arguments
+	dir := flag.String("dir", "", "Validate the recordings and scripts in this local directory before uploading")
+	projectID := flag.String("project", "tuong-custom-voice-project", "Custom voice project ID")
+	trainingSetID := flag.String("id", "", "Training set ID to create, upload to or delete")
+	locale := flag.String("locale", "en-US", "Training set locale")
+	description := flag.String("description", "", "Training set description")
+	kind := flag.String("kind", speech.TrainingSetKindAudioAndScript, "Data kind: AudioAndScript, LongAudio, AudioOnly")
+	audiosURL := flag.String("audios-url", "", "Blob container SAS URL of the recordings")
+	scriptsURL := flag.String("scripts-url", "", "Blob container SAS URL of the scripts; defaults to -audios-url")
+	prefix := flag.String("prefix", "", "Blob name prefix of the recordings and scripts")
+	wait := flag.Duration("wait", 0, "Wait up to this long for the upload to be processed, e.g. 30m")
+	deleteSet := flag.Bool("delete", false, "Delete the training set given by -id")
+	flag.Parse()
+
+	// Validate locally first, nothing is sent to the service
+	if *dir != "" {
+		report, err := speech.ValidateTrainingSetDir(*dir, nil)
+		if err != nil {
+			log.Fatalf("Error validating training set: %v", err)
+		}
+		for _, issue := range report.Issues {
+			fmt.Println(issue)
+		}
+		fmt.Printf("Utterances: %d, total %s (min %s, max %s, mean %s), speech %s, sample rates %v\n",
+			report.Utterances, report.TotalDuration.Round(time.Second), report.MinDuration.Round(time.Millisecond),
+			report.MaxDuration.Round(time.Millisecond), report.MeanDuration.Round(time.Millisecond),
+			report.SpeechDuration.Round(time.Second), report.SampleRates)
+		if !report.Valid() {
+			log.Fatalf("Training set has %d error(s)", len(report.Errors()))
+		}
+		fmt.Println("✅ Training set is ready to upload")
+		if *trainingSetID == "" {
+			return
+		}
+	}
+
+	// Validate input
+	if endpoint == "" || key == "" || apiVersion == "" {
+		log.Fatalf("Missing required credentials")
+	}
+
+	// Create a new CustomVoiceClient
+	client := speech.NewCustomVoiceClient(endpoint, key, region, apiVersion)
+
+	switch {
+	case *trainingSetID == "":
+		// List the training sets of the project
+		trainingSets, err := client.ListTrainingSets(*projectID)
+		if err != nil {
+			log.Fatalf("Error listing training sets: %v", err)
+		}
+		fmt.Printf("Training sets of %s:\n", *projectID)
+		for _, trainingSet := range trainingSets {
+			fmt.Printf("ID: %s, Locale: %s, Status: %s, Description: %s\n", trainingSet.ID, trainingSet.Locale, trainingSet.Status, trainingSet.Description)
+		}
+	case *deleteSet:
+		if err := client.DeleteTrainingSet(*trainingSetID); err != nil {
+			log.Fatalf("Error deleting training set: %v", err)
+		}
+		fmt.Printf("Training set %s deleted\n", *trainingSetID)
+	default:
+		trainingSet, err := client.CreateTrainingSet(*trainingSetID, *projectID, *locale, *description)
+		if err != nil {
+			log.Fatalf("Error creating training set: %v", err)
+		}
+		fmt.Printf("Training set created: %+v\n", trainingSet)
+
+		if *audiosURL == "" {
+			return
+		}
+
+		upload := speech.TrainingSetUpload{
+			Kind:   *kind,
+			Audios: speech.AzureBlobContentSource{ContainerURL: *audiosURL, Prefix: *prefix},
+		}
+		if *kind != speech.TrainingSetKindAudioOnly {
+			container := *scriptsURL
+			if container == "" {
+				container = *audiosURL
+			}
+			upload.Scripts = &speech.AzureBlobContentSource{ContainerURL: container, Prefix: *prefix}
+		}
+		operationID, err := client.UploadTrainingSetData(*trainingSetID, upload)
+		if err != nil {
+			log.Fatalf("Error uploading training set data: %v", err)
+		}
+		fmt.Printf("Upload started, operation ID: %s\n", operationID)
+
+		if *wait > 0 {
+			ctx, cancel := context.WithTimeout(context.Background(), *wait)
+			defer cancel()
+			trainingSet, err := client.WaitForTrainingSet(ctx, *trainingSetID, 30*time.Second)
+			if err != nil {
+				log.Fatalf("Error waiting for training set: %v", err)
+			}
+			fmt.Printf("✅ Training set %s is ready with %d utterance(s)\n", trainingSet.ID, utteranceCount(trainingSet))
+		}
+	}
+}
+
+// utteranceCount returns the imported utterances, which the service reports once processing succeeds
+func utteranceCount(trainingSet *speech.TrainingSet) int {
+	if trainingSet.Properties == nil {
+		return 0
+	}
+	return trainingSet.Properties.UtteranceCount
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/ngothientuong/tngo-ai-svcs/internal/ai/speech"
+)
+
+func main() {
+	// Load environment variables from .env file
+	err := godotenv.Load("/home/tngo/ngo/projects/tngo-ai-svcs/.env")
+	if err != nil {
+		log.Fatalf("Error loading .env file: %v", err)
+	}
+
+	// Get the endpoint, key, region, and API version from environment variables
+	endpoint := os.Getenv("SPEECH_ENDPOINT")
+	key := os.Getenv("SPEECH_KEY")
+	region := os.Getenv("SPEECH_REGION")
+	apiVersion := os.Getenv("SPEECH_TO_TEXT_API_VERSION")
+
+	// Parse command-line arguments
+	projectID := flag.String("project", "tuong-personal-voice-project", "Custom voice project ID")
+	personalVoiceID := flag.String("id", "", "Personal voice ID to create, delete or speak with")
+	consentID := flag.String("consent", "", "Consent ID of the speaker")
+	description := flag.String("description", "", "Personal voice description")
+	files := flag.String("files", "", "Comma-separated local audio samples of the speaker")
+	audiosURL := flag.String("audios-url", "", "Blob container SAS URL of the audio samples, instead of -files")
+	prefix := flag.String("prefix", "", "Blob name prefix of the audio samples")
+	wait := flag.Duration("wait", 0, "Wait up to this long for the personal voice to be ready, e.g. 5m")
+	deleteVoice := flag.Bool("delete", false, "Delete the personal voice given by -id")
+	baseModels := flag.Bool("base-models", false, "List the base models that support personal voices")
+	text := flag.String("text", "", "Synthesize this text with the personal voice given by -id")
+	baseModel := flag.String("base-model", "DragonLatestNeural", "Base model used to synthesize")
+	locale := flag.String("locale", "en-US", "Locale of the text")
+	format := flag.String("format", "riff-24khz-16bit-mono-pcm", "Synthesis output format")
+	output := flag.String("output", "personalvoice.wav", "Synthesized audio file")
+	flag.Parse()
+
+	// Validate input
+	if endpoint == "" || key == "" || apiVersion == "" {
+		log.Fatalf("Missing required credentials")
+	}
+
+	// Create a new CustomVoiceClient
+	client := speech.NewCustomVoiceClient(endpoint, key, region, apiVersion)
+
+	switch {
+	case *baseModels:
+		models, err := client.ListBaseModels(speech.BaseModelCapabilityPersonalVoice)
+		if err != nil {
+			log.Fatalf("Error listing base models: %v", err)
+		}
+		fmt.Println("Base models supporting personal voices:")
+		for _, model := range models {
+			fmt.Printf("Name: %s, Released: %s, Capabilities: %s\n", model.Name, model.ReleaseDateTime, strings.Join(model.Capabilities, ", "))
+		}
+	case *personalVoiceID == "":
+		// List the personal voices of the project
+		personalVoices, err := client.ListPersonalVoices(*projectID)
+		if err != nil {
+			log.Fatalf("Error listing personal voices: %v", err)
+		}
+		fmt.Printf("Personal voices of %s:\n", *projectID)
+		for _, personalVoice := range personalVoices {
+			fmt.Printf("ID: %s, Consent: %s, Status: %s, Speaker profile: %s\n", personalVoice.ID, personalVoice.ConsentID, personalVoice.Status, personalVoice.SpeakerProfileID)
+		}
+	case *deleteVoice:
+		if err := client.DeletePersonalVoice(*personalVoiceID); err != nil {
+			log.Fatalf("Error deleting personal voice: %v", err)
+		}
+		fmt.Printf("Personal voice %s deleted\n", *personalVoiceID)
+	case *text != "":
+		personalVoice, err := client.GetPersonalVoice(*personalVoiceID)
+		if err != nil {
+			log.Fatalf("Error getting personal voice: %v", err)
+		}
+		tts := speech.NewTextToSpeechClient(endpoint, key, region)
+		audioData, err := tts.SynthesizePersonalVoice(*text, *baseModel, personalVoice.SpeakerProfileID, *locale, *format)
+		if err != nil {
+			log.Fatalf("Error synthesizing speech: %v", err)
+		}
+		if err := os.WriteFile(*output, audioData, 0644); err != nil {
+			log.Fatalf("Error saving audio file: %v", err)
+		}
+		fmt.Printf("Audio saved to %s\n", *output)
+	default:
+		if *consentID == "" {
+			log.Fatalf("-consent is required to create a personal voice")
+		}
+		var personalVoice *speech.PersonalVoice
+		var err error
+		if *files != "" {
+			personalVoice, err = client.CreatePersonalVoiceFromFiles(*personalVoiceID, *projectID, *consentID, *description, strings.Split(*files, ","))
+		} else if *audiosURL != "" {
+			audios := speech.AzureBlobContentSource{ContainerURL: *audiosURL, Prefix: *prefix}
+			personalVoice, err = client.CreatePersonalVoice(*personalVoiceID, *projectID, *consentID, *description, audios)
+		} else {
+			log.Fatalf("-files or -audios-url is required to create a personal voice")
+		}
+		if err != nil {
+			log.Fatalf("Error creating personal voice: %v", err)
+		}
+		fmt.Printf("Personal voice created: %+v\n", personalVoice)
+
+		if *wait > 0 {
+			ctx, cancel := context.WithTimeout(context.Background(), *wait)
+			defer cancel()
+			personalVoice, err = client.WaitForPersonalVoice(ctx, *personalVoiceID, 10*time.Second)
+			if err != nil {
+				log.Fatalf("Error waiting for personal voice: %v", err)
+			}
+			fmt.Printf("✅ Personal voice %s is ready, speaker profile ID: %s\n", personalVoice.ID, personalVoice.SpeakerProfileID)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/ngothientuong/tngo-ai-svcs/internal/ai/speech"
+)
+
+const usage = `Usage: transcripts [-store path] <command> [arguments]
+
+Commands:
+  list                                      List stored sessions, most recent first
+  search [-limit n] <words...>              Find segments containing every word
+  show <session-id>                         Print a session with timestamps
+  export [-format srt|vtt|json|txt] [-output file] <session-id>
+                                            Export a session as captions, JSON or text
+
+Session IDs may be shortened to any unique prefix.`
+
+func main() {
+	// The store path can come from .env, which is optional for this local command
+	godotenv.Load("/home/tngo/ngo/projects/tngo-ai-svcs/.env")
+
+	defaultStore := os.Getenv("SPEECH_TRANSCRIPT_STORE")
+	if defaultStore == "" {
+		defaultStore = "transcripts.jsonl"
+	}
+
+	// Parse command-line arguments
+	storePath := flag.String("store", defaultStore, "Transcript store file (SPEECH_TRANSCRIPT_STORE)")
+	flag.Usage = func() { fmt.Fprintln(os.Stderr, usage) }
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if _, err := os.Stat(*storePath); err != nil {
+		log.Fatalf("Transcript store not found: %v", err)
+	}
+	store, err := speech.OpenTranscriptStore(*storePath)
+	if err != nil {
+		log.Fatalf("Error opening transcript store: %v", err)
+	}
+	defer store.Close()
+
+	command, args := flag.Arg(0), flag.Args()[1:]
+	switch command {
+	case "list":
+		listSessions(store)
+	case "search":
+		searchSegments(store, args)
+	case "show":
+		showSession(store, args)
+	case "export":
+		exportSession(store, args)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if lines := store.CorruptLines(); len(lines) > 0 {
+		fmt.Fprintf(os.Stderr, "⚠️ Skipped %d corrupt line(s) of %s: %v\n", len(lines), *storePath, lines)
+	}
+}
+
+// listSessions prints one line per stored session
+func listSessions(store *speech.TranscriptStore) {
+	sessions, err := store.Sessions()
+	if err != nil {
+		log.Fatalf("Error listing sessions: %v", err)
+	}
+	if len(sessions) == 0 {
+		fmt.Println("No sessions stored")
+		return
+	}
+
+	for _, session := range sessions {
+		status := "running or interrupted"
+		if session.EndedAt != nil {
+			status = "ended " + session.EndedAt.Local().Format(time.DateTime)
+		}
+		fmt.Printf("%s  %s  %-5s  %3d segment(s)  %8s  %s  (%s)\n",
+			shortID(session.ID), session.StartedAt.Local().Format(time.DateTime), session.Language,
+			session.SegmentCount, session.Duration.Round(time.Second), session.Source, status)
+	}
+}
+
+// searchSegments prints the segments matching the query with their session and position
+func searchSegments(store *speech.TranscriptStore, args []string) {
+	flags := flag.NewFlagSet("search", flag.ExitOnError)
+	limit := flags.Int("limit", 50, "Maximum number of matches, 0 for all")
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		log.Fatalf("Missing search words. Example usage: transcripts search quarterly revenue")
+	}
+
+	matches, err := store.Search(strings.Join(flags.Args(), " "), *limit)
+	if err != nil {
+		log.Fatalf("Error searching transcripts: %v", err)
+	}
+	for _, match := range matches {
+		fmt.Printf("%s  %s  [%s]  %s\n", shortID(match.Session.ID), match.Session.StartedAt.Local().Format(time.DateTime), match.Segment.Offset.Round(time.Second), match.Segment.Text)
+	}
+	fmt.Printf("🔍 %d match(es)\n", len(matches))
+}
+
+// showSession prints a session header and its segments
+func showSession(store *speech.TranscriptStore, args []string) {
+	if len(args) != 1 {
+		log.Fatalf("Missing session ID. Example usage: transcripts show 3f2a9c1e")
+	}
+	transcript, err := store.Session(args[0])
+	if err != nil {
+		log.Fatalf("Error reading session: %v", err)
+	}
+
+	fmt.Printf("Session:  %s\nSource:   %s\nLanguage: %s\nStarted:  %s\nDuration: %s\n\n",
+		transcript.ID, transcript.Source, transcript.Language, transcript.StartedAt.Local().Format(time.DateTime), transcript.Duration.Round(time.Second))
+	if err := transcript.WriteText(os.Stdout); err != nil {
+		log.Fatalf("Error writing transcript: %v", err)
+	}
+}
+
+// exportSession writes a session in the requested format
+func exportSession(store *speech.TranscriptStore, args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	format := flags.String("format", "srt", "Export format: srt, vtt, json, txt")
+	output := flags.String("output", "", "Output file; writes to stdout when empty")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		log.Fatalf("Missing session ID. Example usage: transcripts export -format vtt -output talk.vtt 3f2a9c1e")
+	}
+
+	transcript, err := store.Session(flags.Arg(0))
+	if err != nil {
+		log.Fatalf("Error reading session: %v", err)
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			log.Fatalf("Error creating output file: %v", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if err := transcript.Export(out, *format, nil); err != nil {
+		log.Fatalf("Error exporting transcript: %v", err)
+	}
+}
+
+// shortID abbreviates a session ID for listings; IDs shorter than the prefix are printed whole
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
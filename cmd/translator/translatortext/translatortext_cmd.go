@@ -1,11 +1,11 @@
 package main
 
 import (
-	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/joho/godotenv"
 	"github.com/ngothientuong/tngo-ai-svcs/internal/ai/translator"
@@ -51,37 +51,148 @@ func main() {
 	action := flag.String("action", "", "Action to perform (languages, translate, transliterate, detect, dictionary-lookup, dictionary-examples)")
 	flag.Var(&texts, "text", "Text to process (can be specified multiple times)")
 	flag.Var(&translations, "translation", "Translation to process (can be specified multiple times)")
-	flag.Var(&from, "from", "Source language or script (can be specified multiple times)")
+	flag.Var(&from, "from", "Source language, or script for transliterate action (can be specified multiple times)")
 	flag.Var(&to, "to", "Target language or script (can be specified multiple times)")
 	language := flag.String("language", "", "Language for transliterate action")
 	scope := flag.String("scope", "translation,transliteration,dictionary", "Scope for GetLanguages action")
+	raw := flag.Bool("raw", false, "Print the service JSON response instead of a summary")
+
+	// Translate options
+	textType := flag.String("text-type", "", "Input text type for translate action: plain, html")
+	category := flag.String("category", "", "Custom Translator category ID for translate action")
+	noFallback := flag.Bool("no-fallback", false, "Fail instead of using the general model when -category has no model for a language pair")
+	profanityAction := flag.String("profanity-action", "", "Profanity handling for translate action: NoAction, Marked, Deleted")
+	profanityMarker := flag.String("profanity-marker", "", "Marker with -profanity-action Marked: Asterisk, Tag")
+	includeAlignment := flag.Bool("include-alignment", false, "Include the alignment between source and translated text")
+	includeSentenceLength := flag.Bool("include-sentence-length", false, "Include sentence lengths of source and translated text")
+	suggestedFrom := flag.String("suggested-from", "", "Fallback source language when detection fails")
+	fromScript := flag.String("from-script", "", "Script of the input text for translate action")
+	toScript := flag.String("to-script", "", "Script of the translated text for translate action")
 	flag.Parse()
 
 	// Perform the requested action
-	var result []byte
 	switch *action {
 	case "languages":
-		result, err = client.GetLanguages(*scope)
+		if *raw {
+			printRaw(client.GetLanguagesRaw(*scope))
+			return
+		}
+		languages, err := client.GetLanguages(*scope)
+		if err != nil {
+			log.Fatalf("Error performing action: %v", err)
+		}
+		fmt.Printf("Translation: %d languages, transliteration: %d languages, dictionary: %d languages\n",
+			len(languages.Translation), len(languages.Transliteration), len(languages.Dictionary))
+		for code, language := range languages.Translation {
+			fmt.Printf("  %s\t%s (%s)\n", code, language.Name, language.NativeName)
+		}
 	case "translate":
 		if len(texts) == 0 || len(to) == 0 {
 			log.Fatalf("Missing required parameters for translate action. Example usage: go run translatortext_cmd.go -action translate -text \"Hello, world!\" -to fr")
 		}
-		result, err = client.Translate(texts, to)
+		options := &translator.TranslateOptions{
+			SuggestedFrom:         *suggestedFrom,
+			FromScript:            *fromScript,
+			ToScript:              *toScript,
+			TextType:              translator.TextType(*textType),
+			Category:              *category,
+			ProfanityAction:       translator.ProfanityAction(*profanityAction),
+			ProfanityMarker:       translator.ProfanityMarker(*profanityMarker),
+			IncludeAlignment:      *includeAlignment,
+			IncludeSentenceLength: *includeSentenceLength,
+		}
+		if len(from) > 0 {
+			options.From = from[0]
+		}
+		if *noFallback {
+			allowFallback := false
+			options.AllowFallback = &allowFallback
+		}
+		if err := options.Validate(); err != nil {
+			log.Fatalf("Invalid translate options: %v", err)
+		}
+		if *raw {
+			printRaw(client.TranslateRaw(texts, to, options))
+			return
+		}
+		results, err := client.Translate(texts, to, options)
+		if err != nil {
+			log.Fatalf("Error performing action: %v", err)
+		}
+		for i, result := range results {
+			fmt.Printf("📝 %s", texts[i])
+			if result.DetectedLanguage != nil {
+				fmt.Printf(" (%s, score %.2f)", result.DetectedLanguage.Language, result.DetectedLanguage.Score)
+			}
+			fmt.Println()
+			for _, translation := range result.Translations {
+				fmt.Printf("  %s: %s\n", translation.To, translation.Text)
+				if translation.Transliteration != nil {
+					fmt.Printf("    %s: %s\n", translation.Transliteration.Script, translation.Transliteration.Text)
+				}
+				if translation.Alignment != nil {
+					fmt.Printf("    alignment: %s\n", translation.Alignment.Projection)
+				}
+				if translation.SentenceLengths != nil {
+					fmt.Printf("    sentence lengths: %v -> %v\n", translation.SentenceLengths.Source, translation.SentenceLengths.Target)
+				}
+			}
+		}
 	case "transliterate":
 		if len(texts) == 0 || len(from) == 0 || len(to) == 0 || *language == "" {
 			log.Fatalf("Missing required parameters for transliterate action. Example usage: go run translatortext_cmd.go -action transliterate -text \"こんにちは\" -from jpan -to latn -language ja")
 		}
-		result, err = client.Transliterate(texts, *language, from[0], to[0])
+		if *raw {
+			printRaw(client.TransliterateRaw(texts, *language, from[0], to[0]))
+			return
+		}
+		results, err := client.Transliterate(texts, *language, from[0], to[0])
+		if err != nil {
+			log.Fatalf("Error performing action: %v", err)
+		}
+		for i, result := range results {
+			fmt.Printf("📝 %s -> %s (%s)\n", texts[i], result.Text, result.Script)
+		}
 	case "detect":
 		if len(texts) == 0 {
 			log.Fatalf("Missing required parameters for detect action. Example usage: go run translatortext_cmd.go -action detect -text \"Bonjour tout le monde\"")
 		}
-		result, err = client.Detect(texts)
+		if *raw {
+			printRaw(client.DetectRaw(texts))
+			return
+		}
+		results, err := client.Detect(texts)
+		if err != nil {
+			log.Fatalf("Error performing action: %v", err)
+		}
+		for i, result := range results {
+			fmt.Printf("📝 %s: %s (score %.2f)\n", texts[i], result.Language, result.Score)
+			for _, alternative := range result.Alternatives {
+				fmt.Printf("  or %s (score %.2f)\n", alternative.Language, alternative.Score)
+			}
+		}
 	case "dictionary-lookup":
 		if len(texts) == 0 || len(from) == 0 || len(to) == 0 {
 			log.Fatalf("Missing required parameters for dictionary-lookup action. Example usage: go run translatortext_cmd.go -action dictionary-lookup -text \"example\" -from en -to fr")
 		}
-		result, err = client.DictionaryLookup(texts, from[0], to[0])
+		if *raw {
+			printRaw(client.DictionaryLookupRaw(texts, from[0], to[0]))
+			return
+		}
+		results, err := client.DictionaryLookup(texts, from[0], to[0])
+		if err != nil {
+			log.Fatalf("Error performing action: %v", err)
+		}
+		for _, result := range results {
+			fmt.Printf("📖 %s\n", result.DisplaySource)
+			for _, translation := range result.Translations {
+				var backTranslations []string
+				for _, back := range translation.BackTranslations {
+					backTranslations = append(backTranslations, back.DisplayText)
+				}
+				fmt.Printf("  %s [%s, %.2f] <- %s\n", translation.DisplayTarget, translation.PosTag, translation.Confidence, strings.Join(backTranslations, ", "))
+			}
+		}
 	case "dictionary-examples":
 		if len(texts) == 0 || len(from) == 0 || len(to) == 0 {
 			log.Fatalf("Missing required parameters for dictionary-examples action. Example usage: go run translatortext_cmd.go -action dictionary-examples -text \"example\" -from en -to fr")
@@ -89,48 +200,42 @@ func main() {
 
 		// Step 1: Call Dictionary Lookup to Get Translations
 		fmt.Println("Fetching dictionary lookup translations...")
-		lookupResult, err := client.DictionaryLookup(texts, from[0], to[0])
+		lookupResults, err := client.DictionaryLookup(texts, from[0], to[0])
 		if err != nil {
 			log.Fatalf("Error fetching dictionary lookup: %v", err)
 		}
 
-		// Step 2: Extract translations from response
-		var lookupResponse []map[string]interface{}
-		err = json.Unmarshal(lookupResult, &lookupResponse)
-		if err != nil {
-			log.Fatalf("Failed to parse lookup response: %v", err)
-		}
-
-		// Step 3: Prepare Dictionary Examples Requests
-		var exampleRequests []map[string]string
-		for _, entry := range lookupResponse {
-			if translations, exists := entry["translations"].([]interface{}); exists {
-				for _, t := range translations {
-					if translation, ok := t.(map[string]interface{}); ok {
-						text := entry["normalizedSource"].(string)
-						translatedText := translation["normalizedTarget"].(string)
-						exampleRequests = append(exampleRequests, map[string]string{
-							"Text":        text,
-							"Translation": translatedText,
-						})
-					}
-				}
-			}
+		// Step 2: Pair each term with its translations
+		var exampleRequests []translator.DictionaryExampleRequest
+		for _, result := range lookupResults {
+			exampleRequests = append(exampleRequests, result.ExampleRequests()...)
 		}
 
-		// Step 4: Call Dictionary Examples for Each Translation
+		// Step 3: Call Dictionary Examples for Each Translation
 		fmt.Println("Fetching dictionary examples for translations...")
-		result, err = client.DictionaryExamples(exampleRequests, from[0], to[0])
+		if *raw {
+			printRaw(client.DictionaryExamplesRaw(exampleRequests, from[0], to[0]))
+			return
+		}
+		results, err := client.DictionaryExamples(exampleRequests, from[0], to[0])
 		if err != nil {
 			log.Fatalf("Error fetching dictionary examples: %v", err)
 		}
+		for _, result := range results {
+			fmt.Printf("📖 %s -> %s\n", result.NormalizedSource, result.NormalizedTarget)
+			for _, example := range result.Examples {
+				fmt.Printf("  %s\n  %s\n", example.Source(), example.Target())
+			}
+		}
 	default:
 		log.Fatalf("Invalid action specified. Example usage: go run translatortext_cmd.go -action translate -text \"Hello, world!\" -to fr")
 	}
+}
 
+// printRaw prints the JSON response of an XxxRaw call
+func printRaw(result []byte, err error) {
 	if err != nil {
 		log.Fatalf("Error performing action: %v", err)
 	}
-
 	fmt.Printf("Result: %s\n", result)
 }
@@ -0,0 +1,436 @@
+package speech
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ngothientuong/tngo-ai-svcs/internal/ai/translator"
+)
+
+/*
+Reference: https://learn.microsoft.com/en-us/azure/ai-services/speech-service/captioning-concepts
+Captions are built from word-level timestamps of final results. In real-time mode partial results are
+shown as they arrive and replaced by the final cue once the utterance is recognized.
+*/
+
+// CaptionOptions controls how recognized speech is split into cues
+type CaptionOptions struct {
+	MaxLineLength  int           // Characters per caption line, defaults to 42
+	LinesPerCue    int           // Lines shown at once, defaults to 2
+	MinCueDuration time.Duration // Short cues are extended up to the next cue, defaults to 1s
+	MaxCueDuration time.Duration // Long cues are split, defaults to 7s
+	MaskProfanity  bool          // Replace masked or listed profane words entirely with asterisks
+	ProfanityWords []string      // Additional words to mask when MaskProfanity is set
+}
+
+// Cue is a single caption shown between Start and End
+type Cue struct {
+	Index int           `json:"index"`
+	Start time.Duration `json:"start"`
+	End   time.Duration `json:"end"`
+	Lines []string      `json:"lines"`
+}
+
+// CaptionEvent carries a cue produced in real-time mode
+type CaptionEvent struct {
+	Cue     Cue
+	Partial bool // Partial cues are replaced by the next event
+}
+
+// Text returns the cue lines joined by newlines
+func (c Cue) Text() string {
+	return strings.Join(c.Lines, "\n")
+}
+
+func (o *CaptionOptions) withDefaults() CaptionOptions {
+	opts := CaptionOptions{}
+	if o != nil {
+		opts = *o
+	}
+	if opts.MaxLineLength <= 0 {
+		opts.MaxLineLength = 42
+	}
+	if opts.LinesPerCue <= 0 {
+		opts.LinesPerCue = 2
+	}
+	if opts.MinCueDuration <= 0 {
+		opts.MinCueDuration = time.Second
+	}
+	if opts.MaxCueDuration <= 0 {
+		opts.MaxCueDuration = 7 * time.Second
+	}
+	return opts
+}
+
+// captionWord is a displayable token with its timing
+type captionWord struct {
+	Text  string
+	Start time.Duration
+	End   time.Duration
+}
+
+// BuildCues splits final transcript segments into caption cues (post-processed mode)
+func BuildCues(segments []TranscriptSegment, opts *CaptionOptions) []Cue {
+	o := opts.withDefaults()
+
+	var cues []Cue
+	for _, segment := range segments {
+		cues = append(cues, segmentCues(segment, o)...)
+	}
+	return finalizeCues(cues, o)
+}
+
+// segmentCues splits one segment into cues without crossing into the next segment
+func segmentCues(segment TranscriptSegment, o CaptionOptions) []Cue {
+	words := captionWords(segment)
+	if o.MaskProfanity {
+		for i := range words {
+			words[i].Text = maskProfanity(words[i].Text, o.ProfanityWords)
+		}
+	}
+
+	var cues []Cue
+	var current *Cue
+	for _, word := range words {
+		if current != nil && !cueFits(current, word, o) {
+			cues = append(cues, *current)
+			current = nil
+		}
+		if current == nil {
+			current = &Cue{Start: word.Start, Lines: []string{word.Text}, End: word.End}
+			continue
+		}
+
+		last := len(current.Lines) - 1
+		if len(current.Lines[last])+1+len(word.Text) <= o.MaxLineLength {
+			current.Lines[last] += " " + word.Text
+		} else {
+			current.Lines = append(current.Lines, word.Text)
+		}
+		current.End = word.End
+	}
+	if current != nil {
+		cues = append(cues, *current)
+	}
+
+	return cues
+}
+
+// cueFits reports whether word can be appended to cue within the line and duration limits
+func cueFits(cue *Cue, word captionWord, o CaptionOptions) bool {
+	if word.End-cue.Start > o.MaxCueDuration {
+		return false
+	}
+	last := cue.Lines[len(cue.Lines)-1]
+	if len(last)+1+len(word.Text) <= o.MaxLineLength {
+		return true
+	}
+	return len(cue.Lines) < o.LinesPerCue
+}
+
+// captionWords pairs display tokens with word timings, falling back to lexical words or the whole segment
+func captionWords(segment TranscriptSegment) []captionWord {
+	timings := segment.Words()
+	if len(timings) == 0 {
+		return spreadWords(segment.Text, segment.Offset, segment.Offset+segment.Duration)
+	}
+
+	display := strings.Fields(segment.Text)
+	words := make([]captionWord, 0, len(timings))
+	for i, timing := range timings {
+		text := timing.Word
+		if len(display) == len(timings) {
+			text = display[i]
+		}
+		words = append(words, captionWord{Text: text, Start: timing.Offset, End: timing.Offset + timing.Duration})
+	}
+	return words
+}
+
+// spreadWords distributes a time range evenly across the words of text
+func spreadWords(text string, start, end time.Duration) []captionWord {
+	tokens := strings.Fields(text)
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	step := (end - start) / time.Duration(len(tokens))
+	words := make([]captionWord, 0, len(tokens))
+	for i, token := range tokens {
+		wordStart := start + time.Duration(i)*step
+		words = append(words, captionWord{Text: token, Start: wordStart, End: wordStart + step})
+	}
+	return words
+}
+
+// finalizeCues numbers cues and stretches short cues up to the next cue
+func finalizeCues(cues []Cue, o CaptionOptions) []Cue {
+	for i := range cues {
+		cues[i].Index = i + 1
+		if cues[i].End-cues[i].Start >= o.MinCueDuration {
+			continue
+		}
+		end := cues[i].Start + o.MinCueDuration
+		if i+1 < len(cues) && end > cues[i+1].Start {
+			end = cues[i+1].Start
+		}
+		if end > cues[i].End {
+			cues[i].End = end
+		}
+	}
+	return cues
+}
+
+// maskProfanity masks words the service already masked with asterisks and words from the extra list
+func maskProfanity(word string, profanityWords []string) string {
+	masked := strings.Repeat("*", len([]rune(word)))
+	if strings.Contains(word, "*") {
+		return masked
+	}
+	normalized := strings.ToLower(strings.Trim(word, ".,!?;:\"'"))
+	for _, profanity := range profanityWords {
+		if normalized == strings.ToLower(profanity) {
+			return masked
+		}
+	}
+	return word
+}
+
+// partialCue renders the tail of a partial result that fits on screen
+func partialCue(segment TranscriptSegment, o CaptionOptions) Cue {
+	words := spreadWords(segment.Text, segment.Offset, segment.Offset+segment.Duration)
+	if o.MaskProfanity {
+		for i := range words {
+			words[i].Text = maskProfanity(words[i].Text, o.ProfanityWords)
+		}
+	}
+
+	// Fill lines from the end so the most recent words stay visible
+	var lines []string
+	line := ""
+	for i := len(words) - 1; i >= 0; i-- {
+		text := words[i].Text
+		if line == "" {
+			line = text
+			continue
+		}
+		if len(line)+1+len(text) <= o.MaxLineLength {
+			line = text + " " + line
+			continue
+		}
+		lines = append([]string{line}, lines...)
+		line = text
+		if len(lines) == o.LinesPerCue {
+			line = ""
+			break
+		}
+	}
+	if line != "" && len(lines) < o.LinesPerCue {
+		lines = append([]string{line}, lines...)
+	}
+
+	return Cue{Start: segment.Offset, End: segment.Offset + segment.Duration, Lines: lines}
+}
+
+// StreamCaptions turns a running recognizer into caption events (real-time mode).
+// It consumes the recognizer's Partial, Final and Canceled channels, and closes the returned channel when the session ends;
+// the reason a session was canceled is reported by recognizer.Wait.
+func StreamCaptions(recognizer *Recognizer, opts *CaptionOptions) <-chan CaptionEvent {
+	o := opts.withDefaults()
+	events := make(chan CaptionEvent, eventBufferSize)
+
+	go func() {
+		defer close(events)
+
+		index := 0
+		partial, final, canceled := recognizer.Partial(), recognizer.Final(), recognizer.Canceled()
+		for partial != nil || final != nil || canceled != nil {
+			select {
+			case _, ok := <-canceled:
+				if !ok {
+					canceled = nil
+				}
+			case event, ok := <-partial:
+				if !ok {
+					partial = nil
+					continue
+				}
+				cue := partialCue(event.Segment, o)
+				cue.Index = index + 1
+				events <- CaptionEvent{Cue: cue, Partial: true}
+			case event, ok := <-final:
+				if !ok {
+					final = nil
+					continue
+				}
+				for _, cue := range finalizeCues(segmentCues(event.Segment, o), o) {
+					index++
+					cue.Index = index
+					events <- CaptionEvent{Cue: cue}
+				}
+			}
+		}
+	}()
+
+	return events
+}
+
+// translateBatchSize keeps Translator requests well under the 1000 element limit
+const translateBatchSize = 100
+
+// TranslateCues builds one subtitle track per target language within the source cue timings.
+// from is the recognition locale of the cues; Translator detects the language of each cue when it is empty.
+// A translation longer than LinesPerCue lines is split into consecutive cues sharing the source cue's time.
+func TranslateCues(client *translator.TranslatorClient, cues []Cue, from string, languages []string, opts *CaptionOptions) (map[string][]Cue, error) {
+	o := opts.withDefaults()
+	tracks := make(map[string][]Cue, len(languages))
+	translateOptions := &translator.TranslateOptions{From: translatorLanguage(from)}
+
+	texts := make([]string, len(cues))
+	for i, cue := range cues {
+		texts[i] = strings.Join(cue.Lines, " ")
+	}
+
+	for start := 0; start < len(texts); start += translateBatchSize {
+		end := start + translateBatchSize
+		if end > len(texts) {
+			end = len(texts)
+		}
+
+		response, err := client.Translate(texts[start:end], languages, translateOptions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to translate cues: %v", err)
+		}
+		if len(response) != end-start {
+			return nil, fmt.Errorf("expected %d translations, got %d", end-start, len(response))
+		}
+
+		for i, item := range response {
+			source := cues[start+i]
+			for _, translation := range item.Translations {
+				tracks[translation.To] = append(tracks[translation.To], translatedCues(source, translation.Text, o)...)
+			}
+		}
+	}
+
+	// Split translations shift the numbering of the following cues
+	for _, track := range tracks {
+		for i := range track {
+			track[i].Index = i + 1
+		}
+	}
+
+	return tracks, nil
+}
+
+// translatorLanguage maps a speech locale to its Translator language code: en-US becomes en,
+// while Chinese and the Portuguese and French variants Translator tells apart keep their script or region
+func translatorLanguage(locale string) string {
+	switch strings.ToLower(locale) {
+	case "":
+		return ""
+	case "zh-cn", "zh-sg":
+		return "zh-Hans"
+	case "zh-tw", "zh-hk":
+		return "zh-Hant"
+	case "pt-pt":
+		return "pt-pt"
+	case "fr-ca":
+		return "fr-ca"
+	}
+	language, _, _ := strings.Cut(locale, "-")
+	return strings.ToLower(language)
+}
+
+// translatedCues wraps a translation of source into cues of at most LinesPerCue lines,
+// dividing the source cue's time between them by their length
+func translatedCues(source Cue, text string, o CaptionOptions) []Cue {
+	lines := wrapLines(text, o.MaxLineLength)
+	if len(lines) <= o.LinesPerCue {
+		return []Cue{{Index: source.Index, Start: source.Start, End: source.End, Lines: lines}}
+	}
+
+	var groups [][]string
+	total := 0
+	for start := 0; start < len(lines); start += o.LinesPerCue {
+		group := lines[start:min(start+o.LinesPerCue, len(lines))]
+		groups = append(groups, group)
+		total += len(strings.Join(group, " "))
+	}
+
+	cues := make([]Cue, 0, len(groups))
+	duration, elapsed, start := source.End-source.Start, 0, source.Start
+	for i, group := range groups {
+		elapsed += len(strings.Join(group, " "))
+		end := source.Start + duration*time.Duration(elapsed)/time.Duration(total)
+		if i == len(groups)-1 {
+			end = source.End
+		}
+		cues = append(cues, Cue{Start: start, End: end, Lines: group})
+		start = end
+	}
+	return cues
+}
+
+// wrapLines wraps text at word boundaries to lines of at most maxLength characters
+func wrapLines(text string, maxLength int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && len(line)+1+len(word) > maxLength {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line == "" {
+			line = word
+		} else {
+			line += " " + word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// WriteSRT writes cues in SubRip format
+func WriteSRT(w io.Writer, cues []Cue) error {
+	for _, cue := range cues {
+		_, err := fmt.Fprintf(w, "%d\n%s --> %s\n%s\n\n", cue.Index, formatCueTime(cue.Start, ","), formatCueTime(cue.End, ","), cue.Text())
+		if err != nil {
+			return fmt.Errorf("failed to write SRT cue: %v", err)
+		}
+	}
+	return nil
+}
+
+// WriteWebVTT writes cues in WebVTT format
+func WriteWebVTT(w io.Writer, cues []Cue) error {
+	if _, err := io.WriteString(w, "WEBVTT\n\n"); err != nil {
+		return fmt.Errorf("failed to write WebVTT header: %v", err)
+	}
+	for _, cue := range cues {
+		_, err := fmt.Fprintf(w, "%d\n%s --> %s\n%s\n\n", cue.Index, formatCueTime(cue.Start, "."), formatCueTime(cue.End, "."), cue.Text())
+		if err != nil {
+			return fmt.Errorf("failed to write WebVTT cue: %v", err)
+		}
+	}
+	return nil
+}
+
+// formatCueTime renders hh:mm:ss followed by the milliseconds separator used by the format
+func formatCueTime(d time.Duration, separator string) string {
+	if d < 0 {
+		d = 0
+	}
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+	d -= minutes * time.Minute
+	seconds := d / time.Second
+	d -= seconds * time.Second
+	milliseconds := d / time.Millisecond
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", hours, minutes, seconds, separator, milliseconds)
+}
@@ -0,0 +1,289 @@
+package protocol
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+/*
+Client speaks the protocol to a translation server, for scripts and tests:
+
+	client, err := protocol.Dial(ctx, "ws://localhost:8080/ws", nil, nil)
+	started, err := client.Start(ctx, protocol.Start{TargetLanguages: []string{"vi"}, AudioFormat: "pcm"})
+	client.SendAudio(ctx, pcm)
+	for message := range client.Messages() { ... }
+
+Start and Stop wait for the server's reply; every other server message is delivered on Messages,
+which must be drained or the connection stalls and the server drops or disconnects the client.
+*/
+type Client struct {
+	conn         *Conn
+	capabilities Capabilities
+	messages     chan Message
+
+	mu        sync.Mutex
+	sessionID string
+	waiter    *replyWaiter
+	err       error
+	closed    bool
+}
+
+// replyWaiter receives the reply to a pending start or stop request
+type replyWaiter struct {
+	reply MessageType
+	ch    chan Message
+}
+
+// requestErrors are the error codes that answer a start or stop request rather than report on a running session
+var requestErrors = map[string]bool{
+	ErrorInvalidMessage: true,
+	ErrorHelloRequired:  true,
+	ErrorSessionExists:  true,
+	ErrorNoSession:      true,
+	ErrorStartFailed:    true,
+}
+
+// Dial connects to url, such as ws://localhost:8080/ws, and performs the hello exchange
+func Dial(ctx context.Context, url string, header http.Header, opts *ConnOptions) (*Client, error) {
+	ws, _, err := websocket.DefaultDialer.DialContext(ctx, url, header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %v", url, err)
+	}
+	conn := NewConn(ws, opts)
+
+	hello, err := NewMessage(TypeHello, "", Hello{Client: "tngo-ai-svcs-go"})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := conn.SendContext(ctx, hello); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to send hello: %v", err)
+	}
+
+	frame, err := conn.ReadFrame()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read capabilities: %v", err)
+	}
+	if err := ValidateServerMessage(frame.Message); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("invalid hello reply: %v", err)
+	}
+	if frame.Message.Type == TypeError {
+		conn.Close()
+		return nil, replyError(frame.Message)
+	}
+	if frame.Message.Type != TypeCapabilities {
+		conn.Close()
+		return nil, fmt.Errorf("expected capabilities, got %s", frame.Message.Type)
+	}
+
+	client := &Client{conn: conn, messages: make(chan Message, conn.Options().SendQueueSize)}
+	if err := frame.Message.Decode(&client.capabilities); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	go client.read()
+	return client, nil
+}
+
+// Capabilities returns what the server announced in reply to hello
+func (c *Client) Capabilities() Capabilities {
+	return c.capabilities
+}
+
+// SessionID returns the running session, empty when none
+func (c *Client) SessionID() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sessionID
+}
+
+// Start opens a translation session and waits for the server to confirm it
+func (c *Client) Start(ctx context.Context, start Start) (Started, error) {
+	message, err := NewMessage(TypeStart, "", start)
+	if err != nil {
+		return Started{}, err
+	}
+	reply, err := c.request(ctx, message, TypeStarted)
+	if err != nil {
+		return Started{}, err
+	}
+
+	var started Started
+	if err := reply.Decode(&started); err != nil {
+		return Started{}, err
+	}
+	c.mu.Lock()
+	c.sessionID = reply.SessionID
+	c.mu.Unlock()
+	return started, nil
+}
+
+// SendAudio streams a chunk of microphone audio in the format given to Start
+func (c *Client) SendAudio(ctx context.Context, data []byte) error {
+	return c.conn.SendBinary(ctx, data)
+}
+
+// SetMode changes what the running session delivers
+func (c *Client) SetMode(ctx context.Context, mode string) error {
+	message, err := NewMessage(TypeConfig, c.SessionID(), Config{Mode: mode})
+	if err != nil {
+		return err
+	}
+	return c.conn.SendContext(ctx, message)
+}
+
+// Heartbeat sends an application-level heartbeat; the server answers on Messages
+func (c *Client) Heartbeat(ctx context.Context, timestamp int64) error {
+	message, err := NewMessage(TypeHeartbeat, "", Heartbeat{Timestamp: timestamp})
+	if err != nil {
+		return err
+	}
+	return c.conn.SendContext(ctx, message)
+}
+
+// Stop ends the running session and waits for the server to confirm it
+func (c *Client) Stop(ctx context.Context) (Stopped, error) {
+	message, err := NewMessage(TypeStop, c.SessionID(), nil)
+	if err != nil {
+		return Stopped{}, err
+	}
+	reply, err := c.request(ctx, message, TypeStopped)
+	if err != nil {
+		return Stopped{}, err
+	}
+
+	var stopped Stopped
+	if len(reply.Payload) > 0 {
+		if err := reply.Decode(&stopped); err != nil {
+			return Stopped{}, err
+		}
+	}
+	c.mu.Lock()
+	c.sessionID = ""
+	c.mu.Unlock()
+	return stopped, nil
+}
+
+// Messages delivers server messages; it is closed when the connection ends
+func (c *Client) Messages() <-chan Message {
+	return c.messages
+}
+
+// Err returns the error that ended the connection, if any
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Close ends the connection; the server stops the session it owns
+func (c *Client) Close() error {
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+	return c.conn.Close()
+}
+
+// request sends message and waits for a reply of the given type or a request error
+func (c *Client) request(ctx context.Context, message Message, reply MessageType) (Message, error) {
+	waiter := &replyWaiter{reply: reply, ch: make(chan Message, 1)}
+	c.mu.Lock()
+	if c.waiter != nil {
+		c.mu.Unlock()
+		return Message{}, fmt.Errorf("another request is pending")
+	}
+	c.waiter = waiter
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		if c.waiter == waiter {
+			c.waiter = nil
+		}
+		c.mu.Unlock()
+	}()
+
+	if err := c.conn.SendContext(ctx, message); err != nil {
+		return Message{}, fmt.Errorf("failed to send %s: %v", message.Type, err)
+	}
+
+	select {
+	case response := <-waiter.ch:
+		if response.Type == TypeError {
+			return Message{}, replyError(response)
+		}
+		return response, nil
+	case <-c.conn.Done():
+		return Message{}, fmt.Errorf("connection closed while waiting for %s", reply)
+	case <-ctx.Done():
+		return Message{}, ctx.Err()
+	}
+}
+
+// read routes server messages to a pending request or to Messages
+func (c *Client) read() {
+	defer close(c.messages)
+	for {
+		frame, err := c.conn.ReadFrame()
+		if err != nil {
+			if _, ok := err.(*ValidationError); ok {
+				continue
+			}
+			c.mu.Lock()
+			if !c.closed && !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				c.err = err
+			}
+			c.mu.Unlock()
+			return
+		}
+		if frame.Binary || ValidateServerMessage(frame.Message) != nil {
+			continue
+		}
+
+		if c.deliverReply(frame.Message) {
+			continue
+		}
+		select {
+		case c.messages <- frame.Message:
+		case <-c.conn.Done():
+			return
+		}
+	}
+}
+
+func (c *Client) deliverReply(message Message) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.waiter == nil {
+		return false
+	}
+	if message.Type != c.waiter.reply && !(message.Type == TypeError && isRequestError(message)) {
+		return false
+	}
+	c.waiter.ch <- message
+	c.waiter = nil
+	return true
+}
+
+func isRequestError(message Message) bool {
+	var e Error
+	if message.Decode(&e) != nil {
+		return false
+	}
+	return requestErrors[e.Code]
+}
+
+func replyError(message Message) error {
+	var e Error
+	if err := message.Decode(&e); err != nil {
+		return err
+	}
+	return fmt.Errorf("server error %s: %s", e.Code, e.Message)
+}
@@ -0,0 +1,372 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// newTestServer serves the protocol over httptest: it performs the hello exchange, rejects invalid
+// client messages like the translation server, and hands every valid frame to handle
+func newTestServer(t *testing.T, opts *ConnOptions, handle func(conn *Conn, frame Frame)) string {
+	return newRawTestServer(t, opts, func(conn *Conn) {
+		frame, err := conn.ReadFrame()
+		if err != nil {
+			return
+		}
+		if frame.Binary || frame.Message.Type != TypeHello {
+			conn.Send(ErrorMessage("", ErrorHelloRequired, "the first message must be hello"))
+			conn.CloseWithError(ErrorHelloRequired)
+			return
+		}
+		capabilities, _ := NewMessage(TypeCapabilities, "", Capabilities{Version: Version, AudioFormats: AudioFormats, Modes: Modes})
+		conn.Send(capabilities)
+
+		for {
+			frame, err := conn.ReadFrame()
+			var invalid *ValidationError
+			if errors.As(err, &invalid) {
+				conn.Send(ErrorMessage("", invalid.Code, invalid.Message))
+				continue
+			}
+			if err != nil {
+				return
+			}
+			if !frame.Binary {
+				if err := ValidateClientMessage(frame.Message); errors.As(err, &invalid) {
+					conn.Send(ErrorMessage(frame.Message.SessionID, invalid.Code, invalid.Message))
+					continue
+				}
+			}
+			handle(conn, frame)
+		}
+	})
+}
+
+// newRawTestServer upgrades every request and hands the connection to serve, which owns it
+func newRawTestServer(t *testing.T, opts *ConnOptions, serve func(conn *Conn)) string {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		conn := NewConn(ws, opts)
+		defer conn.Close()
+		serve(conn)
+	}))
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+// echoSession answers start, stop and heartbeat, and reports the audio bytes received in a final result
+func echoSession() func(conn *Conn, frame Frame) {
+	var sessionID string
+	var received int
+	return func(conn *Conn, frame Frame) {
+		if frame.Binary {
+			if sessionID == "" {
+				conn.Send(ErrorMessage("", ErrorAudioRejected, "no session is running"))
+				return
+			}
+			received += len(frame.Audio)
+			return
+		}
+
+		message := frame.Message
+		switch message.Type {
+		case TypeStart:
+			if sessionID != "" {
+				conn.Send(ErrorMessage(sessionID, ErrorSessionExists, "a session is already running"))
+				return
+			}
+			var start Start
+			message.Decode(&start)
+			sessionID = "session-1"
+			started, _ := NewMessage(TypeStarted, sessionID, Started{SourceLanguage: "en-US", TargetLanguages: start.TargetLanguages, Mode: start.Mode})
+			conn.Send(started)
+		case TypeStop:
+			if sessionID == "" {
+				conn.Send(ErrorMessage("", ErrorNoSession, "no session is running"))
+				return
+			}
+			final, _ := NewMessage(TypeFinal, sessionID, Recognition{Text: fmt.Sprintf("%d bytes", received)})
+			stopped, _ := NewMessage(TypeStopped, sessionID, Stopped{})
+			conn.Send(final)
+			conn.Send(stopped)
+			sessionID, received = "", 0
+		case TypeHeartbeat:
+			conn.Send(message)
+		}
+	}
+}
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func dial(t *testing.T, ctx context.Context, url string, opts *ConnOptions) *Client {
+	client, err := Dial(ctx, url, nil, opts)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func receive(t *testing.T, ctx context.Context, client *Client) Message {
+	select {
+	case message, ok := <-client.Messages():
+		if !ok {
+			t.Fatalf("connection ended: %v", client.Err())
+		}
+		return message
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for a server message")
+	}
+	return Message{}
+}
+
+func TestClientRoundTrip(t *testing.T) {
+	ctx := testContext(t)
+	client := dial(t, ctx, newTestServer(t, nil, echoSession()), nil)
+
+	if capabilities := client.Capabilities(); capabilities.Version != Version || len(capabilities.Modes) != len(Modes) {
+		t.Errorf("capabilities = %+v", capabilities)
+	}
+
+	started, err := client.Start(ctx, Start{TargetLanguages: []string{"vi", "fr"}, Mode: "both", AudioFormat: "pcm"})
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if client.SessionID() != "session-1" {
+		t.Errorf("session ID = %q", client.SessionID())
+	}
+	if started.Mode != "both" || len(started.TargetLanguages) != 2 {
+		t.Errorf("started = %+v", started)
+	}
+
+	chunk := make([]byte, 3200)
+	for i := 0; i < 5; i++ {
+		if err := client.SendAudio(ctx, chunk); err != nil {
+			t.Fatalf("send audio: %v", err)
+		}
+	}
+
+	if _, err := client.Stop(ctx); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if client.SessionID() != "" {
+		t.Errorf("session ID = %q after stop", client.SessionID())
+	}
+
+	final := receive(t, ctx, client)
+	var recognition Recognition
+	if final.Type != TypeFinal || final.Decode(&recognition) != nil {
+		t.Fatalf("expected a final result, got %+v", final)
+	}
+	if recognition.Text != "16000 bytes" {
+		t.Errorf("server received %s, want 16000 bytes", recognition.Text)
+	}
+}
+
+func TestClientRequestErrors(t *testing.T) {
+	ctx := testContext(t)
+	client := dial(t, ctx, newTestServer(t, nil, echoSession()), nil)
+
+	if _, err := client.Stop(ctx); err == nil || !strings.Contains(err.Error(), ErrorNoSession) {
+		t.Errorf("stop without a session: %v, want %s", err, ErrorNoSession)
+	}
+	if _, err := client.Start(ctx, Start{TargetLanguages: []string{"vi"}, Mode: "loud"}); err == nil || !strings.Contains(err.Error(), ErrorInvalidMessage) {
+		t.Errorf("start with an unknown mode: %v, want %s", err, ErrorInvalidMessage)
+	}
+	if _, err := client.Start(ctx, Start{}); err == nil || !strings.Contains(err.Error(), ErrorInvalidMessage) {
+		t.Errorf("start without target languages: %v, want %s", err, ErrorInvalidMessage)
+	}
+
+	// A rejected request releases the waiter, so the connection stays usable
+	if _, err := client.Start(ctx, Start{TargetLanguages: []string{"vi"}}); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if _, err := client.Start(ctx, Start{TargetLanguages: []string{"vi"}}); err == nil || !strings.Contains(err.Error(), ErrorSessionExists) {
+		t.Errorf("second start: %v, want %s", err, ErrorSessionExists)
+	}
+}
+
+func TestClientSessionErrorsOnMessages(t *testing.T) {
+	ctx := testContext(t)
+	client := dial(t, ctx, newTestServer(t, nil, echoSession()), nil)
+
+	// Audio without a session is rejected with an error that is not the reply to a request
+	if err := client.SendAudio(ctx, []byte{0, 1}); err != nil {
+		t.Fatalf("send audio: %v", err)
+	}
+	message := receive(t, ctx, client)
+	var e Error
+	if message.Type != TypeError || message.Decode(&e) != nil || e.Code != ErrorAudioRejected {
+		t.Errorf("expected %s on Messages, got %+v", ErrorAudioRejected, message)
+	}
+}
+
+func TestClientPendingRequest(t *testing.T) {
+	ctx := testContext(t)
+	release := make(chan struct{})
+	url := newTestServer(t, nil, func(conn *Conn, frame Frame) {
+		<-release
+	})
+	client := dial(t, ctx, url, nil)
+	defer close(release)
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := client.Start(ctx, Start{TargetLanguages: []string{"vi"}})
+		errs <- err
+	}()
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(5 * time.Millisecond) {
+		client.mu.Lock()
+		pending := client.waiter != nil
+		client.mu.Unlock()
+		if pending {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("start never became pending")
+		}
+	}
+
+	if _, err := client.Stop(ctx); err == nil || !strings.Contains(err.Error(), "pending") {
+		t.Errorf("stop during a pending start: %v, want a pending request error", err)
+	}
+
+	client.Close()
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Error("start succeeded on a closed connection")
+		}
+	case <-ctx.Done():
+		t.Fatal("start did not return after close")
+	}
+}
+
+func TestDialHelloRejected(t *testing.T) {
+	ctx := testContext(t)
+	url := newRawTestServer(t, nil, func(conn *Conn) {
+		conn.ReadFrame()
+		conn.Send(ErrorMessage("", ErrorUnsupportedVersion, "unsupported protocol version"))
+		conn.CloseWithError(ErrorUnsupportedVersion)
+	})
+
+	if _, err := Dial(ctx, url, nil, nil); err == nil || !strings.Contains(err.Error(), ErrorUnsupportedVersion) {
+		t.Errorf("dial: %v, want %s", err, ErrorUnsupportedVersion)
+	}
+}
+
+func TestHeartbeat(t *testing.T) {
+	ctx := testContext(t)
+	client := dial(t, ctx, newTestServer(t, nil, echoSession()), nil)
+
+	if err := client.Heartbeat(ctx, 1234); err != nil {
+		t.Fatalf("heartbeat: %v", err)
+	}
+	message := receive(t, ctx, client)
+	var heartbeat Heartbeat
+	if message.Type != TypeHeartbeat || message.Decode(&heartbeat) != nil || heartbeat.Timestamp != 1234 {
+		t.Errorf("expected the heartbeat back, got %+v", message)
+	}
+}
+
+func TestPingKeepsIdleConnectionAlive(t *testing.T) {
+	ctx := testContext(t)
+	opts := &ConnOptions{PongTimeout: 150 * time.Millisecond, PingInterval: 50 * time.Millisecond}
+	client := dial(t, ctx, newTestServer(t, opts, echoSession()), opts)
+
+	// Without pings and pongs both read deadlines would expire several times over
+	time.Sleep(500 * time.Millisecond)
+
+	if err := client.Heartbeat(ctx, 1); err != nil {
+		t.Fatalf("heartbeat after idling: %v", err)
+	}
+	if message := receive(t, ctx, client); message.Type != TypeHeartbeat {
+		t.Errorf("expected a heartbeat, got %+v", message)
+	}
+}
+
+func TestPongTimeoutEndsConnection(t *testing.T) {
+	ctx := testContext(t)
+	release := make(chan struct{})
+	url := newRawTestServer(t, nil, func(conn *Conn) {
+		conn.ReadFrame()
+		capabilities, _ := NewMessage(TypeCapabilities, "", Capabilities{Version: Version})
+		conn.Send(capabilities)
+		// Stop reading, so pings from the client are never answered
+		<-release
+	})
+	defer close(release)
+
+	client := dial(t, ctx, url, &ConnOptions{PongTimeout: 150 * time.Millisecond, PingInterval: 50 * time.Millisecond})
+	select {
+	case _, ok := <-client.Messages():
+		if ok {
+			t.Fatal("unexpected message")
+		}
+	case <-ctx.Done():
+		t.Fatal("connection outlived the pong timeout")
+	}
+	if client.Err() == nil {
+		t.Error("expected a read timeout error")
+	}
+}
+
+func TestSendBackpressure(t *testing.T) {
+	release := make(chan struct{})
+	conns := make(chan *Conn, 1)
+	url := newRawTestServer(t, &ConnOptions{SendQueueSize: 1, WriteTimeout: 200 * time.Millisecond}, func(conn *Conn) {
+		conns <- conn
+		<-release
+	})
+	defer close(release)
+
+	// The client never reads, so the server's socket buffers and then its send queue fill up
+	ws, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer ws.Close()
+	conn := <-conns
+
+	partial, _ := NewMessage(TypePartial, "session-1", Recognition{Text: strings.Repeat("a", 256<<10)})
+	for i := 0; conn.Dropped() == 0; i++ {
+		if i == 1000 {
+			t.Fatal("no partial result was dropped")
+		}
+		if err := conn.Send(partial); err != nil {
+			t.Fatalf("send partial: %v", err)
+		}
+	}
+
+	final, _ := NewMessage(TypeFinal, "session-1", Recognition{Text: "done"})
+	if err := conn.Send(final); err != ErrSlowConsumer {
+		t.Fatalf("send final to a slow consumer: %v, want %v", err, ErrSlowConsumer)
+	}
+	select {
+	case <-conn.Done():
+	case <-time.After(time.Second):
+		t.Fatal("connection not closed after a slow consumer")
+	}
+	if conn.Err() != ErrSlowConsumer {
+		t.Errorf("connection error = %v, want %v", conn.Err(), ErrSlowConsumer)
+	}
+	if err := conn.Send(final); err != ErrClosed {
+		t.Errorf("send after close: %v, want %v", err, ErrClosed)
+	}
+}
@@ -0,0 +1,294 @@
+package protocol
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+var (
+	// ErrSlowConsumer is returned when a peer stops reading and the send queue fills with messages that cannot be dropped
+	ErrSlowConsumer = errors.New("peer is not reading fast enough")
+
+	// ErrClosed is returned when sending on a closed connection
+	ErrClosed = errors.New("connection closed")
+)
+
+// ConnOptions tunes keepalive and backpressure; zero values use the defaults
+type ConnOptions struct {
+	SendQueueSize  int           // Frames buffered per connection, 64 by default
+	WriteTimeout   time.Duration // Deadline of a single frame write, 10s by default
+	PongTimeout    time.Duration // The peer is considered gone after this long without a frame or pong, 60s by default
+	PingInterval   time.Duration // 9/10 of PongTimeout by default
+	MaxMessageSize int64         // Largest accepted frame, 1 MiB by default
+}
+
+// WithDefaults returns the options with zero values replaced by the defaults
+func (o *ConnOptions) WithDefaults() ConnOptions {
+	var options ConnOptions
+	if o != nil {
+		options = *o
+	}
+	if options.SendQueueSize <= 0 {
+		options.SendQueueSize = 64
+	}
+	if options.WriteTimeout <= 0 {
+		options.WriteTimeout = 10 * time.Second
+	}
+	if options.PongTimeout <= 0 {
+		options.PongTimeout = 60 * time.Second
+	}
+	if options.PingInterval <= 0 || options.PingInterval >= options.PongTimeout {
+		options.PingInterval = options.PongTimeout * 9 / 10
+	}
+	if options.MaxMessageSize <= 0 {
+		options.MaxMessageSize = 1 << 20
+	}
+	return options
+}
+
+// Frame is one received frame: a protocol message, or audio when Binary is set
+type Frame struct {
+	Message Message
+	Binary  bool
+	Audio   []byte
+}
+
+type outgoing struct {
+	messageType int
+	data        []byte
+}
+
+/*
+Conn wraps a WebSocket with the protocol's keepalive and backpressure rules:
+✅ A single writer goroutine owns all writes and pings the peer every PingInterval
+✅ Every received frame or pong extends the read deadline by PongTimeout
+✅ Send never blocks: when the queue is full, partial results and heartbeats are dropped, anything else closes the connection because the peer can no longer keep up
+✅ SendContext blocks instead, for producers that should slow down rather than lose data
+Only one goroutine may call ReadFrame.
+*/
+type Conn struct {
+	ws      *websocket.Conn
+	options ConnOptions
+	send    chan outgoing
+	dropped atomic.Int64
+
+	mu          sync.Mutex
+	err         error
+	closeCode   int
+	closeReason string
+	closeOnce   sync.Once
+	done        chan struct{}
+	writerDone  chan struct{}
+}
+
+// NewConn takes ownership of ws and starts its writer
+func NewConn(ws *websocket.Conn, opts *ConnOptions) *Conn {
+	c := &Conn{
+		ws:         ws,
+		options:    opts.WithDefaults(),
+		done:       make(chan struct{}),
+		writerDone: make(chan struct{}),
+	}
+	c.send = make(chan outgoing, c.options.SendQueueSize)
+
+	ws.SetReadLimit(c.options.MaxMessageSize)
+	ws.SetReadDeadline(time.Now().Add(c.options.PongTimeout))
+	ws.SetPongHandler(func(string) error {
+		return ws.SetReadDeadline(time.Now().Add(c.options.PongTimeout))
+	})
+
+	go c.write()
+	return c
+}
+
+// Options returns the effective options
+func (c *Conn) Options() ConnOptions {
+	return c.options
+}
+
+// ReadFrame blocks for the next frame. Text frames that are not JSON envelopes return a
+// *ValidationError and leave the connection usable; any other error ends the connection.
+func (c *Conn) ReadFrame() (Frame, error) {
+	messageType, data, err := c.ws.ReadMessage()
+	if err != nil {
+		c.fail(err)
+		return Frame{}, err
+	}
+	c.ws.SetReadDeadline(time.Now().Add(c.options.PongTimeout))
+
+	if messageType == websocket.BinaryMessage {
+		return Frame{Binary: true, Audio: data}, nil
+	}
+	var message Message
+	if err := json.Unmarshal(data, &message); err != nil {
+		return Frame{}, invalid("message is not a JSON envelope: %v", err)
+	}
+	return Frame{Message: message}, nil
+}
+
+// Send queues a message without blocking, applying the backpressure rules
+func (c *Conn) Send(message Message) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s message: %v", message.Type, err)
+	}
+
+	select {
+	case <-c.done:
+		return ErrClosed
+	default:
+	}
+	select {
+	case c.send <- outgoing{messageType: websocket.TextMessage, data: data}:
+		return nil
+	default:
+	}
+
+	if message.Type == TypePartial || message.Type == TypeHeartbeat {
+		c.dropped.Add(1)
+		return nil
+	}
+	c.closeWith(websocket.ClosePolicyViolation, "send queue full", ErrSlowConsumer)
+	return ErrSlowConsumer
+}
+
+// SendContext queues a message, waiting for room in the queue
+func (c *Conn) SendContext(ctx context.Context, message Message) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s message: %v", message.Type, err)
+	}
+	return c.enqueue(ctx, outgoing{messageType: websocket.TextMessage, data: data})
+}
+
+// SendBinary queues an audio frame, waiting for room in the queue
+func (c *Conn) SendBinary(ctx context.Context, data []byte) error {
+	return c.enqueue(ctx, outgoing{messageType: websocket.BinaryMessage, data: data})
+}
+
+func (c *Conn) enqueue(ctx context.Context, frame outgoing) error {
+	select {
+	case <-c.done:
+		return ErrClosed
+	default:
+	}
+	select {
+	case c.send <- frame:
+		return nil
+	case <-c.done:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Dropped returns the number of messages dropped for a slow peer
+func (c *Conn) Dropped() int64 {
+	return c.dropped.Load()
+}
+
+// Done is closed when the connection is closing
+func (c *Conn) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns the error that ended the connection, if any
+func (c *Conn) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Close flushes queued frames, sends a normal close frame and closes the socket
+func (c *Conn) Close() error {
+	c.closeWith(websocket.CloseNormalClosure, "", nil)
+	<-c.writerDone
+	return nil
+}
+
+// CloseWithError flushes queued frames, then closes with a policy violation carrying reason
+func (c *Conn) CloseWithError(reason string) {
+	c.closeWith(websocket.ClosePolicyViolation, reason, nil)
+	<-c.writerDone
+}
+
+// CloseGoingAway flushes queued frames, then closes telling the peer the server is going away
+func (c *Conn) CloseGoingAway(reason string) {
+	c.closeWith(websocket.CloseGoingAway, reason, nil)
+	<-c.writerDone
+}
+
+func (c *Conn) closeWith(code int, reason string, err error) {
+	c.closeOnce.Do(func() {
+		c.mu.Lock()
+		c.closeCode = code
+		c.closeReason = reason
+		if c.err == nil {
+			c.err = err
+		}
+		c.mu.Unlock()
+		close(c.done)
+	})
+}
+
+// fail ends the connection after a read or write error, without a close handshake
+func (c *Conn) fail(err error) {
+	c.closeWith(0, "", err)
+}
+
+// write is the only goroutine writing to the socket
+func (c *Conn) write() {
+	ticker := time.NewTicker(c.options.PingInterval)
+	defer func() {
+		ticker.Stop()
+		c.ws.Close()
+		close(c.writerDone)
+	}()
+
+	for {
+		select {
+		case frame := <-c.send:
+			if err := c.writeFrame(frame); err != nil {
+				c.fail(err)
+				return
+			}
+		case <-ticker.C:
+			if err := c.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(c.options.WriteTimeout)); err != nil {
+				c.fail(err)
+				return
+			}
+		case <-c.done:
+			c.mu.Lock()
+			code, reason, err := c.closeCode, c.closeReason, c.err
+			c.mu.Unlock()
+			if code == 0 {
+				return
+			}
+			// Flush what was queued before the close, such as a final error message, unless the peer stopped reading
+			for flushing := err != ErrSlowConsumer; flushing; {
+				select {
+				case frame := <-c.send:
+					if c.writeFrame(frame) != nil {
+						return
+					}
+				default:
+					flushing = false
+				}
+			}
+			c.ws.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(c.options.WriteTimeout))
+			return
+		}
+	}
+}
+
+func (c *Conn) writeFrame(frame outgoing) error {
+	c.ws.SetWriteDeadline(time.Now().Add(c.options.WriteTimeout))
+	return c.ws.WriteMessage(frame.messageType, frame.data)
+}
@@ -0,0 +1,154 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+/*
+Versioned WebSocket protocol of the speech translation server, documented in docs/TranslationWebSocketProtocol.md.
+Control messages are JSON envelopes in text frames. Microphone audio from the client travels in binary frames.
+*/
+
+// Version is the protocol version spoken by this package; peers must agree on it in the hello exchange
+const Version = "1"
+
+// MessageType identifies a protocol message
+type MessageType string
+
+const (
+	TypeHello        MessageType = "hello"        // client → server, first message of a connection
+	TypeCapabilities MessageType = "capabilities" // server → client, reply to hello
+	TypeStart        MessageType = "start"        // client → server
+	TypeStarted      MessageType = "started"      // server → client
+	TypeStop         MessageType = "stop"         // client → server
+	TypeStopped      MessageType = "stopped"      // server → client
+	TypeConfig       MessageType = "config"       // client → server, updates a running session
+	TypePartial      MessageType = "partial"      // server → client
+	TypeFinal        MessageType = "final"        // server → client
+	TypeTranslation  MessageType = "translation"  // server → client
+	TypeAudio        MessageType = "audio"        // server → client
+	TypeError        MessageType = "error"        // server → client
+	TypeHeartbeat    MessageType = "heartbeat"    // both directions
+)
+
+// Error codes carried by error messages
+const (
+	ErrorInvalidMessage     = "invalid_message"
+	ErrorUnsupportedVersion = "unsupported_version"
+	ErrorHelloRequired      = "hello_required"
+	ErrorSessionExists      = "session_exists"
+	ErrorNoSession          = "no_session"
+	ErrorStartFailed        = "start_failed"
+	ErrorAudioRejected      = "audio_rejected"
+	ErrorRecognition        = "recognition_failed"
+	ErrorSynthesis          = "synthesis_failed"
+)
+
+// Message is the envelope of every text frame
+type Message struct {
+	Version   string          `json:"version"`
+	Type      MessageType     `json:"type"`
+	SessionID string          `json:"sessionId,omitempty"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+}
+
+// Hello opens a connection
+type Hello struct {
+	Client string `json:"client,omitempty"` // Free-form client name for logs
+}
+
+// Capabilities describes what the server accepts
+type Capabilities struct {
+	Version             string   `json:"version"`
+	AudioFormats        []string `json:"audioFormats"` // Accepted start.audioFormat values; empty means the server microphone
+	Modes               []string `json:"modes"`
+	MaxFrameBytes       int      `json:"maxFrameBytes"`
+	HeartbeatIntervalMs int      `json:"heartbeatIntervalMs"`
+}
+
+// Start asks the server to open a translation session on this connection
+type Start struct {
+	SourceLanguage  string            `json:"sourceLanguage,omitempty"`
+	TargetLanguages []string          `json:"targetLanguages"`
+	Voices          map[string]string `json:"voices,omitempty"`
+	Mode            string            `json:"mode,omitempty"`
+	AudioFormat     string            `json:"audioFormat,omitempty"` // pcm, ogg-opus or any; empty uses the server microphone
+}
+
+// Started confirms a session
+type Started struct {
+	SourceLanguage  string   `json:"sourceLanguage"`
+	TargetLanguages []string `json:"targetLanguages"`
+	Mode            string   `json:"mode"`
+}
+
+// Stopped reports the end of a session
+type Stopped struct {
+	Error string `json:"error,omitempty"`
+}
+
+// Config updates a running session
+type Config struct {
+	Mode string `json:"mode"`
+}
+
+// Recognition carries partial or final source text, with translations when available
+type Recognition struct {
+	Text         string            `json:"text"`
+	Translations map[string]string `json:"translations,omitempty"`
+}
+
+// Translation carries the final translations of an utterance
+type Translation struct {
+	SourceText   string            `json:"sourceText"`
+	Translations map[string]string `json:"translations"`
+}
+
+// Audio carries one synthesized utterance, base64 encoded in JSON
+type Audio struct {
+	Language string `json:"language"`
+	Format   string `json:"format"`
+	Data     []byte `json:"data"`
+}
+
+// Error reports a failure; fatal errors are followed by the server closing the connection
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Heartbeat keeps idle connections alive for clients that cannot send WebSocket pings
+type Heartbeat struct {
+	Timestamp int64 `json:"timestamp"` // Unix milliseconds
+}
+
+// NewMessage wraps payload in an envelope of the current version
+func NewMessage(messageType MessageType, sessionID string, payload interface{}) (Message, error) {
+	message := Message{Version: Version, Type: messageType, SessionID: sessionID}
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return Message{}, fmt.Errorf("failed to marshal %s payload: %v", messageType, err)
+		}
+		message.Payload = data
+	}
+	return message, nil
+}
+
+// Decode unmarshals the payload into v
+func (m Message) Decode(v interface{}) error {
+	if len(m.Payload) == 0 {
+		return fmt.Errorf("%s message has no payload", m.Type)
+	}
+	if err := json.Unmarshal(m.Payload, v); err != nil {
+		return fmt.Errorf("failed to decode %s payload: %v", m.Type, err)
+	}
+	return nil
+}
+
+// ErrorMessage builds an error message
+func ErrorMessage(sessionID, code, text string) Message {
+	message, _ := NewMessage(TypeError, sessionID, Error{Code: code, Message: text})
+	return message
+}
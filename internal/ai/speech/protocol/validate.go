@@ -0,0 +1,167 @@
+package protocol
+
+import (
+	"fmt"
+)
+
+// Modes accepted by start and config messages
+var Modes = []string{"text-only", "speech-only", "both"}
+
+// AudioFormats accepted by start messages; empty selects the server microphone
+var AudioFormats = []string{"pcm", "ogg-opus", "any"}
+
+var clientTypes = map[MessageType]bool{
+	TypeHello:     true,
+	TypeStart:     true,
+	TypeStop:      true,
+	TypeConfig:    true,
+	TypeHeartbeat: true,
+}
+
+var serverTypes = map[MessageType]bool{
+	TypeCapabilities: true,
+	TypeStarted:      true,
+	TypeStopped:      true,
+	TypePartial:      true,
+	TypeFinal:        true,
+	TypeTranslation:  true,
+	TypeAudio:        true,
+	TypeError:        true,
+	TypeHeartbeat:    true,
+}
+
+// ValidationError describes why a message was rejected, with the error code to report
+type ValidationError struct {
+	Code    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func invalid(format string, args ...interface{}) error {
+	return &ValidationError{Code: ErrorInvalidMessage, Message: fmt.Sprintf(format, args...)}
+}
+
+// ValidateClientMessage checks a message received by the server
+func ValidateClientMessage(m Message) error {
+	if err := validateEnvelope(m); err != nil {
+		return err
+	}
+	if !clientTypes[m.Type] {
+		return invalid("unexpected message type from client: %s", m.Type)
+	}
+	return validatePayload(m)
+}
+
+// ValidateServerMessage checks a message received by the client
+func ValidateServerMessage(m Message) error {
+	if err := validateEnvelope(m); err != nil {
+		return err
+	}
+	if !serverTypes[m.Type] {
+		return invalid("unexpected message type from server: %s", m.Type)
+	}
+	return validatePayload(m)
+}
+
+func validateEnvelope(m Message) error {
+	if m.Version != Version {
+		return &ValidationError{Code: ErrorUnsupportedVersion, Message: fmt.Sprintf("unsupported protocol version %q, expected %q", m.Version, Version)}
+	}
+	if m.Type == "" {
+		return invalid("message type is required")
+	}
+	return nil
+}
+
+func validatePayload(m Message) error {
+	switch m.Type {
+	case TypeStart:
+		var start Start
+		if err := m.Decode(&start); err != nil {
+			return invalid("%v", err)
+		}
+		if len(start.TargetLanguages) == 0 {
+			return invalid("start requires at least one target language")
+		}
+		for _, language := range start.TargetLanguages {
+			if language == "" {
+				return invalid("start has an empty target language")
+			}
+		}
+		if start.Mode != "" && !contains(Modes, start.Mode) {
+			return invalid("unsupported mode: %s", start.Mode)
+		}
+		if start.AudioFormat != "" && !contains(AudioFormats, start.AudioFormat) {
+			return invalid("unsupported audio format: %s", start.AudioFormat)
+		}
+	case TypeConfig:
+		var config Config
+		if err := m.Decode(&config); err != nil {
+			return invalid("%v", err)
+		}
+		if !contains(Modes, config.Mode) {
+			return invalid("unsupported mode: %s", config.Mode)
+		}
+	case TypeStarted, TypeStopped, TypePartial, TypeFinal, TypeTranslation, TypeAudio:
+		if m.SessionID == "" {
+			return invalid("%s requires a session ID", m.Type)
+		}
+		return validateSessionPayload(m)
+	case TypeError:
+		var e Error
+		if err := m.Decode(&e); err != nil {
+			return invalid("%v", err)
+		}
+		if e.Code == "" {
+			return invalid("error requires a code")
+		}
+	case TypeCapabilities:
+		var capabilities Capabilities
+		if err := m.Decode(&capabilities); err != nil {
+			return invalid("%v", err)
+		}
+		if capabilities.Version == "" {
+			return invalid("capabilities require a version")
+		}
+	}
+	return nil
+}
+
+func validateSessionPayload(m Message) error {
+	switch m.Type {
+	case TypeTranslation:
+		var translation Translation
+		if err := m.Decode(&translation); err != nil {
+			return invalid("%v", err)
+		}
+		if len(translation.Translations) == 0 {
+			return invalid("translation requires at least one translation")
+		}
+	case TypeAudio:
+		var audio Audio
+		if err := m.Decode(&audio); err != nil {
+			return invalid("%v", err)
+		}
+		if audio.Language == "" || audio.Format == "" || len(audio.Data) == 0 {
+			return invalid("audio requires language, format and data")
+		}
+	case TypePartial, TypeFinal:
+		var recognition Recognition
+		if err := m.Decode(&recognition); err != nil {
+			return invalid("%v", err)
+		}
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func message(t *testing.T, messageType MessageType, sessionID string, payload interface{}) Message {
+	m, err := NewMessage(messageType, sessionID, payload)
+	if err != nil {
+		t.Fatalf("new message: %v", err)
+	}
+	return m
+}
+
+func TestValidateClientMessage(t *testing.T) {
+	valid := []Message{
+		message(t, TypeHello, "", Hello{Client: "test"}),
+		message(t, TypeStart, "", Start{TargetLanguages: []string{"vi"}}),
+		message(t, TypeStart, "", Start{TargetLanguages: []string{"vi", "fr"}, Mode: "speech-only", AudioFormat: "ogg-opus"}),
+		message(t, TypeStop, "session-1", nil),
+		message(t, TypeConfig, "session-1", Config{Mode: "text-only"}),
+		message(t, TypeHeartbeat, "", Heartbeat{Timestamp: 1}),
+	}
+	for _, m := range valid {
+		if err := ValidateClientMessage(m); err != nil {
+			t.Errorf("%s rejected: %v", m.Type, err)
+		}
+	}
+
+	rejected := []struct {
+		name    string
+		message Message
+		code    string
+	}{
+		{"old version", Message{Version: "0", Type: TypeHello}, ErrorUnsupportedVersion},
+		{"missing version", Message{Type: TypeHello}, ErrorUnsupportedVersion},
+		{"missing type", Message{Version: Version}, ErrorInvalidMessage},
+		{"unknown type", Message{Version: Version, Type: "resume"}, ErrorInvalidMessage},
+		{"server type", message(t, TypeStarted, "session-1", Started{}), ErrorInvalidMessage},
+		{"start without payload", Message{Version: Version, Type: TypeStart}, ErrorInvalidMessage},
+		{"start with malformed payload", Message{Version: Version, Type: TypeStart, Payload: json.RawMessage(`{"targetLanguages": "vi"}`)}, ErrorInvalidMessage},
+		{"start without targets", message(t, TypeStart, "", Start{}), ErrorInvalidMessage},
+		{"start with an empty target", message(t, TypeStart, "", Start{TargetLanguages: []string{"vi", ""}}), ErrorInvalidMessage},
+		{"start with an unknown mode", message(t, TypeStart, "", Start{TargetLanguages: []string{"vi"}, Mode: "loud"}), ErrorInvalidMessage},
+		{"start with an unknown audio format", message(t, TypeStart, "", Start{TargetLanguages: []string{"vi"}, AudioFormat: "mp3"}), ErrorInvalidMessage},
+		{"config without mode", message(t, TypeConfig, "session-1", Config{}), ErrorInvalidMessage},
+		{"config with an unknown mode", message(t, TypeConfig, "session-1", Config{Mode: "loud"}), ErrorInvalidMessage},
+	}
+	for _, test := range rejected {
+		err := ValidateClientMessage(test.message)
+		var invalid *ValidationError
+		if !errors.As(err, &invalid) {
+			t.Errorf("%s: error = %v, want a validation error", test.name, err)
+			continue
+		}
+		if invalid.Code != test.code {
+			t.Errorf("%s: code = %s, want %s", test.name, invalid.Code, test.code)
+		}
+	}
+}
+
+func TestValidateServerMessage(t *testing.T) {
+	valid := []Message{
+		message(t, TypeCapabilities, "", Capabilities{Version: Version}),
+		message(t, TypeStarted, "session-1", Started{TargetLanguages: []string{"vi"}}),
+		message(t, TypeStopped, "session-1", Stopped{}),
+		message(t, TypePartial, "session-1", Recognition{Text: "hel"}),
+		message(t, TypeFinal, "session-1", Recognition{Text: "hello", Translations: map[string]string{"vi": "xin chào"}}),
+		message(t, TypeTranslation, "session-1", Translation{SourceText: "hello", Translations: map[string]string{"vi": "xin chào"}}),
+		message(t, TypeAudio, "session-1", Audio{Language: "vi", Format: "riff-16khz-16bit-mono-pcm", Data: []byte{1, 2}}),
+		ErrorMessage("", ErrorNoSession, "no session is running"),
+		message(t, TypeHeartbeat, "", Heartbeat{Timestamp: 1}),
+	}
+	for _, m := range valid {
+		if err := ValidateServerMessage(m); err != nil {
+			t.Errorf("%s rejected: %v", m.Type, err)
+		}
+	}
+
+	rejected := []struct {
+		name    string
+		message Message
+	}{
+		{"client type", message(t, TypeStart, "", Start{TargetLanguages: []string{"vi"}})},
+		{"capabilities without version", message(t, TypeCapabilities, "", Capabilities{})},
+		{"started without session", message(t, TypeStarted, "", Started{})},
+		{"final without session", message(t, TypeFinal, "", Recognition{Text: "hello"})},
+		{"translation without translations", message(t, TypeTranslation, "session-1", Translation{SourceText: "hello"})},
+		{"audio without data", message(t, TypeAudio, "session-1", Audio{Language: "vi", Format: "riff-16khz-16bit-mono-pcm"})},
+		{"error without code", message(t, TypeError, "", Error{Message: "failed"})},
+	}
+	for _, test := range rejected {
+		if err := ValidateServerMessage(test.message); err == nil {
+			t.Errorf("%s accepted", test.name)
+		}
+	}
+}
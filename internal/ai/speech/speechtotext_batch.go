@@ -0,0 +1,409 @@
+package speech
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ngothientuong/tngo-ai-svcs/pkg/httpclient"
+	"github.com/ngothientuong/tngo-ai-svcs/pkg/poller"
+)
+
+/*
+Reference: https://learn.microsoft.com/en-us/azure/ai-services/speech-service/batch-transcription
+Batch transcription works on recorded audio stored in Azure Blob Storage (content URLs or a whole container, SAS or managed identity).
+Jobs run asynchronously: submit, poll status until Succeeded or Failed, then download the result files.
+*/
+
+type BatchTranscriptionClient struct {
+	Endpoint   string
+	Key        string
+	APIVersion string
+}
+
+// Batch transcription job statuses
+const (
+	TranscriptionStatusNotStarted = "NotStarted"
+	TranscriptionStatusRunning    = "Running"
+	TranscriptionStatusSucceeded  = "Succeeded"
+	TranscriptionStatusFailed     = "Failed"
+)
+
+// Punctuation and profanity modes accepted by batch transcription
+const (
+	PunctuationModeNone                 = "None"
+	PunctuationModeDictated             = "Dictated"
+	PunctuationModeAutomatic            = "Automatic"
+	PunctuationModeDictatedAndAutomatic = "DictatedAndAutomatic"
+
+	ProfanityFilterModeNone    = "None"
+	ProfanityFilterModeMasked  = "Masked"
+	ProfanityFilterModeRemoved = "Removed"
+	ProfanityFilterModeTags    = "Tags"
+)
+
+type TranscriptionRequest struct {
+	DisplayName         string                  `json:"displayName"`
+	Description         string                  `json:"description,omitempty"`
+	Locale              string                  `json:"locale"`
+	ContentURLs         []string                `json:"contentUrls,omitempty"`
+	ContentContainerURL string                  `json:"contentContainerUrl,omitempty"`
+	Model               *EntityReference        `json:"model,omitempty"`
+	Properties          TranscriptionProperties `json:"properties"`
+}
+
+type EntityReference struct {
+	Self string `json:"self"`
+}
+
+type TranscriptionProperties struct {
+	Diarization                           *DiarizationProperties `json:"diarization,omitempty"`
+	WordLevelTimestampsEnabled            bool                   `json:"wordLevelTimestampsEnabled,omitempty"`
+	DisplayFormWordLevelTimestampsEnabled bool                   `json:"displayFormWordLevelTimestampsEnabled,omitempty"`
+	PunctuationMode                       string                 `json:"punctuationMode,omitempty"`
+	ProfanityFilterMode                   string                 `json:"profanityFilterMode,omitempty"`
+	Channels                              []int                  `json:"channels,omitempty"`
+	TimeToLiveHours                       int                    `json:"timeToLiveHours,omitempty"`
+	DestinationContainerURL               string                 `json:"destinationContainerUrl,omitempty"`
+	Error                                 *TranscriptionError    `json:"error,omitempty"`
+}
+
+type DiarizationProperties struct {
+	Enabled     bool `json:"enabled"`
+	MaxSpeakers int  `json:"maxSpeakers,omitempty"`
+}
+
+type TranscriptionError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type BatchTranscription struct {
+	Self               string                  `json:"self"`
+	DisplayName        string                  `json:"displayName"`
+	Description        string                  `json:"description"`
+	Locale             string                  `json:"locale"`
+	Status             string                  `json:"status"`
+	CreatedDateTime    string                  `json:"createdDateTime"`
+	LastActionDateTime string                  `json:"lastActionDateTime"`
+	Properties         TranscriptionProperties `json:"properties"`
+	Links              struct {
+		Files string `json:"files"`
+	} `json:"links"`
+}
+
+// ID returns the transcription identifier, the last segment of its self link
+func (t *BatchTranscription) ID() string {
+	return t.Self[strings.LastIndex(t.Self, "/")+1:]
+}
+
+type TranscriptionFile struct {
+	Self            string `json:"self"`
+	Name            string `json:"name"`
+	Kind            string `json:"kind"`
+	CreatedDateTime string `json:"createdDateTime"`
+	Properties      struct {
+		Size int64 `json:"size"`
+	} `json:"properties"`
+	Links struct {
+		ContentURL string `json:"contentUrl"`
+	} `json:"links"`
+}
+
+type ListTranscriptionFilesResponse struct {
+	Values   []TranscriptionFile `json:"values"`
+	NextLink string              `json:"@nextLink"`
+}
+
+// BatchTranscriptionResult is the content of a "Transcription" result file
+type BatchTranscriptionResult struct {
+	Source                    string `json:"source"`
+	Timestamp                 string `json:"timestamp"`
+	DurationInTicks           int64  `json:"durationInTicks"`
+	Duration                  string `json:"duration"`
+	CombinedRecognizedPhrases []struct {
+		Channel   int    `json:"channel"`
+		Lexical   string `json:"lexical"`
+		ITN       string `json:"itn"`
+		MaskedITN string `json:"maskedITN"`
+		Display   string `json:"display"`
+	} `json:"combinedRecognizedPhrases"`
+	RecognizedPhrases []RecognizedPhrase `json:"recognizedPhrases"`
+}
+
+type RecognizedPhrase struct {
+	RecognitionStatus string  `json:"recognitionStatus"`
+	Channel           int     `json:"channel"`
+	Speaker           int     `json:"speaker"`
+	Offset            string  `json:"offset"`
+	Duration          string  `json:"duration"`
+	OffsetInTicks     float64 `json:"offsetInTicks"`
+	DurationInTicks   float64 `json:"durationInTicks"`
+	NBest             []struct {
+		Confidence float64 `json:"confidence"`
+		Lexical    string  `json:"lexical"`
+		ITN        string  `json:"itn"`
+		MaskedITN  string  `json:"maskedITN"`
+		Display    string  `json:"display"`
+		Words      []struct {
+			Word            string  `json:"word"`
+			OffsetInTicks   float64 `json:"offsetInTicks"`
+			DurationInTicks float64 `json:"durationInTicks"`
+			Confidence      float64 `json:"confidence"`
+		} `json:"words"`
+	} `json:"nBest"`
+}
+
+// Transcript converts the result file into the typed transcript used by the real-time recognizers
+func (r *BatchTranscriptionResult) Transcript() *Transcript {
+	transcript := &Transcript{Duration: time.Duration(r.DurationInTicks) * ticksPerDuration}
+	for _, phrase := range r.RecognizedPhrases {
+		if phrase.RecognitionStatus != "Success" {
+			continue
+		}
+
+		segment := TranscriptSegment{
+			Offset:   time.Duration(phrase.OffsetInTicks) * ticksPerDuration,
+			Duration: time.Duration(phrase.DurationInTicks) * ticksPerDuration,
+			Channel:  phrase.Channel,
+			Speaker:  phrase.Speaker,
+		}
+		for _, nbest := range phrase.NBest {
+			entry := NBestEntry{
+				Confidence: nbest.Confidence,
+				Lexical:    nbest.Lexical,
+				ITN:        nbest.ITN,
+				MaskedITN:  nbest.MaskedITN,
+				Display:    nbest.Display,
+			}
+			for _, word := range nbest.Words {
+				entry.Words = append(entry.Words, WordTiming{
+					Word:       word.Word,
+					Offset:     time.Duration(word.OffsetInTicks) * ticksPerDuration,
+					Duration:   time.Duration(word.DurationInTicks) * ticksPerDuration,
+					Confidence: word.Confidence,
+				})
+			}
+			segment.NBest = append(segment.NBest, entry)
+		}
+		if len(segment.NBest) > 0 {
+			segment.Text = segment.NBest[0].Display
+			segment.Confidence = segment.NBest[0].Confidence
+		}
+		transcript.Segments = append(transcript.Segments, segment)
+	}
+	return transcript
+}
+
+func NewBatchTranscriptionClient(endpoint, key, apiVersion string) *BatchTranscriptionClient {
+	return &BatchTranscriptionClient{
+		Endpoint:   endpoint,
+		Key:        key,
+		APIVersion: apiVersion,
+	}
+}
+
+func (c *BatchTranscriptionClient) headers() map[string]string {
+	return map[string]string{
+		"Ocp-Apim-Subscription-Key": c.Key,
+		"Content-Type":              "application/json",
+	}
+}
+
+func (c *BatchTranscriptionClient) CreateTranscription(request TranscriptionRequest) (*BatchTranscription, error) {
+	url := fmt.Sprintf("%s/speechtotext/transcriptions:submit?api-version=%s", c.Endpoint, c.APIVersion)
+
+	if len(request.ContentURLs) == 0 && request.ContentContainerURL == "" {
+		return nil, fmt.Errorf("either content URLs or a content container URL is required")
+	}
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %v", err)
+	}
+
+	client := httpclient.NewClient()
+	resp, err := client.Post(url, bytes.NewBuffer(requestBody), c.headers(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to create transcription: %s", body)
+	}
+
+	var transcription BatchTranscription
+	err = json.NewDecoder(resp.Body).Decode(&transcription)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &transcription, nil
+}
+
+func (c *BatchTranscriptionClient) GetTranscription(transcriptionID string) (*BatchTranscription, error) {
+	url := fmt.Sprintf("%s/speechtotext/transcriptions/%s?api-version=%s", c.Endpoint, transcriptionID, c.APIVersion)
+
+	client := httpclient.NewClient()
+	resp, err := client.Get(url, c.headers(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get transcription: %s", body)
+	}
+
+	var transcription BatchTranscription
+	err = json.NewDecoder(resp.Body).Decode(&transcription)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &transcription, nil
+}
+
+func (c *BatchTranscriptionClient) DeleteTranscription(transcriptionID string) error {
+	url := fmt.Sprintf("%s/speechtotext/transcriptions/%s?api-version=%s", c.Endpoint, transcriptionID, c.APIVersion)
+
+	client := httpclient.NewClient()
+	resp, err := client.Delete(url, c.headers(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete transcription: %s", body)
+	}
+
+	return nil
+}
+
+// WaitForTranscription polls the job until it succeeds or fails
+func (c *BatchTranscriptionClient) WaitForTranscription(ctx context.Context, transcriptionID string, interval time.Duration) (*BatchTranscription, error) {
+	var transcription *BatchTranscription
+	err := poller.Poll(ctx, interval, func() (bool, error) {
+		var err error
+		transcription, err = c.GetTranscription(transcriptionID)
+		if err != nil {
+			return false, err
+		}
+		switch transcription.Status {
+		case TranscriptionStatusSucceeded:
+			return true, nil
+		case TranscriptionStatusFailed:
+			if transcription.Properties.Error != nil {
+				return false, fmt.Errorf("transcription failed: %s - %s", transcription.Properties.Error.Code, transcription.Properties.Error.Message)
+			}
+			return false, fmt.Errorf("transcription failed")
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transcription, nil
+}
+
+// ListTranscriptionFiles returns every result and report file of a job, following @nextLink
+func (c *BatchTranscriptionClient) ListTranscriptionFiles(transcriptionID string) ([]TranscriptionFile, error) {
+	url := fmt.Sprintf("%s/speechtotext/transcriptions/%s/files?api-version=%s", c.Endpoint, transcriptionID, c.APIVersion)
+
+	client := httpclient.NewClient()
+	var files []TranscriptionFile
+	for url != "" {
+		resp, err := client.Get(url, c.headers(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to send request: %v", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to list transcription files: %s", body)
+		}
+
+		var response ListTranscriptionFilesResponse
+		err = json.NewDecoder(resp.Body).Decode(&response)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %v", err)
+		}
+
+		files = append(files, response.Values...)
+		url = response.NextLink
+	}
+
+	return files, nil
+}
+
+// DownloadTranscriptionResult fetches and parses a "Transcription" result file
+func (c *BatchTranscriptionClient) DownloadTranscriptionResult(file TranscriptionFile) (*BatchTranscriptionResult, error) {
+	if file.Kind != "Transcription" {
+		return nil, fmt.Errorf("file %s is a %s file, not a transcription", file.Name, file.Kind)
+	}
+
+	// The content URL carries its own SAS token: no subscription key is sent, and the URL is never logged
+	resp, err := http.Get(file.Links.ContentURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download transcription result %s: %v", file.Name, redactURLError(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to download transcription result: %s", body)
+	}
+
+	var result BatchTranscriptionResult
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &result, nil
+}
+
+// redactURLError drops the URL, and so its SAS token, from a net/http error
+func redactURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
+
+// GetTranscriptionResults downloads every transcription result file of a finished job
+func (c *BatchTranscriptionClient) GetTranscriptionResults(transcriptionID string) ([]BatchTranscriptionResult, error) {
+	files, err := c.ListTranscriptionFiles(transcriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []BatchTranscriptionResult
+	for _, file := range files {
+		if file.Kind != "Transcription" {
+			continue
+		}
+		result, err := c.DownloadTranscriptionResult(file)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *result)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,299 @@
+package speech
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ngothientuong/tngo-ai-svcs/internal/audio/audioutil"
+)
+
+/*
+Reference: https://learn.microsoft.com/en-us/azure/ai-services/speech-service/get-started-stt-diarization
+The Go Speech SDK has no ConversationTranscriber, so conversations are transcribed through fast transcription
+with diarization enabled. Every phrase comes back with a speaker ID; adjacent phrases of the same speaker are
+merged into utterances.
+✅ TranscribeFile and TranscribeRecording upload a complete recording in one request
+✅ TranscribeStream cuts live PCM into chunks of ChunkDuration and diarizes each chunk as soon as it is complete
+Each request diarizes on its own, so in a stream the same person may get different speaker IDs in different chunks;
+longer chunks keep the labels consistent for longer, shorter ones deliver results sooner.
+*/
+
+// ConversationOptions controls diarized transcription
+type ConversationOptions struct {
+	Locales             []string      // Candidate locales, defaults to en-US
+	MaxSpeakers         int           // Upper bound of distinct speakers, defaults to 2
+	ProfanityFilterMode string        // None, Masked, Removed or Tags
+	MaxGap              time.Duration // Same-speaker segments further apart start a new utterance, defaults to 2s
+	ChunkDuration       time.Duration // Audio per request of TranscribeStream, defaults to 30s
+}
+
+type ConversationTranscriber struct {
+	client *FastTranscriptionClient
+	opts   ConversationOptions
+}
+
+// Conversation is a diarized transcript
+type Conversation struct {
+	Source     string        `json:"source,omitempty"`
+	Duration   time.Duration `json:"duration"`
+	Speakers   []int         `json:"speakers"`
+	Utterances []Utterance   `json:"utterances"`
+}
+
+// ConversationChunk is the diarized transcript of one chunk of a stream
+type ConversationChunk struct {
+	Index      int           `json:"index"`
+	Start      time.Duration `json:"start"` // Offset of the chunk in the stream
+	Duration   time.Duration `json:"duration"`
+	Utterances []Utterance   `json:"utterances"` // Times are offsets in the stream
+}
+
+// Utterance is what one speaker said without interruption
+type Utterance struct {
+	Speaker int           `json:"speaker"`
+	Start   time.Duration `json:"start"`
+	End     time.Duration `json:"end"`
+	Text    string        `json:"text"`
+}
+
+func NewConversationTranscriber(endpoint, key, region, apiVersion string, opts *ConversationOptions) *ConversationTranscriber {
+	o := ConversationOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if len(o.Locales) == 0 {
+		o.Locales = []string{"en-US"}
+	}
+	if o.MaxSpeakers <= 0 {
+		o.MaxSpeakers = 2
+	}
+	if o.MaxGap <= 0 {
+		o.MaxGap = 2 * time.Second
+	}
+	if o.ChunkDuration <= 0 {
+		o.ChunkDuration = 30 * time.Second
+	}
+
+	return &ConversationTranscriber{
+		client: NewFastTranscriptionClient(endpoint, key, region, apiVersion),
+		opts:   o,
+	}
+}
+
+// TranscribeFile transcribes a recording and labels each utterance with its speaker
+func (t *ConversationTranscriber) TranscribeFile(audioPath string) (*Conversation, error) {
+	audioFile, err := os.Open(audioPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audio file: %v", err)
+	}
+	defer audioFile.Close()
+
+	return t.TranscribeRecording(audioFile, filepath.Base(audioPath))
+}
+
+// TranscribeRecording transcribes a complete recording read from reader; name identifies the source.
+// The recording is uploaded as a whole, so the result is only available after reader reaches EOF.
+func (t *ConversationTranscriber) TranscribeRecording(reader io.Reader, name string) (*Conversation, error) {
+	result, err := t.client.Transcribe(reader, name, t.definition())
+	if err != nil {
+		return nil, err
+	}
+
+	conversation := NewConversation(result.Transcript(), t.opts.MaxGap)
+	conversation.Source = name
+	return conversation, nil
+}
+
+// TranscribeStream diarizes raw PCM in format read from reader, such as a microphone or a WAV stream after its header,
+// one chunk of ChunkDuration at a time. onChunk, when not nil, receives each chunk as soon as it is transcribed;
+// the whole conversation is returned when reader reaches EOF. Cancelling ctx stops before the next chunk.
+func (t *ConversationTranscriber) TranscribeStream(ctx context.Context, reader io.Reader, format audioutil.Format, name string, onChunk func(ConversationChunk)) (*Conversation, error) {
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+	chunkSize := int(t.opts.ChunkDuration.Seconds() * float64(format.ByteRate()))
+	chunkSize -= chunkSize % format.BytesPerFrame()
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("chunk duration %s is too short", t.opts.ChunkDuration)
+	}
+
+	buffer := make([]byte, chunkSize)
+	var segments []TranscriptSegment
+	var offset time.Duration
+	for index := 0; ; index++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		n, readErr := io.ReadFull(reader, buffer)
+		if readErr != nil && readErr != io.EOF && !errors.Is(readErr, io.ErrUnexpectedEOF) {
+			return nil, fmt.Errorf("failed to read audio stream: %v", readErr)
+		}
+		n -= n % format.BytesPerFrame()
+		if n > 0 {
+			chunk, err := t.transcribeChunk(buffer[:n], format, fmt.Sprintf("%s-%d.wav", name, index), offset)
+			if err != nil {
+				return nil, fmt.Errorf("failed to transcribe chunk %d: %v", index, err)
+			}
+			segments = append(segments, chunk...)
+
+			duration := format.Duration(int64(n))
+			if onChunk != nil {
+				onChunk(ConversationChunk{Index: index, Start: offset, Duration: duration, Utterances: MergeSpeakerSegments(chunk, t.opts.MaxGap)})
+			}
+			offset += duration
+		}
+		if readErr != nil {
+			break
+		}
+	}
+
+	conversation := NewConversation(&Transcript{Duration: offset, Segments: segments}, t.opts.MaxGap)
+	conversation.Source = name
+	return conversation, nil
+}
+
+// transcribeChunk diarizes one chunk of PCM and shifts its segments and words to the chunk's offset in the stream
+func (t *ConversationTranscriber) transcribeChunk(pcm []byte, format audioutil.Format, name string, offset time.Duration) ([]TranscriptSegment, error) {
+	wav, err := audioutil.EncodeWAV(format, pcm)
+	if err != nil {
+		return nil, err
+	}
+	result, err := t.client.Transcribe(bytes.NewReader(wav), name, t.definition())
+	if err != nil {
+		return nil, err
+	}
+
+	segments := result.Transcript().Segments
+	for i := range segments {
+		segments[i].Offset += offset
+		for j := range segments[i].NBest {
+			for k := range segments[i].NBest[j].Words {
+				segments[i].NBest[j].Words[k].Offset += offset
+			}
+		}
+	}
+	return segments, nil
+}
+
+// definition requests diarized fast transcription with the transcriber's options
+func (t *ConversationTranscriber) definition() FastTranscriptionDefinition {
+	return FastTranscriptionDefinition{
+		Locales:             t.opts.Locales,
+		Diarization:         &FastTranscriptionDiarization{Enabled: true, MaxSpeakers: t.opts.MaxSpeakers},
+		ProfanityFilterMode: t.opts.ProfanityFilterMode,
+	}
+}
+
+// NewConversation builds a conversation from diarized segments, such as batch results with diarization enabled
+func NewConversation(transcript *Transcript, maxGap time.Duration) *Conversation {
+	conversation := &Conversation{
+		Duration:   transcript.Duration,
+		Utterances: MergeSpeakerSegments(transcript.Segments, maxGap),
+	}
+
+	seen := make(map[int]bool)
+	for _, utterance := range conversation.Utterances {
+		if !seen[utterance.Speaker] {
+			seen[utterance.Speaker] = true
+			conversation.Speakers = append(conversation.Speakers, utterance.Speaker)
+		}
+	}
+	sort.Ints(conversation.Speakers)
+
+	return conversation
+}
+
+// MergeSpeakerSegments joins consecutive segments of the same speaker separated by at most maxGap
+func MergeSpeakerSegments(segments []TranscriptSegment, maxGap time.Duration) []Utterance {
+	sorted := make([]TranscriptSegment, len(segments))
+	copy(sorted, segments)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Offset < sorted[j].Offset })
+
+	var utterances []Utterance
+	for _, segment := range sorted {
+		if segment.Text == "" {
+			continue
+		}
+		end := segment.Offset + segment.Duration
+
+		if n := len(utterances); n > 0 {
+			last := &utterances[n-1]
+			if last.Speaker == segment.Speaker && segment.Offset-last.End <= maxGap {
+				last.Text += " " + segment.Text
+				if end > last.End {
+					last.End = end
+				}
+				continue
+			}
+		}
+		utterances = append(utterances, Utterance{
+			Speaker: segment.Speaker,
+			Start:   segment.Offset,
+			End:     end,
+			Text:    segment.Text,
+		})
+	}
+	return utterances
+}
+
+// SpeakerLabel returns the display name of a speaker ID
+func SpeakerLabel(speaker int) string {
+	if speaker == 0 {
+		return "Unknown speaker"
+	}
+	return fmt.Sprintf("Speaker %d", speaker)
+}
+
+// WriteJSON writes the conversation as indented JSON
+func (c *Conversation) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(c); err != nil {
+		return fmt.Errorf("failed to write conversation JSON: %v", err)
+	}
+	return nil
+}
+
+// WriteMarkdown writes the conversation as a Markdown document with one bold speaker line per utterance
+func (c *Conversation) WriteMarkdown(w io.Writer) error {
+	title := "Conversation transcript"
+	if c.Source != "" {
+		title = fmt.Sprintf("Transcript of %s", c.Source)
+	}
+	if _, err := fmt.Fprintf(w, "# %s\n\n", title); err != nil {
+		return fmt.Errorf("failed to write Markdown header: %v", err)
+	}
+
+	for _, utterance := range c.Utterances {
+		_, err := fmt.Fprintf(w, "**%s** `[%s - %s]`\n\n%s\n\n", SpeakerLabel(utterance.Speaker), formatTimestamp(utterance.Start), formatTimestamp(utterance.End), utterance.Text)
+		if err != nil {
+			return fmt.Errorf("failed to write Markdown utterance: %v", err)
+		}
+	}
+	return nil
+}
+
+// WriteText writes one paragraph per utterance, plain enough to paste into a Word document
+func (c *Conversation) WriteText(w io.Writer) error {
+	for _, utterance := range c.Utterances {
+		_, err := fmt.Fprintf(w, "[%s] %s: %s\r\n\r\n", formatTimestamp(utterance.Start), SpeakerLabel(utterance.Speaker), utterance.Text)
+		if err != nil {
+			return fmt.Errorf("failed to write utterance: %v", err)
+		}
+	}
+	return nil
+}
+
+// formatTimestamp renders hh:mm:ss
+func formatTimestamp(d time.Duration) string {
+	return formatCueTime(d, ".")[:8]
+}
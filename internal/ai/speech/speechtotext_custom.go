@@ -0,0 +1,338 @@
+package speech
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ngothientuong/tngo-ai-svcs/pkg/httpclient"
+	"github.com/ngothientuong/tngo-ai-svcs/pkg/poller"
+)
+
+/*
+Reference: https://learn.microsoft.com/en-us/azure/ai-services/speech-service/custom-speech-overview
+Custom Speech adapts a base model to domain vocabulary, then hosts it on an endpoint the recognizers can target:
+✅ Datasets: plain text, structured text (markdown), pronunciation and audio + human-labeled transcript,
+   from a URL or uploaded from a local file
+✅ Models trained from a base model and datasets
+✅ Evaluations comparing the word error rate (WER) of two models on an audio + transcript dataset
+✅ Endpoints deploying a model; pass the endpoint ID as RecognizerOptions.EndpointID
+Datasets, models, evaluations and endpoints run asynchronously and report the same statuses as batch transcriptions.
+*/
+
+type CustomSpeechClient struct {
+	Endpoint   string
+	Key        string
+	Region     string
+	APIVersion string
+}
+
+// Dataset kinds
+const (
+	DatasetKindLanguage         = "Language"         // Plain text sentences
+	DatasetKindLanguageMarkdown = "LanguageMarkdown" // Structured text with lists and patterns
+	DatasetKindPronunciation    = "Pronunciation"    // Word and spoken form pairs
+	DatasetKindAcoustic         = "Acoustic"         // Zipped audio with a human-labeled transcript
+	DatasetKindAudioFiles       = "AudioFiles"       // Zipped audio without transcript, for inspection only
+)
+
+// DefaultCustomSpeechPollInterval suits dataset processing and training, which take minutes to hours
+const DefaultCustomSpeechPollInterval = 30 * time.Second
+
+type DatasetRequest struct {
+	DisplayName string `json:"displayName"`
+	Description string `json:"description,omitempty"`
+	Locale      string `json:"locale"`
+	Kind        string `json:"kind"`
+	ContentURL  string `json:"contentUrl,omitempty"` // Public or SAS URL; not used by UploadDataset
+}
+
+type CustomSpeechDataset struct {
+	Self               string `json:"self"`
+	DisplayName        string `json:"displayName"`
+	Description        string `json:"description"`
+	Locale             string `json:"locale"`
+	Kind               string `json:"kind"`
+	ContentURL         string `json:"contentUrl"`
+	Status             string `json:"status"`
+	CreatedDateTime    string `json:"createdDateTime"`
+	LastActionDateTime string `json:"lastActionDateTime"`
+	Properties         struct {
+		AcceptedLineCount int                 `json:"acceptedLineCount"`
+		RejectedLineCount int                 `json:"rejectedLineCount"`
+		Error             *TranscriptionError `json:"error,omitempty"`
+	} `json:"properties"`
+}
+
+// ID returns the dataset identifier, the last segment of its self link
+func (d *CustomSpeechDataset) ID() string {
+	return entityID(d.Self)
+}
+
+// Reference returns the reference used to train or evaluate with the dataset
+func (d *CustomSpeechDataset) Reference() EntityReference {
+	return EntityReference{Self: d.Self}
+}
+
+// customSpeechList is one page of a Custom Speech list response
+type customSpeechList[T any] struct {
+	Values   []T    `json:"values"`
+	NextLink string `json:"@nextLink"`
+}
+
+func NewCustomSpeechClient(endpoint, key, region, apiVersion string) *CustomSpeechClient {
+	return &CustomSpeechClient{
+		Endpoint:   endpoint,
+		Key:        key,
+		Region:     region,
+		APIVersion: apiVersion,
+	}
+}
+
+func (c *CustomSpeechClient) headers() map[string]string {
+	return map[string]string{
+		"Ocp-Apim-Subscription-Key": c.Key,
+		"Content-Type":              "application/json",
+	}
+}
+
+func (c *CustomSpeechClient) url(path string) string {
+	return fmt.Sprintf("%s/speechtotext/%s?api-version=%s", c.Endpoint, path, c.APIVersion)
+}
+
+// CreateDataset imports a dataset from request.ContentURL
+func (c *CustomSpeechClient) CreateDataset(request DatasetRequest) (*CustomSpeechDataset, error) {
+	if request.ContentURL == "" {
+		return nil, fmt.Errorf("a content URL is required")
+	}
+	return customSpeechCreate[CustomSpeechDataset](c, "datasets", request, "dataset")
+}
+
+// UploadDataset uploads a local file: a .txt or .md file for language datasets, a .zip for audio datasets
+func (c *CustomSpeechClient) UploadDataset(request DatasetRequest, filePath string) (*CustomSpeechDataset, error) {
+	var requestBody bytes.Buffer
+	form := multipart.NewWriter(&requestBody)
+	fields := map[string]string{
+		"displayName": request.DisplayName,
+		"description": request.Description,
+		"locale":      request.Locale,
+		"kind":        request.Kind,
+	}
+	for name, value := range fields {
+		if err := form.WriteField(name, value); err != nil {
+			return nil, fmt.Errorf("failed to write form field %s: %v", name, err)
+		}
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dataset file: %v", err)
+	}
+	defer file.Close()
+	part, err := form.CreateFormFile("data", filepath.Base(filePath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to add dataset file %s: %v", filePath, err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return nil, fmt.Errorf("failed to read dataset file %s: %v", filePath, err)
+	}
+	if err := form.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close form: %v", err)
+	}
+
+	client := httpclient.NewClient()
+	headers := map[string]string{
+		"Ocp-Apim-Subscription-Key": c.Key,
+		"Content-Type":              form.FormDataContentType(),
+	}
+
+	resp, err := client.Post(c.url("datasets:upload"), &requestBody, headers, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusAccepted {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to upload dataset: %s", body)
+	}
+
+	var dataset CustomSpeechDataset
+	err = json.NewDecoder(resp.Body).Decode(&dataset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &dataset, nil
+}
+
+func (c *CustomSpeechClient) GetDataset(datasetID string) (*CustomSpeechDataset, error) {
+	return customSpeechGet[CustomSpeechDataset](c, "datasets/"+datasetID, "dataset")
+}
+
+func (c *CustomSpeechClient) DeleteDataset(datasetID string) error {
+	return customSpeechDelete(c, "datasets/"+datasetID, "dataset")
+}
+
+// ListDatasets returns the datasets, only those of a kind when kind is not empty
+func (c *CustomSpeechClient) ListDatasets(kind string) ([]CustomSpeechDataset, error) {
+	filter := ""
+	if kind != "" {
+		filter = fmt.Sprintf("kind eq '%s'", kind)
+	}
+	return customSpeechListAll[CustomSpeechDataset](c, "datasets", filter, "datasets")
+}
+
+// WaitForDataset polls the dataset until it is processed
+func (c *CustomSpeechClient) WaitForDataset(ctx context.Context, datasetID string, interval time.Duration) (*CustomSpeechDataset, error) {
+	var dataset *CustomSpeechDataset
+	err := waitForCustomSpeech(ctx, interval, "dataset "+datasetID, func() (string, *TranscriptionError, error) {
+		var err error
+		dataset, err = c.GetDataset(datasetID)
+		if err != nil {
+			return "", nil, err
+		}
+		return dataset.Status, dataset.Properties.Error, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dataset, nil
+}
+
+// entityID returns the last segment of a self link
+func entityID(self string) string {
+	return self[strings.LastIndex(self, "/")+1:]
+}
+
+func customSpeechCreate[T any](c *CustomSpeechClient, path string, request any, resource string) (*T, error) {
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %v", err)
+	}
+
+	client := httpclient.NewClient()
+	resp, err := client.Post(c.url(path), bytes.NewBuffer(requestBody), c.headers(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to create %s: %s", resource, body)
+	}
+
+	var created T
+	err = json.NewDecoder(resp.Body).Decode(&created)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &created, nil
+}
+
+func customSpeechGet[T any](c *CustomSpeechClient, path, resource string) (*T, error) {
+	client := httpclient.NewClient()
+	resp, err := client.Get(c.url(path), c.headers(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get %s: %s", resource, body)
+	}
+
+	var entity T
+	err = json.NewDecoder(resp.Body).Decode(&entity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &entity, nil
+}
+
+func customSpeechDelete(c *CustomSpeechClient, path, resource string) error {
+	client := httpclient.NewClient()
+	resp, err := client.Delete(c.url(path), c.headers(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete %s: %s", resource, body)
+	}
+
+	return nil
+}
+
+// customSpeechListAll returns every entity of a collection matching the OData filter, following @nextLink
+func customSpeechListAll[T any](c *CustomSpeechClient, path, filter, resource string) ([]T, error) {
+	listURL := c.url(path)
+	if filter != "" {
+		listURL += "&filter=" + url.QueryEscape(filter)
+	}
+
+	client := httpclient.NewClient()
+	var entities []T
+	for listURL != "" {
+		resp, err := client.Get(listURL, c.headers(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to send request: %v", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to list %s: %s", resource, body)
+		}
+
+		var page customSpeechList[T]
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %v", err)
+		}
+
+		entities = append(entities, page.Values...)
+		listURL = page.NextLink
+	}
+
+	return entities, nil
+}
+
+// waitForCustomSpeech polls get until the entity succeeds, and fails with the service error when it fails
+func waitForCustomSpeech(ctx context.Context, interval time.Duration, resource string, get func() (string, *TranscriptionError, error)) error {
+	if interval <= 0 {
+		interval = DefaultCustomSpeechPollInterval
+	}
+	return poller.Poll(ctx, interval, func() (bool, error) {
+		status, failure, err := get()
+		if err != nil {
+			return false, err
+		}
+		switch status {
+		case TranscriptionStatusSucceeded:
+			return true, nil
+		case TranscriptionStatusFailed:
+			if failure != nil {
+				return false, fmt.Errorf("%s failed: %s - %s", resource, failure.Code, failure.Message)
+			}
+			return false, fmt.Errorf("%s failed", resource)
+		}
+		return false, nil
+	})
+}
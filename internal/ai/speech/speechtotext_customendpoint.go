@@ -0,0 +1,82 @@
+package speech
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+/*
+Reference: https://learn.microsoft.com/en-us/azure/ai-services/speech-service/how-to-custom-speech-deploy-model
+An endpoint hosts a custom model for real-time recognition:
+✅ Deploy a model, then get, list and delete endpoints, and poll deployment until it succeeds or fails
+Recognize with the endpoint by setting RecognizerOptions.EndpointID to its ID.
+*/
+
+type CustomSpeechEndpointRequest struct {
+	DisplayName string          `json:"displayName"`
+	Description string          `json:"description,omitempty"`
+	Locale      string          `json:"locale"`
+	Model       EntityReference `json:"model"`
+	Properties  struct {
+		LoggingEnabled bool `json:"loggingEnabled"` // Keep audio and transcripts sent to the endpoint
+	} `json:"properties"`
+}
+
+type CustomSpeechEndpoint struct {
+	Self               string          `json:"self"`
+	DisplayName        string          `json:"displayName"`
+	Description        string          `json:"description"`
+	Locale             string          `json:"locale"`
+	Model              EntityReference `json:"model"`
+	Status             string          `json:"status"`
+	CreatedDateTime    string          `json:"createdDateTime"`
+	LastActionDateTime string          `json:"lastActionDateTime"`
+	Properties         struct {
+		LoggingEnabled bool                `json:"loggingEnabled"`
+		Error          *TranscriptionError `json:"error,omitempty"`
+	} `json:"properties"`
+}
+
+// ID returns the endpoint identifier, the last segment of its self link, used as RecognizerOptions.EndpointID
+func (e *CustomSpeechEndpoint) ID() string {
+	return entityID(e.Self)
+}
+
+// CreateEndpoint deploys request.Model
+func (c *CustomSpeechClient) CreateEndpoint(request CustomSpeechEndpointRequest) (*CustomSpeechEndpoint, error) {
+	if request.Model.Self == "" {
+		return nil, fmt.Errorf("a model is required")
+	}
+	return customSpeechCreate[CustomSpeechEndpoint](c, "endpoints", request, "endpoint")
+}
+
+func (c *CustomSpeechClient) GetEndpoint(endpointID string) (*CustomSpeechEndpoint, error) {
+	return customSpeechGet[CustomSpeechEndpoint](c, "endpoints/"+endpointID, "endpoint")
+}
+
+func (c *CustomSpeechClient) DeleteEndpoint(endpointID string) error {
+	return customSpeechDelete(c, "endpoints/"+endpointID, "endpoint")
+}
+
+func (c *CustomSpeechClient) ListEndpoints() ([]CustomSpeechEndpoint, error) {
+	return customSpeechListAll[CustomSpeechEndpoint](c, "endpoints", "", "endpoints")
+}
+
+// WaitForEndpoint polls the endpoint until deployment succeeds or fails
+func (c *CustomSpeechClient) WaitForEndpoint(ctx context.Context, endpointID string, interval time.Duration) (*CustomSpeechEndpoint, error) {
+	var endpoint *CustomSpeechEndpoint
+	err := waitForCustomSpeech(ctx, interval, "endpoint "+endpointID, func() (string, *TranscriptionError, error) {
+		var err error
+		endpoint, err = c.GetEndpoint(endpointID)
+		if err != nil {
+			return "", nil, err
+		}
+		return endpoint.Status, endpoint.Properties.Error, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return endpoint, nil
+}
@@ -0,0 +1,116 @@
+package speech
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+/*
+Reference: https://learn.microsoft.com/en-us/azure/ai-services/speech-service/how-to-custom-speech-evaluate-data
+An evaluation transcribes an audio + human-labeled transcript dataset with two models and compares the results:
+✅ Create, get, list and delete evaluations, and poll them until they finish
+✅ Word and sentence error rates of both models, with the insertion, deletion and substitution counts behind them
+Evaluate a custom model against its base model on audio that was not used for training.
+*/
+
+type EvaluationRequest struct {
+	DisplayName string          `json:"displayName"`
+	Description string          `json:"description,omitempty"`
+	Locale      string          `json:"locale"`
+	Model1      EntityReference `json:"model1"`
+	Model2      EntityReference `json:"model2"`
+	Dataset     EntityReference `json:"dataset"` // An Acoustic dataset
+}
+
+// EvaluationProperties holds the results of an evaluation; error rates are percentages
+type EvaluationProperties struct {
+	WordErrorRate1         float64             `json:"wordErrorRate1"`
+	WordErrorRate2         float64             `json:"wordErrorRate2"`
+	SentenceErrorRate1     float64             `json:"sentenceErrorRate1"`
+	SentenceErrorRate2     float64             `json:"sentenceErrorRate2"`
+	WordCount1             int                 `json:"wordCount1"`
+	WordCount2             int                 `json:"wordCount2"`
+	SentenceCount1         int                 `json:"sentenceCount1"`
+	SentenceCount2         int                 `json:"sentenceCount2"`
+	CorrectWordCount1      int                 `json:"correctWordCount1"`
+	CorrectWordCount2      int                 `json:"correctWordCount2"`
+	WordSubstitutionCount1 int                 `json:"wordSubstitutionCount1"`
+	WordSubstitutionCount2 int                 `json:"wordSubstitutionCount2"`
+	WordDeletionCount1     int                 `json:"wordDeletionCount1"`
+	WordDeletionCount2     int                 `json:"wordDeletionCount2"`
+	WordInsertionCount1    int                 `json:"wordInsertionCount1"`
+	WordInsertionCount2    int                 `json:"wordInsertionCount2"`
+	SentenceErrorCount1    int                 `json:"sentenceErrorCount1,omitempty"`
+	SentenceErrorCount2    int                 `json:"sentenceErrorCount2,omitempty"`
+	Error                  *TranscriptionError `json:"error,omitempty"`
+}
+
+type CustomSpeechEvaluation struct {
+	Self               string               `json:"self"`
+	DisplayName        string               `json:"displayName"`
+	Description        string               `json:"description"`
+	Locale             string               `json:"locale"`
+	Model1             EntityReference      `json:"model1"`
+	Model2             EntityReference      `json:"model2"`
+	Dataset            EntityReference      `json:"dataset"`
+	Status             string               `json:"status"`
+	CreatedDateTime    string               `json:"createdDateTime"`
+	LastActionDateTime string               `json:"lastActionDateTime"`
+	Properties         EvaluationProperties `json:"properties"`
+}
+
+// ID returns the evaluation identifier, the last segment of its self link
+func (e *CustomSpeechEvaluation) ID() string {
+	return entityID(e.Self)
+}
+
+// WERImprovement returns how many WER points model 2 gains over model 1; it is negative when model 2 is worse
+func (e *CustomSpeechEvaluation) WERImprovement() float64 {
+	return e.Properties.WordErrorRate1 - e.Properties.WordErrorRate2
+}
+
+// Summary describes the error rates of both models in one line
+func (e *CustomSpeechEvaluation) Summary() string {
+	p := e.Properties
+	return fmt.Sprintf("WER %.2f%% -> %.2f%% (%+.2f points), SER %.2f%% -> %.2f%%, %d words",
+		p.WordErrorRate1, p.WordErrorRate2, -e.WERImprovement(), p.SentenceErrorRate1, p.SentenceErrorRate2, p.WordCount1)
+}
+
+// CreateEvaluation starts transcribing request.Dataset with both models
+func (c *CustomSpeechClient) CreateEvaluation(request EvaluationRequest) (*CustomSpeechEvaluation, error) {
+	if request.Model1.Self == "" || request.Model2.Self == "" || request.Dataset.Self == "" {
+		return nil, fmt.Errorf("two models and a dataset are required")
+	}
+	return customSpeechCreate[CustomSpeechEvaluation](c, "evaluations", request, "evaluation")
+}
+
+func (c *CustomSpeechClient) GetEvaluation(evaluationID string) (*CustomSpeechEvaluation, error) {
+	return customSpeechGet[CustomSpeechEvaluation](c, "evaluations/"+evaluationID, "evaluation")
+}
+
+func (c *CustomSpeechClient) DeleteEvaluation(evaluationID string) error {
+	return customSpeechDelete(c, "evaluations/"+evaluationID, "evaluation")
+}
+
+func (c *CustomSpeechClient) ListEvaluations() ([]CustomSpeechEvaluation, error) {
+	return customSpeechListAll[CustomSpeechEvaluation](c, "evaluations", "", "evaluations")
+}
+
+// WaitForEvaluation polls the evaluation until both models have transcribed the dataset
+func (c *CustomSpeechClient) WaitForEvaluation(ctx context.Context, evaluationID string, interval time.Duration) (*CustomSpeechEvaluation, error) {
+	var evaluation *CustomSpeechEvaluation
+	err := waitForCustomSpeech(ctx, interval, "evaluation "+evaluationID, func() (string, *TranscriptionError, error) {
+		var err error
+		evaluation, err = c.GetEvaluation(evaluationID)
+		if err != nil {
+			return "", nil, err
+		}
+		return evaluation.Status, evaluation.Properties.Error, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return evaluation, nil
+}
@@ -0,0 +1,155 @@
+package speech
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+/*
+Reference: https://learn.microsoft.com/en-us/azure/ai-services/speech-service/how-to-custom-speech-train-model
+A custom model is trained from a base model with one or more datasets of the same locale:
+✅ List the base models of a locale and pick the latest one that supports adaptation
+✅ Train, get, list and delete custom models, and poll training until it succeeds or fails
+Every model expires: DeprecationDates tells when it can no longer be trained from or used for transcription.
+*/
+
+type CustomSpeechModelRequest struct {
+	DisplayName string            `json:"displayName"`
+	Description string            `json:"description,omitempty"`
+	Locale      string            `json:"locale"`
+	BaseModel   *EntityReference  `json:"baseModel,omitempty"` // The latest base model of the locale when nil
+	Datasets    []EntityReference `json:"datasets"`
+}
+
+type CustomSpeechModel struct {
+	Self               string            `json:"self"`
+	DisplayName        string            `json:"displayName"`
+	Description        string            `json:"description"`
+	Locale             string            `json:"locale"`
+	BaseModel          *EntityReference  `json:"baseModel,omitempty"` // Not set on base models
+	Datasets           []EntityReference `json:"datasets,omitempty"`
+	Status             string            `json:"status"`
+	CreatedDateTime    string            `json:"createdDateTime"`
+	LastActionDateTime string            `json:"lastActionDateTime"`
+	Properties         struct {
+		DeprecationDates struct {
+			AdaptationDateTime    string `json:"adaptationDateTime,omitempty"`
+			TranscriptionDateTime string `json:"transcriptionDateTime,omitempty"`
+		} `json:"deprecationDates"`
+		Features struct {
+			SupportsAdaptationsWith []string `json:"supportsAdaptationsWith,omitempty"` // Dataset kinds accepted for training
+			SupportsTranscriptions  bool     `json:"supportsTranscriptions"`
+		} `json:"features"`
+		Error *TranscriptionError `json:"error,omitempty"`
+	} `json:"properties"`
+}
+
+// ID returns the model identifier, the last segment of its self link
+func (m *CustomSpeechModel) ID() string {
+	return entityID(m.Self)
+}
+
+// Reference returns the reference used to train from, evaluate or deploy the model
+func (m *CustomSpeechModel) Reference() EntityReference {
+	return EntityReference{Self: m.Self}
+}
+
+// SupportsAdaptation reports whether the model can be trained with datasets of a kind, or with any dataset when kind is empty
+func (m *CustomSpeechModel) SupportsAdaptation(kind string) bool {
+	adaptations := m.Properties.Features.SupportsAdaptationsWith
+	if kind == "" {
+		return len(adaptations) > 0
+	}
+	return slices.Contains(adaptations, kind)
+}
+
+// ListBaseModels returns the base models of a locale, or of every locale when locale is empty
+func (c *CustomSpeechClient) ListBaseModels(locale string) ([]CustomSpeechModel, error) {
+	filter := ""
+	if locale != "" {
+		filter = fmt.Sprintf("locale eq '%s'", locale)
+	}
+	return customSpeechListAll[CustomSpeechModel](c, "models/base", filter, "base models")
+}
+
+func (c *CustomSpeechClient) GetBaseModel(modelID string) (*CustomSpeechModel, error) {
+	return customSpeechGet[CustomSpeechModel](c, "models/base/"+modelID, "base model")
+}
+
+// LatestBaseModel returns the most recent base model of a locale that can be trained with datasets of a kind
+func (c *CustomSpeechClient) LatestBaseModel(locale, kind string) (*CustomSpeechModel, error) {
+	baseModels, err := c.ListBaseModels(locale)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *CustomSpeechModel
+	for i := range baseModels {
+		baseModel := &baseModels[i]
+		if !baseModel.SupportsAdaptation(kind) {
+			continue
+		}
+		// RFC 3339 timestamps in UTC sort as strings
+		if latest == nil || baseModel.CreatedDateTime > latest.CreatedDateTime {
+			latest = baseModel
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("no base model of %s supports adaptation", locale)
+	}
+
+	return latest, nil
+}
+
+// TrainModel starts training a custom model from request.BaseModel and request.Datasets
+func (c *CustomSpeechClient) TrainModel(request CustomSpeechModelRequest) (*CustomSpeechModel, error) {
+	if len(request.Datasets) == 0 {
+		return nil, fmt.Errorf("at least one dataset is required")
+	}
+	if request.BaseModel == nil {
+		baseModel, err := c.LatestBaseModel(request.Locale, "")
+		if err != nil {
+			return nil, err
+		}
+		reference := baseModel.Reference()
+		request.BaseModel = &reference
+	}
+	return customSpeechCreate[CustomSpeechModel](c, "models", request, "model")
+}
+
+func (c *CustomSpeechClient) GetModel(modelID string) (*CustomSpeechModel, error) {
+	return customSpeechGet[CustomSpeechModel](c, "models/"+modelID, "model")
+}
+
+func (c *CustomSpeechClient) DeleteModel(modelID string) error {
+	return customSpeechDelete(c, "models/"+modelID, "model")
+}
+
+// ListModels returns the custom models, only those of a locale when locale is not empty
+func (c *CustomSpeechClient) ListModels(locale string) ([]CustomSpeechModel, error) {
+	filter := ""
+	if locale != "" {
+		filter = fmt.Sprintf("locale eq '%s'", locale)
+	}
+	return customSpeechListAll[CustomSpeechModel](c, "models", filter, "models")
+}
+
+// WaitForModel polls the model until training succeeds or fails
+func (c *CustomSpeechClient) WaitForModel(ctx context.Context, modelID string, interval time.Duration) (*CustomSpeechModel, error) {
+	var model *CustomSpeechModel
+	err := waitForCustomSpeech(ctx, interval, "model "+modelID, func() (string, *TranscriptionError, error) {
+		var err error
+		model, err = c.GetModel(modelID)
+		if err != nil {
+			return "", nil, err
+		}
+		return model.Status, model.Properties.Error, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
@@ -0,0 +1,196 @@
+package speech
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ngothientuong/tngo-ai-svcs/pkg/aitoken"
+	"github.com/ngothientuong/tngo-ai-svcs/pkg/httpclient"
+)
+
+/*
+Reference: https://learn.microsoft.com/en-us/azure/ai-services/speech-service/fast-transcription-create
+Fast transcription returns the transcript of a file synchronously, faster than real time.
+🛠 Limits
+✅ Audio shorter than 2 hours and smaller than 300 MB
+✅ WAV, MP3, OPUS/OGG, FLAC, WMA, AAC, ALAW, MULAW, AMR, WebM, SPEEX
+*/
+
+type FastTranscriptionClient struct {
+	Endpoint   string
+	Key        string
+	APIVersion string
+}
+
+// FastTranscriptionDefinition is the "definition" part of a fast transcription request
+type FastTranscriptionDefinition struct {
+	Locales             []string                      `json:"locales,omitempty"` // Empty or several locales enable language identification
+	Diarization         *FastTranscriptionDiarization `json:"diarization,omitempty"`
+	Channels            []int                         `json:"channels,omitempty"` // Stereo channels to transcribe separately, such as [0, 1]
+	ProfanityFilterMode string                        `json:"profanityFilterMode,omitempty"`
+}
+
+type FastTranscriptionDiarization struct {
+	Enabled     bool `json:"enabled"`
+	MaxSpeakers int  `json:"maxSpeakers,omitempty"`
+}
+
+type FastTranscriptionResult struct {
+	DurationMilliseconds int64 `json:"durationMilliseconds"`
+	CombinedPhrases      []struct {
+		Channel int    `json:"channel"`
+		Text    string `json:"text"`
+	} `json:"combinedPhrases"`
+	Phrases []FastTranscriptionPhrase `json:"phrases"`
+}
+
+type FastTranscriptionPhrase struct {
+	Channel              int     `json:"channel"`
+	Speaker              int     `json:"speaker"`
+	OffsetMilliseconds   int64   `json:"offsetMilliseconds"`
+	DurationMilliseconds int64   `json:"durationMilliseconds"`
+	Text                 string  `json:"text"`
+	Locale               string  `json:"locale"`
+	Confidence           float64 `json:"confidence"`
+	Words                []struct {
+		Text                 string `json:"text"`
+		OffsetMilliseconds   int64  `json:"offsetMilliseconds"`
+		DurationMilliseconds int64  `json:"durationMilliseconds"`
+	} `json:"words"`
+}
+
+// Text returns the combined text of all channels
+func (r *FastTranscriptionResult) Text() string {
+	var buf bytes.Buffer
+	for i, phrase := range r.CombinedPhrases {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString(phrase.Text)
+	}
+	return buf.String()
+}
+
+// Transcript converts the phrases into the typed transcript used by the other recognizers
+func (r *FastTranscriptionResult) Transcript() *Transcript {
+	transcript := &Transcript{Duration: time.Duration(r.DurationMilliseconds) * time.Millisecond}
+	for _, phrase := range r.Phrases {
+		entry := NBestEntry{
+			Confidence: phrase.Confidence,
+			Display:    phrase.Text,
+		}
+		for _, word := range phrase.Words {
+			entry.Words = append(entry.Words, WordTiming{
+				Word:     word.Text,
+				Offset:   time.Duration(word.OffsetMilliseconds) * time.Millisecond,
+				Duration: time.Duration(word.DurationMilliseconds) * time.Millisecond,
+			})
+		}
+		transcript.Segments = append(transcript.Segments, TranscriptSegment{
+			Text:       phrase.Text,
+			Offset:     time.Duration(phrase.OffsetMilliseconds) * time.Millisecond,
+			Duration:   time.Duration(phrase.DurationMilliseconds) * time.Millisecond,
+			Confidence: phrase.Confidence,
+			Channel:    phrase.Channel,
+			Speaker:    phrase.Speaker,
+			NBest:      []NBestEntry{entry},
+		})
+	}
+	return transcript
+}
+
+// NewFastTranscriptionClient creates a client; an empty endpoint defaults to the regional endpoint of region
+func NewFastTranscriptionClient(endpoint, key, region, apiVersion string) *FastTranscriptionClient {
+	if endpoint == "" && region != "" {
+		endpoint = fmt.Sprintf("https://%s.api.cognitive.microsoft.com", region)
+	}
+	return &FastTranscriptionClient{
+		Endpoint:   endpoint,
+		Key:        key,
+		APIVersion: apiVersion,
+	}
+}
+
+// TranscribeFile uploads an audio file and returns its transcription
+func (c *FastTranscriptionClient) TranscribeFile(audioPath string, definition FastTranscriptionDefinition) (*FastTranscriptionResult, error) {
+	audioFile, err := os.Open(audioPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audio file: %v", err)
+	}
+	defer audioFile.Close()
+
+	return c.Transcribe(audioFile, filepath.Base(audioPath), definition)
+}
+
+// Transcribe uploads audio read from reader; fileName is sent as the part's file name
+func (c *FastTranscriptionClient) Transcribe(reader io.Reader, fileName string, definition FastTranscriptionDefinition) (*FastTranscriptionResult, error) {
+	url := fmt.Sprintf("%s/speechtotext/transcriptions:transcribe?api-version=%s", c.Endpoint, c.APIVersion)
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	// Add audio file
+	part, err := writer.CreatePart(map[string][]string{
+		"Content-Disposition": {fmt.Sprintf(`form-data; name="audio"; filename="%s"`, fileName)},
+		"Content-Type":        {"application/octet-stream"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create form file: %v", err)
+	}
+	_, err = io.Copy(part, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy audio: %v", err)
+	}
+
+	// Add definition
+	definitionJSON, err := json.Marshal(definition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal definition: %v", err)
+	}
+	err = writer.WriteField("definition", string(definitionJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to write definition: %v", err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to close writer: %v", err)
+	}
+
+	token, err := aitoken.GetToken(c.Endpoint, c.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token: %v", err)
+	}
+
+	client := httpclient.NewClient()
+	headers := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  writer.FormDataContentType(),
+	}
+
+	resp, err := client.Post(url, body, headers, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to transcribe audio: %s", bodyBytes)
+	}
+
+	var result FastTranscriptionResult
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &result, nil
+}
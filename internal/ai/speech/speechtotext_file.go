@@ -0,0 +1,133 @@
+package speech
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/audio"
+	"github.com/ngothientuong/tngo-ai-svcs/internal/audio/audioutil"
+)
+
+// AudioInputFormat identifies the encoding of audio handed to the recognizers
+type AudioInputFormat string
+
+/*
+Reference: https://learn.microsoft.com/en-us/azure/ai-services/speech-service/how-to-use-codec-compressed-audio-input-streams
+WAV and raw PCM are read directly. Compressed formats are decoded by the Speech SDK and require GStreamer on Linux.
+*/
+const (
+	AudioInputFormatWAV     AudioInputFormat = "wav"
+	AudioInputFormatPCM     AudioInputFormat = "pcm" // Raw 16 kHz, 16-bit, mono PCM
+	AudioInputFormatMP3     AudioInputFormat = "mp3"
+	AudioInputFormatOggOpus AudioInputFormat = "ogg-opus"
+	AudioInputFormatFLAC    AudioInputFormat = "flac"
+	AudioInputFormatALaw    AudioInputFormat = "alaw"
+	AudioInputFormatMuLaw   AudioInputFormat = "mulaw"
+	AudioInputFormatAny     AudioInputFormat = "any"
+)
+
+var compressedFormats = map[AudioInputFormat]audio.AudioStreamContainerFormat{
+	AudioInputFormatMP3:     audio.MP3,
+	AudioInputFormatOggOpus: audio.OGGOPUS,
+	AudioInputFormatFLAC:    audio.FLAC,
+	AudioInputFormatALaw:    audio.ALAW,
+	AudioInputFormatMuLaw:   audio.MULAW,
+	AudioInputFormatAny:     audio.ANY,
+}
+
+// AudioInputFormatFromPath guesses the audio format from a file extension
+func AudioInputFormatFromPath(path string) AudioInputFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".wav":
+		return AudioInputFormatWAV
+	case ".pcm", ".raw":
+		return AudioInputFormatPCM
+	case ".mp3":
+		return AudioInputFormatMP3
+	case ".ogg", ".opus":
+		return AudioInputFormatOggOpus
+	case ".flac":
+		return AudioInputFormatFLAC
+	case ".alaw":
+		return AudioInputFormatALaw
+	case ".mulaw", ".ulaw":
+		return AudioInputFormatMuLaw
+	default:
+		return AudioInputFormatAny
+	}
+}
+
+// TranscribeFile transcribes an audio file and returns the final results as a transcript
+func TranscribeFile(subscriptionKey, region, filePath string, opts *RecognizerOptions) (*Transcript, error) {
+	return transcribe(subscriptionKey, region, FileInput(filePath), opts)
+}
+
+// TranscribeReader transcribes audio read from reader until it reaches EOF
+func TranscribeReader(subscriptionKey, region string, reader io.Reader, format AudioInputFormat, opts *RecognizerOptions) (*Transcript, error) {
+	return transcribe(subscriptionKey, region, ReaderInput(reader, format), opts)
+}
+
+// transcribe runs a recognition session until the audio ends and collects the final results
+func transcribe(subscriptionKey, region string, input AudioInput, opts *RecognizerOptions) (*Transcript, error) {
+	recognizer, err := NewRecognizer(subscriptionKey, region, input, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer recognizer.Stop()
+
+	if err := recognizer.Start(context.Background()); err != nil {
+		return nil, err
+	}
+
+	transcript := &Transcript{}
+	for event := range recognizer.Final() {
+		transcript.Segments = append(transcript.Segments, event.Segment)
+		if end := event.Segment.Offset + event.Segment.Duration; end > transcript.Duration {
+			transcript.Duration = end
+		}
+	}
+
+	if err := recognizer.Wait(); err != nil {
+		return nil, err
+	}
+
+	return transcript, nil
+}
+
+// newAudioStreamFormat picks the push stream format, consuming the WAV header when present;
+// the PCM layout of the stream is returned for PCM and WAV input
+func newAudioStreamFormat(reader *bufio.Reader, format AudioInputFormat) (*audio.AudioStreamFormat, *audioutil.Format, error) {
+	switch format {
+	case AudioInputFormatWAV:
+		header, err := audioutil.ReadWAVHeader(reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		streamFormat, err := audio.GetWaveFormatPCM(uint32(header.SampleRate), uint8(header.BitsPerSample), uint8(header.Channels))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create audio format: %v", err)
+		}
+		return streamFormat, &header.Format, nil
+	case AudioInputFormatPCM:
+		streamFormat, err := audio.GetDefaultInputFormat()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get default audio format: %v", err)
+		}
+		pcmFormat := audioutil.SpeechInputFormat
+		return streamFormat, &pcmFormat, nil
+	}
+
+	containerFormat, ok := compressedFormats[format]
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported audio format: %s", format)
+	}
+	streamFormat, err := audio.GetCompressedFormat(containerFormat)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create compressed audio format: %v", err)
+	}
+	return streamFormat, nil, nil
+}
@@ -0,0 +1,329 @@
+package speech
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+/*
+Reference: https://learn.microsoft.com/en-us/azure/ai-services/speech-service/pattern-matching-overview
+The Go Speech SDK has no IntentRecognizer, so patterns are matched locally against final results.
+Pattern syntax follows the SDK's pattern matching model:
+✅ {entity} or {entity:role} captures an entity, reported under the role when one is given
+✅ (Bring | Take) is a required group, one alternative must be spoken
+✅ [to | the] is an optional group
+Matching ignores case and punctuation. Patterns are tried in registration order and the first match wins.
+*/
+
+// EntityType selects how an entity's captured text is validated
+type EntityType string
+
+const (
+	EntityAny     EntityType = "Any"     // Any text
+	EntityList    EntityType = "List"    // One of Values; fuzzy lists also accept other text
+	EntityInteger EntityType = "Integer" // A whole number
+)
+
+// IntentEntity declares a pattern entity; entities used in patterns without a declaration match any text
+type IntentEntity struct {
+	Name   string
+	Type   EntityType
+	Values []string // List entities only
+	Fuzzy  bool     // List entities only: accept text outside Values
+}
+
+// IntentMatch is a pattern match against an utterance
+type IntentMatch struct {
+	IntentID string            `json:"intentId"`
+	Text     string            `json:"text"`
+	Entities map[string]string `json:"entities,omitempty"`
+}
+
+// IntentEvent carries the result of matching a final recognition result; IntentID is empty when nothing matched
+type IntentEvent struct {
+	SessionID string
+	IntentMatch
+	Segment TranscriptSegment
+}
+
+// intentPattern is a compiled pattern
+type intentPattern struct {
+	intentID string
+	regex    *regexp.Regexp
+	entities []patternEntity // In capture group order
+}
+
+// patternEntity is an entity reference inside a pattern
+type patternEntity struct {
+	name string
+	key  string // Role when given, otherwise the entity name
+}
+
+// IntentMatcher matches text against registered intent patterns
+type IntentMatcher struct {
+	mu       sync.RWMutex
+	patterns []intentPattern
+	entities map[string]IntentEntity
+}
+
+func NewIntentMatcher() *IntentMatcher {
+	return &IntentMatcher{entities: make(map[string]IntentEntity)}
+}
+
+// AddEntity declares how an entity referenced by patterns is validated
+func (m *IntentMatcher) AddEntity(entity IntentEntity) error {
+	if entity.Name == "" {
+		return fmt.Errorf("entity name is required")
+	}
+	if entity.Type == EntityList && len(entity.Values) == 0 {
+		return fmt.Errorf("list entity %s has no values", entity.Name)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entities[entity.Name] = entity
+	return nil
+}
+
+// AddIntent registers a pattern for intentID; an intent may have several patterns
+func (m *IntentMatcher) AddIntent(intentID, pattern string) error {
+	compiled, err := compileIntentPattern(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern for intent %s: %v", intentID, err)
+	}
+	compiled.intentID = intentID
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.patterns = append(m.patterns, *compiled)
+	return nil
+}
+
+// Match returns the first registered pattern that matches text
+func (m *IntentMatcher) Match(text string) (IntentMatch, bool) {
+	normalized := normalizeIntentText(text)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, pattern := range m.patterns {
+		groups := pattern.regex.FindStringSubmatch(normalized)
+		if groups == nil {
+			continue
+		}
+
+		entities, ok := m.resolveEntities(pattern, groups[1:])
+		if !ok {
+			continue
+		}
+		return IntentMatch{IntentID: pattern.intentID, Text: text, Entities: entities}, true
+	}
+	return IntentMatch{Text: text}, false
+}
+
+// resolveEntities validates captured entity values against their declarations
+func (m *IntentMatcher) resolveEntities(pattern intentPattern, values []string) (map[string]string, bool) {
+	if len(pattern.entities) == 0 {
+		return nil, true
+	}
+
+	entities := make(map[string]string, len(pattern.entities))
+	for i, ref := range pattern.entities {
+		value := strings.TrimSpace(values[i])
+		entity, declared := m.entities[ref.name]
+		if declared {
+			switch entity.Type {
+			case EntityInteger:
+				if _, err := strconv.Atoi(value); err != nil {
+					return nil, false
+				}
+			case EntityList:
+				listValue, found := matchListValue(entity.Values, value)
+				if !found && !entity.Fuzzy {
+					return nil, false
+				}
+				if found {
+					value = listValue
+				}
+			}
+		}
+		entities[ref.key] = value
+	}
+	return entities, true
+}
+
+// matchListValue returns the list value equal to value, ignoring case and punctuation
+func matchListValue(values []string, value string) (string, bool) {
+	for _, candidate := range values {
+		if normalizeIntentText(candidate) == value {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// normalizeIntentText lowercases text, drops punctuation and collapses whitespace
+func normalizeIntentText(text string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r) || r == '\'' {
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, text)
+	return strings.Join(strings.Fields(cleaned), " ")
+}
+
+// compileIntentPattern turns the pattern syntax into an anchored regular expression
+func compileIntentPattern(pattern string) (*intentPattern, error) {
+	compiled := &intentPattern{}
+	var expr strings.Builder
+	expr.WriteString(`^`)
+
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '{':
+			end := strings.IndexByte(pattern[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed entity at position %d", i)
+			}
+			name, role, _ := strings.Cut(pattern[i+1:i+end], ":")
+			name = strings.TrimSpace(name)
+			if name == "" {
+				return nil, fmt.Errorf("empty entity at position %d", i)
+			}
+			key := name
+			if role = strings.TrimSpace(role); role != "" {
+				key = role
+			}
+			compiled.entities = append(compiled.entities, patternEntity{name: name, key: key})
+			expr.WriteString(intentBoundary + `(.+?)`)
+			i += end + 1
+		case c == '(' || c == '[':
+			closing := byte(')')
+			if c == '[' {
+				closing = ']'
+			}
+			end := strings.IndexByte(pattern[i:], closing)
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed group at position %d", i)
+			}
+			group := pattern[i+1 : i+end]
+			if strings.ContainsAny(group, "{}()[]") {
+				return nil, fmt.Errorf("nested groups and entities are not supported: %s", group)
+			}
+			var alternatives []string
+			for _, alternative := range strings.Split(group, "|") {
+				if words := normalizeIntentText(alternative); words != "" {
+					alternatives = append(alternatives, wordsExpr(words))
+				}
+			}
+			if len(alternatives) == 0 {
+				return nil, fmt.Errorf("empty group at position %d", i)
+			}
+			if c == '[' {
+				expr.WriteString(`(?:` + intentBoundary + `(?:` + strings.Join(alternatives, "|") + `))?`)
+			} else {
+				expr.WriteString(intentBoundary + `(?:` + strings.Join(alternatives, "|") + `)`)
+			}
+			i += end + 1
+		default:
+			end := strings.IndexAny(pattern[i:], " \t{([")
+			if end < 0 {
+				end = len(pattern) - i
+			}
+			if words := normalizeIntentText(pattern[i : i+end]); words != "" {
+				expr.WriteString(intentBoundary + wordsExpr(words))
+			}
+			i += end
+		}
+	}
+	expr.WriteString(`\s*$`)
+
+	regex, err := regexp.Compile(expr.String())
+	if err != nil {
+		return nil, err
+	}
+	compiled.regex = regex
+	return compiled, nil
+}
+
+// intentBoundary starts every pattern element, so words and entities only match whole words of the
+// normalized text. RE2's \b only knows ASCII letters and would not match around words such as café.
+const intentBoundary = `(?:^|\s)`
+
+// wordsExpr matches normalized words separated by whitespace
+func wordsExpr(words string) string {
+	fields := strings.Fields(words)
+	for i, field := range fields {
+		fields[i] = regexp.QuoteMeta(field)
+	}
+	return strings.Join(fields, `\s+`)
+}
+
+// IntentRecognizer matches the final results of a recognition session against intent patterns
+type IntentRecognizer struct {
+	*IntentMatcher
+	recognizer *Recognizer
+	intents    chan IntentEvent
+	startOnce  sync.Once
+}
+
+// NewIntentRecognizer creates an intent recognition session; register intents, then call Start.
+// With opts.KeywordModelPath set, only utterances following the wake word are matched.
+func NewIntentRecognizer(subscriptionKey, region string, input AudioInput, opts *RecognizerOptions) (*IntentRecognizer, error) {
+	recognizer, err := NewRecognizer(subscriptionKey, region, input, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IntentRecognizer{
+		IntentMatcher: NewIntentMatcher(),
+		recognizer:    recognizer,
+		intents:       make(chan IntentEvent, eventBufferSize),
+	}, nil
+}
+
+// Start begins recognition; every final result is delivered on Intents(), matched or not
+func (r *IntentRecognizer) Start(ctx context.Context) error {
+	if err := r.recognizer.Start(ctx); err != nil {
+		return err
+	}
+
+	r.startOnce.Do(func() {
+		go func() {
+			defer close(r.intents)
+			for event := range r.recognizer.Final() {
+				match, _ := r.Match(event.Segment.Text)
+				r.intents <- IntentEvent{SessionID: event.SessionID, IntentMatch: match, Segment: event.Segment}
+			}
+		}()
+	})
+	return nil
+}
+
+// Intents delivers one event per final result; it is closed when the session ends
+func (r *IntentRecognizer) Intents() <-chan IntentEvent {
+	return r.intents
+}
+
+// Recognizer exposes the underlying session for its partial, keyword, canceled and session events
+func (r *IntentRecognizer) Recognizer() *Recognizer {
+	return r.recognizer
+}
+
+// Stop ends the session
+func (r *IntentRecognizer) Stop() error {
+	return r.recognizer.Stop()
+}
+
+// Wait blocks until the session ends and returns its error, if any
+func (r *IntentRecognizer) Wait() error {
+	return r.recognizer.Wait()
+}
@@ -0,0 +1,92 @@
+package speech
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIntentMatcher(t *testing.T) {
+	m := NewIntentMatcher()
+	if err := m.AddEntity(IntentEntity{Name: "floor", Type: EntityInteger}); err != nil {
+		t.Fatalf("add entity: %v", err)
+	}
+	if err := m.AddEntity(IntentEntity{Name: "room", Type: EntityList, Values: []string{"Kitchen", "Living Room"}}); err != nil {
+		t.Fatalf("add entity: %v", err)
+	}
+	for intent, pattern := range map[string]string{
+		"elevator": "(Take | Bring) me to floor {floor}",
+		"lights":   "Turn on the lights [in | of] [the] {room}",
+		"go":       "Go to {place:destination} now",
+	} {
+		if err := m.AddIntent(intent, pattern); err != nil {
+			t.Fatalf("add intent %s: %v", intent, err)
+		}
+	}
+
+	tests := []struct {
+		text     string
+		intent   string
+		entities map[string]string
+	}{
+		{"Take me to floor 7.", "elevator", map[string]string{"floor": "7"}},
+		{"bring me to floor 12", "elevator", map[string]string{"floor": "12"}},
+		{"Take me to floor seven", "", nil},
+		{"Turn on the lights in the kitchen", "lights", map[string]string{"room": "Kitchen"}},
+		{"Turn on the lights living room!", "lights", map[string]string{"room": "Living Room"}},
+		{"Turn on the lights in the garage", "", nil},
+		{"Go to the park now", "go", map[string]string{"destination": "the park"}},
+		// Words only match whole words
+		{"Taken me to floor 7", "", nil},
+		{"Go tothe park now", "", nil},
+		{"Go to the park nowhere", "", nil},
+	}
+	for _, test := range tests {
+		match, ok := m.Match(test.text)
+		if ok != (test.intent != "") || match.IntentID != test.intent {
+			t.Errorf("Match(%q) = %q, want %q", test.text, match.IntentID, test.intent)
+			continue
+		}
+		if !reflect.DeepEqual(match.Entities, test.entities) {
+			t.Errorf("Match(%q) entities = %v, want %v", test.text, match.Entities, test.entities)
+		}
+	}
+}
+
+func TestIntentMatcherNonASCIIWords(t *testing.T) {
+	m := NewIntentMatcher()
+	for intent, pattern := range map[string]string{
+		"café":    "go to café {when}",
+		"weather": "(qué | cómo) tiempo hace en {city}",
+		"order":   "ein Stück [Kuchen] für {name}",
+	} {
+		if err := m.AddIntent(intent, pattern); err != nil {
+			t.Fatalf("add intent %s: %v", intent, err)
+		}
+	}
+
+	tests := []struct {
+		text     string
+		intent   string
+		entities map[string]string
+	}{
+		{"Go to café now", "café", map[string]string{"when": "now"}},
+		{"go to CAFÉ tomorrow morning", "café", map[string]string{"when": "tomorrow morning"}},
+		{"¿Qué tiempo hace en Málaga?", "weather", map[string]string{"city": "málaga"}},
+		{"Cómo tiempo hace en Sevilla", "weather", map[string]string{"city": "sevilla"}},
+		{"Ein Stück für Jürgen", "order", map[string]string{"name": "jürgen"}},
+		{"ein Stück Kuchen für Anna", "order", map[string]string{"name": "anna"}},
+		// Accented words still only match whole words
+		{"go to cafés now", "", nil},
+		{"ein Stücke für Anna", "", nil},
+	}
+	for _, test := range tests {
+		match, _ := m.Match(test.text)
+		if match.IntentID != test.intent {
+			t.Errorf("Match(%q) = %q, want %q", test.text, match.IntentID, test.intent)
+			continue
+		}
+		if !reflect.DeepEqual(match.Entities, test.entities) {
+			t.Errorf("Match(%q) entities = %v, want %v", test.text, match.Entities, test.entities)
+		}
+	}
+}
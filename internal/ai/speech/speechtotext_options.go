@@ -0,0 +1,285 @@
+package speech
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	speechsdk "github.com/Microsoft/cognitive-services-speech-sdk-go/speech"
+)
+
+/*
+Reference: https://learn.microsoft.com/en-us/azure/ai-services/speech-service/how-to-recognize-speech
+Lightweight recognition tuning, without training a Custom Speech model:
+✅ Phrase lists boosting names and domain terms
+✅ Segmentation, initial and end silence timeouts
+✅ Simple or detailed output, word-level timestamps, profanity handling
+✅ Continuous language identification among candidate locales
+Every option can be set from environment variables (RecognizerOptionsFromEnv) and command-line flags (RegisterFlags).
+*/
+
+// OutputFormat selects the recognition result payload
+type OutputFormat string
+
+const (
+	OutputFormatDetailed OutputFormat = "detailed" // Confidence, N-best and word timings
+	OutputFormatSimple   OutputFormat = "simple"   // Display text only
+)
+
+// Service limits and settings without a typed SDK setter
+const (
+	maxCandidateLanguages    = 10
+	minSegmentationSilence   = 100 * time.Millisecond
+	maxSegmentationSilence   = 5 * time.Second
+	languageIDModeProperty   = "SpeechServiceConnection_LanguageIdMode"
+	languageIDModeContinuous = "Continuous"
+	listSeparator            = ","
+)
+
+// Environment variables read by RecognizerOptionsFromEnv
+const (
+	EnvSpeechLanguage                   = "SPEECH_LANGUAGE"
+	EnvSpeechCustomEndpointID           = "SPEECH_CUSTOM_ENDPOINT_ID"
+	EnvSpeechPhrases                    = "SPEECH_PHRASES" // Comma-separated phrases, or @file with one phrase per line
+	EnvSpeechProfanity                  = "SPEECH_PROFANITY"
+	EnvSpeechSegmentationSilenceTimeout = "SPEECH_SEGMENTATION_SILENCE_TIMEOUT"
+	EnvSpeechInitialSilenceTimeout      = "SPEECH_INITIAL_SILENCE_TIMEOUT"
+	EnvSpeechEndSilenceTimeout          = "SPEECH_END_SILENCE_TIMEOUT"
+	EnvSpeechOutputFormat               = "SPEECH_OUTPUT_FORMAT"
+	EnvSpeechDisableWordTimestamps      = "SPEECH_DISABLE_WORD_TIMESTAMPS"
+	EnvSpeechCandidateLanguages         = "SPEECH_CANDIDATE_LANGUAGES"
+)
+
+// RecognizerOptionsFromEnv reads the recognition options set in the environment; unset variables keep the defaults
+func RecognizerOptionsFromEnv() (*RecognizerOptions, error) {
+	o := &RecognizerOptions{
+		Language:     os.Getenv(EnvSpeechLanguage),
+		EndpointID:   os.Getenv(EnvSpeechCustomEndpointID),
+		Profanity:    ProfanityOption(os.Getenv(EnvSpeechProfanity)),
+		OutputFormat: OutputFormat(os.Getenv(EnvSpeechOutputFormat)),
+	}
+
+	var err error
+	if value := os.Getenv(EnvSpeechPhrases); value != "" {
+		if o.PhraseList, err = parsePhraseList(value); err != nil {
+			return nil, err
+		}
+	}
+	if value := os.Getenv(EnvSpeechCandidateLanguages); value != "" {
+		o.CandidateLanguages = splitList(value)
+	}
+
+	durations := map[string]*time.Duration{
+		EnvSpeechSegmentationSilenceTimeout: &o.SegmentationSilenceTimeout,
+		EnvSpeechInitialSilenceTimeout:      &o.InitialSilenceTimeout,
+		EnvSpeechEndSilenceTimeout:          &o.EndSilenceTimeout,
+	}
+	for name, duration := range durations {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		if *duration, err = time.ParseDuration(value); err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", name, err)
+		}
+	}
+
+	if value := os.Getenv(EnvSpeechDisableWordTimestamps); value != "" {
+		if o.DisableWordTimestamps, err = strconv.ParseBool(value); err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", EnvSpeechDisableWordTimestamps, err)
+		}
+	}
+
+	return o, o.Validate()
+}
+
+// RegisterFlags defines command-line flags for the tuning options; the current values, such as those from
+// RecognizerOptionsFromEnv, are the flag defaults. Call Validate after parsing.
+func (o *RecognizerOptions) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.Language, "language", o.Language, "Recognition locale such as en-US (optional, "+EnvSpeechLanguage+")")
+	fs.StringVar(&o.EndpointID, "endpoint-id", o.EndpointID, "Custom Speech endpoint ID; -language must match its locale (optional, "+EnvSpeechCustomEndpointID+")")
+	fs.Var((*phraseListValue)(&o.PhraseList), "phrases", "Comma-separated phrases to boost, or @file with one phrase per line ("+EnvSpeechPhrases+")")
+	fs.StringVar((*string)(&o.Profanity), "profanity", string(o.Profanity), "Profanity handling: masked, removed, raw ("+EnvSpeechProfanity+")")
+	fs.DurationVar(&o.SegmentationSilenceTimeout, "segmentation-silence", o.SegmentationSilenceTimeout, "Silence ending a phrase, 100ms to 5s ("+EnvSpeechSegmentationSilenceTimeout+")")
+	fs.DurationVar(&o.InitialSilenceTimeout, "initial-silence", o.InitialSilenceTimeout, "Silence allowed before speech starts ("+EnvSpeechInitialSilenceTimeout+")")
+	fs.DurationVar(&o.EndSilenceTimeout, "end-silence", o.EndSilenceTimeout, "Silence allowed after speech ends ("+EnvSpeechEndSilenceTimeout+")")
+	fs.StringVar((*string)(&o.OutputFormat), "output-format", string(o.OutputFormat), "Result format: detailed, simple ("+EnvSpeechOutputFormat+")")
+	fs.BoolVar(&o.DisableWordTimestamps, "disable-word-timestamps", o.DisableWordTimestamps, "Do not request word-level timestamps ("+EnvSpeechDisableWordTimestamps+")")
+	fs.Var((*listValue)(&o.CandidateLanguages), "candidate-languages", "Comma-separated locales to identify continuously, up to 10 ("+EnvSpeechCandidateLanguages+")")
+}
+
+// Validate checks the options against the limits of the service
+func (o *RecognizerOptions) Validate() error {
+	if o == nil {
+		return nil
+	}
+	switch o.OutputFormat {
+	case "", OutputFormatDetailed, OutputFormatSimple:
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.OutputFormat)
+	}
+	if o.Profanity != "" {
+		if _, ok := profanityOptions[o.Profanity]; !ok {
+			return fmt.Errorf("unsupported profanity option: %s", o.Profanity)
+		}
+	}
+	if o.SegmentationSilenceTimeout != 0 && (o.SegmentationSilenceTimeout < minSegmentationSilence || o.SegmentationSilenceTimeout > maxSegmentationSilence) {
+		return fmt.Errorf("segmentation silence timeout must be between %s and %s", minSegmentationSilence, maxSegmentationSilence)
+	}
+	if o.InitialSilenceTimeout < 0 || o.EndSilenceTimeout < 0 {
+		return fmt.Errorf("silence timeouts cannot be negative")
+	}
+	if len(o.CandidateLanguages) > maxCandidateLanguages {
+		return fmt.Errorf("at most %d candidate languages are supported, got %d", maxCandidateLanguages, len(o.CandidateLanguages))
+	}
+	if len(o.CandidateLanguages) > 0 && o.EndpointID != "" {
+		return fmt.Errorf("a custom endpoint cannot be combined with candidate languages")
+	}
+	if len(o.CandidateLanguages) > 0 && o.KeywordModelPath != "" {
+		return fmt.Errorf("keyword recognition cannot be combined with candidate languages")
+	}
+	return nil
+}
+
+// identifiesLanguage reports whether the session identifies the language among candidates
+func (o *RecognizerOptions) identifiesLanguage() bool {
+	return o != nil && len(o.CandidateLanguages) > 0
+}
+
+// applyTuning sets the output format, timestamps, timeouts and language identification mode on a speech config
+func (o *RecognizerOptions) applyTuning(config *speechsdk.SpeechConfig) error {
+	var tuning RecognizerOptions
+	if o != nil {
+		tuning = *o
+	}
+	if err := tuning.Validate(); err != nil {
+		return err
+	}
+
+	// Detailed output carries confidence and N-best, word timestamps are needed for offsets per word
+	outputFormat := common.Detailed
+	if tuning.OutputFormat == OutputFormatSimple {
+		outputFormat = common.Simple
+	}
+	if err := config.SetOutputFormat(outputFormat); err != nil {
+		return fmt.Errorf("failed to set output format: %v", err)
+	}
+	// Word timings only come with the detailed payload
+	if !tuning.DisableWordTimestamps && outputFormat == common.Detailed {
+		if err := config.RequestWordLevelTimestamps(); err != nil {
+			return fmt.Errorf("failed to request word level timestamps: %v", err)
+		}
+	}
+
+	timeouts := []struct {
+		id      common.PropertyID
+		timeout time.Duration
+	}{
+		{common.SegmentationSilenceTimeoutMs, tuning.SegmentationSilenceTimeout},
+		{common.SpeechServiceConnectionInitialSilenceTimeoutMs, tuning.InitialSilenceTimeout},
+		{common.SpeechServiceConnectionEndSilenceTimeoutMs, tuning.EndSilenceTimeout},
+	}
+	for _, t := range timeouts {
+		if t.timeout == 0 {
+			continue
+		}
+		if err := config.SetProperty(t.id, strconv.FormatInt(t.timeout.Milliseconds(), 10)); err != nil {
+			return fmt.Errorf("failed to set silence timeout: %v", err)
+		}
+	}
+
+	if tuning.identifiesLanguage() {
+		if err := config.SetPropertyByString(languageIDModeProperty, languageIDModeContinuous); err != nil {
+			return fmt.Errorf("failed to enable continuous language identification: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// addPhraseList boosts the phrases on a created recognizer
+func addPhraseList(recognizer *speechsdk.SpeechRecognizer, phrases []string) error {
+	grammar, err := speechsdk.NewPhraseListGrammarFromRecognizer(recognizer)
+	if err != nil {
+		return fmt.Errorf("failed to create phrase list: %v", err)
+	}
+	// The phrases stay attached to the recognizer once the grammar handle is released
+	defer grammar.Close()
+
+	for _, phrase := range phrases {
+		if err := grammar.AddPhrase(phrase); err != nil {
+			return fmt.Errorf("failed to add phrase %q: %v", phrase, err)
+		}
+	}
+	return nil
+}
+
+// parsePhraseList splits comma-separated phrases, or reads one phrase per line from the file named after "@"
+func parsePhraseList(value string) ([]string, error) {
+	path, isFile := strings.CutPrefix(value, "@")
+	if !isFile {
+		return splitList(value), nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open phrase list: %v", err)
+	}
+	defer file.Close()
+
+	var phrases []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if phrase := strings.TrimSpace(scanner.Text()); phrase != "" && !strings.HasPrefix(phrase, "#") {
+			phrases = append(phrases, phrase)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read phrase list: %v", err)
+	}
+	return phrases, nil
+}
+
+// splitList splits a comma-separated list, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, listSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// listValue is a comma-separated list flag
+type listValue []string
+
+func (v *listValue) String() string {
+	return strings.Join(*v, listSeparator)
+}
+
+func (v *listValue) Set(value string) error {
+	*v = splitList(value)
+	return nil
+}
+
+// phraseListValue is a phrase list flag accepting @file
+type phraseListValue []string
+
+func (v *phraseListValue) String() string {
+	return strings.Join(*v, listSeparator)
+}
+
+func (v *phraseListValue) Set(value string) error {
+	phrases, err := parsePhraseList(value)
+	if err != nil {
+		return err
+	}
+	*v = phrases
+	return nil
+}
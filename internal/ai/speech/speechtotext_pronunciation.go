@@ -0,0 +1,278 @@
+package speech
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+/*
+Reference: https://learn.microsoft.com/en-us/azure/ai-services/speech-service/how-to-pronunciation-assessment
+The Go Speech SDK does not wrap PronunciationAssessmentConfig, so the assessment parameters are passed as the
+"PronunciationAssessment_Params" JSON property, the same payload the other SDKs send. Scores come back in the
+detailed JSON result of each final recognition. Prosody assessment is only available for en-US.
+*/
+
+// pronunciationAssessmentParams is the property name read by the service connection
+const pronunciationAssessmentParams = "PronunciationAssessment_Params"
+
+// GradingSystem is the point system used for scores
+type GradingSystem string
+
+const (
+	GradingSystemFivePoint   GradingSystem = "FivePoint"
+	GradingSystemHundredMark GradingSystem = "HundredMark"
+)
+
+// Granularity is the finest level the assessment is evaluated at
+type Granularity string
+
+const (
+	GranularityPhoneme  Granularity = "Phoneme"
+	GranularityWord     Granularity = "Word"
+	GranularityFullText Granularity = "FullText"
+)
+
+// PronunciationAssessmentConfig enables pronunciation assessment on a recognizer
+type PronunciationAssessmentConfig struct {
+	ReferenceText   string        // Text the speaker is expected to read; empty runs an unscripted assessment
+	GradingSystem   GradingSystem // Defaults to HundredMark
+	Granularity     Granularity   // Defaults to Phoneme
+	EnableMiscue    bool          // Flag omitted and inserted words against ReferenceText
+	EnableProsody   bool          // Score stress, intonation, speed and rhythm
+	PhonemeAlphabet string        // "SAPI" (default) or "IPA"
+}
+
+// params renders the JSON payload of the assessment property
+func (c *PronunciationAssessmentConfig) params() (string, error) {
+	gradingSystem := c.GradingSystem
+	if gradingSystem == "" {
+		gradingSystem = GradingSystemHundredMark
+	}
+	granularity := c.Granularity
+	if granularity == "" {
+		granularity = GranularityPhoneme
+	}
+
+	params := map[string]interface{}{
+		"referenceText":           c.ReferenceText,
+		"gradingSystem":           gradingSystem,
+		"granularity":             granularity,
+		"dimension":               "Comprehensive",
+		"enableMiscue":            c.EnableMiscue,
+		"enableProsodyAssessment": c.EnableProsody,
+	}
+	if c.PhonemeAlphabet != "" {
+		params["phonemeAlphabet"] = c.PhonemeAlphabet
+	}
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal pronunciation assessment parameters: %v", err)
+	}
+	return string(data), nil
+}
+
+// PronunciationScores are the scores of an utterance or a whole assessment
+type PronunciationScores struct {
+	Accuracy      float64 `json:"accuracy"`
+	Fluency       float64 `json:"fluency"`
+	Completeness  float64 `json:"completeness"`
+	Prosody       float64 `json:"prosody,omitempty"`
+	Pronunciation float64 `json:"pronunciation"` // Weighted aggregate of the other scores
+}
+
+// PronunciationAssessmentResult is the assessment of one recognized utterance
+type PronunciationAssessmentResult struct {
+	Text   string              `json:"text"`
+	Offset time.Duration       `json:"offset"`
+	Scores PronunciationScores `json:"scores"`
+	Words  []WordAssessment    `json:"words"`
+}
+
+// WordAssessment scores a single word; ErrorType is None, Omission, Insertion or Mispronunciation
+type WordAssessment struct {
+	Word      string              `json:"word"`
+	Offset    time.Duration       `json:"offset"`
+	Duration  time.Duration       `json:"duration"`
+	Accuracy  float64             `json:"accuracy"`
+	ErrorType string              `json:"errorType"`
+	Phonemes  []PhonemeAssessment `json:"phonemes,omitempty"`
+}
+
+// PhonemeAssessment scores a single phoneme of a word
+type PhonemeAssessment struct {
+	Phoneme  string        `json:"phoneme"`
+	Offset   time.Duration `json:"offset"`
+	Duration time.Duration `json:"duration"`
+	Accuracy float64       `json:"accuracy"`
+}
+
+// PronunciationReport aggregates the utterances of an assessment session
+type PronunciationReport struct {
+	ReferenceText string                          `json:"referenceText,omitempty"`
+	Scores        PronunciationScores             `json:"scores"`
+	Utterances    []PronunciationAssessmentResult `json:"utterances"`
+}
+
+// pronunciationResult mirrors the assessment fields of the detailed JSON result
+type pronunciationResult struct {
+	Offset int64 `json:"Offset"`
+	NBest  []struct {
+		Display                 string `json:"Display"`
+		PronunciationAssessment struct {
+			AccuracyScore     float64 `json:"AccuracyScore"`
+			FluencyScore      float64 `json:"FluencyScore"`
+			CompletenessScore float64 `json:"CompletenessScore"`
+			ProsodyScore      float64 `json:"ProsodyScore"`
+			PronScore         float64 `json:"PronScore"`
+		} `json:"PronunciationAssessment"`
+		Words []struct {
+			Word                    string `json:"Word"`
+			Offset                  int64  `json:"Offset"`
+			Duration                int64  `json:"Duration"`
+			PronunciationAssessment struct {
+				AccuracyScore float64 `json:"AccuracyScore"`
+				ErrorType     string  `json:"ErrorType"`
+			} `json:"PronunciationAssessment"`
+			Phonemes []struct {
+				Phoneme                 string `json:"Phoneme"`
+				Offset                  int64  `json:"Offset"`
+				Duration                int64  `json:"Duration"`
+				PronunciationAssessment struct {
+					AccuracyScore float64 `json:"AccuracyScore"`
+				} `json:"PronunciationAssessment"`
+			} `json:"Phonemes"`
+		} `json:"Words"`
+	} `json:"NBest"`
+}
+
+// parsePronunciationAssessment extracts the assessment of the best alternative from a detailed JSON result
+func parsePronunciationAssessment(jsonResult string) (*PronunciationAssessmentResult, error) {
+	var result pronunciationResult
+	if err := json.Unmarshal([]byte(jsonResult), &result); err != nil {
+		return nil, fmt.Errorf("failed to decode pronunciation assessment: %v", err)
+	}
+	if len(result.NBest) == 0 {
+		return nil, fmt.Errorf("recognition result has no pronunciation assessment")
+	}
+
+	best := result.NBest[0]
+	assessment := &PronunciationAssessmentResult{
+		Text:   best.Display,
+		Offset: time.Duration(result.Offset) * ticksPerDuration,
+		Scores: PronunciationScores{
+			Accuracy:      best.PronunciationAssessment.AccuracyScore,
+			Fluency:       best.PronunciationAssessment.FluencyScore,
+			Completeness:  best.PronunciationAssessment.CompletenessScore,
+			Prosody:       best.PronunciationAssessment.ProsodyScore,
+			Pronunciation: best.PronunciationAssessment.PronScore,
+		},
+	}
+	for _, word := range best.Words {
+		wordAssessment := WordAssessment{
+			Word:      word.Word,
+			Offset:    time.Duration(word.Offset) * ticksPerDuration,
+			Duration:  time.Duration(word.Duration) * ticksPerDuration,
+			Accuracy:  word.PronunciationAssessment.AccuracyScore,
+			ErrorType: word.PronunciationAssessment.ErrorType,
+		}
+		for _, phoneme := range word.Phonemes {
+			wordAssessment.Phonemes = append(wordAssessment.Phonemes, PhonemeAssessment{
+				Phoneme:  phoneme.Phoneme,
+				Offset:   time.Duration(phoneme.Offset) * ticksPerDuration,
+				Duration: time.Duration(phoneme.Duration) * ticksPerDuration,
+				Accuracy: phoneme.PronunciationAssessment.AccuracyScore,
+			})
+		}
+		assessment.Words = append(assessment.Words, wordAssessment)
+	}
+
+	return assessment, nil
+}
+
+// AssessPronunciation recognizes input against the reference text and scores the whole session
+func AssessPronunciation(subscriptionKey, region string, input AudioInput, config PronunciationAssessmentConfig, opts *RecognizerOptions) (*PronunciationReport, error) {
+	o := RecognizerOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	o.PronunciationAssessment = &config
+
+	recognizer, err := NewRecognizer(subscriptionKey, region, input, &o)
+	if err != nil {
+		return nil, err
+	}
+	defer recognizer.Stop()
+
+	if err := recognizer.Start(context.Background()); err != nil {
+		return nil, err
+	}
+
+	report := &PronunciationReport{ReferenceText: config.ReferenceText}
+	for event := range recognizer.Final() {
+		if event.Pronunciation != nil {
+			report.Utterances = append(report.Utterances, *event.Pronunciation)
+		}
+	}
+
+	if err := recognizer.Wait(); err != nil {
+		return nil, err
+	}
+
+	report.Scores = aggregatePronunciationScores(report.Utterances, config)
+	return report, nil
+}
+
+// aggregatePronunciationScores combines utterance scores the way the service scores a single utterance:
+// accuracy over assessed words, fluency and prosody weighted by utterance length, completeness against the reference text
+func aggregatePronunciationScores(utterances []PronunciationAssessmentResult, config PronunciationAssessmentConfig) PronunciationScores {
+	var scores PronunciationScores
+	var accuracySum, weightSum float64
+	var wordCount, correctCount int
+	for _, utterance := range utterances {
+		weight := float64(len(utterance.Words))
+		scores.Fluency += utterance.Scores.Fluency * weight
+		scores.Prosody += utterance.Scores.Prosody * weight
+		scores.Pronunciation += utterance.Scores.Pronunciation * weight
+		scores.Completeness += utterance.Scores.Completeness * weight
+		weightSum += weight
+
+		for _, word := range utterance.Words {
+			if word.ErrorType == "Insertion" || word.ErrorType == "Omission" {
+				continue
+			}
+			accuracySum += word.Accuracy
+			wordCount++
+			if word.ErrorType == "" || word.ErrorType == "None" {
+				correctCount++
+			}
+		}
+	}
+	if weightSum == 0 {
+		return PronunciationScores{}
+	}
+
+	scores.Fluency /= weightSum
+	scores.Prosody /= weightSum
+	scores.Pronunciation /= weightSum
+	scores.Completeness /= weightSum
+	if wordCount > 0 {
+		scores.Accuracy = accuracySum / float64(wordCount)
+	}
+
+	// Utterance completeness only sees its own slice of the reference, so recompute it for the whole text
+	if referenceWords := len(strings.Fields(config.ReferenceText)); referenceWords > 0 {
+		scores.Completeness = float64(correctCount) / float64(referenceWords) * 100
+		if scores.Completeness > 100 {
+			scores.Completeness = 100
+		}
+		if config.GradingSystem == GradingSystemFivePoint {
+			scores.Completeness /= 20
+		}
+	}
+
+	return scores
+}
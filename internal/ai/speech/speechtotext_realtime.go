@@ -1,128 +1,100 @@
 package speech
 
 import (
-	"bufio"
+	"context"
 	"fmt"
-	"io"
+	"net/http"
 	"os/exec"
+	"strings"
 
-	"github.com/Microsoft/cognitive-services-speech-sdk-go/audio"
-	speechsdk "github.com/Microsoft/cognitive-services-speech-sdk-go/speech"
+	"github.com/ngothientuong/tngo-ai-svcs/internal/audio/audioutil"
 )
 
-// TranscribeFromLiveStream transcribes speech from a live video stream
-func TranscribeFromLiveStream(subscriptionKey, region, streamURL, format string) error {
-	// Create speech config
-	speechConfig, err := speechsdk.NewSpeechConfigFromSubscription(subscriptionKey, region)
-	if err != nil {
-		return fmt.Errorf("failed to create speech config: %v", err)
-	}
-	defer speechConfig.Close()
-
+// TranscribeFromLiveStream starts a recognition session on the audio track of a live video stream
+func TranscribeFromLiveStream(ctx context.Context, subscriptionKey, region, streamURL, format string, opts *RecognizerOptions) (*Recognizer, error) {
 	// Get direct audio URL if YouTube
 	if format == "youtube" {
 		out, err := exec.Command("yt-dlp", "-g", streamURL).Output()
 		if err != nil {
-			return fmt.Errorf("failed to get direct YouTube audio URL: %v", err)
+			return nil, fmt.Errorf("failed to get direct YouTube audio URL: %v", err)
+		}
+		streamURL = strings.TrimSpace(string(out))
+	}
+
+	// PCM WAV streams are converted in Go, so FFmpeg is only needed for other containers
+	if format == "wav" {
+		input, err := wavStreamInput(ctx, streamURL)
+		if err != nil {
+			return nil, err
 		}
-		streamURL = string(out)
+		return startRecognizer(ctx, subscriptionKey, region, input, opts)
 	}
 
 	// FFmpeg command to extract live audio
 	ffmpegCmd := exec.Command("ffmpeg", "-i", streamURL, "-vn", "-ac", "1", "-ar", "16000", "-f", "wav", "pipe:1")
 	stdout, err := ffmpegCmd.StdoutPipe()
 	if err != nil {
-		return fmt.Errorf("failed to create stdout pipe: %v", err)
+		return nil, fmt.Errorf("failed to create stdout pipe: %v", err)
 	}
 
 	if err := ffmpegCmd.Start(); err != nil {
-		return fmt.Errorf("failed to start ffmpeg: %v", err)
+		return nil, fmt.Errorf("failed to start ffmpeg: %v", err)
 	}
-	defer ffmpegCmd.Wait() // Ensure FFmpeg exits cleanly
 
-	// Create a PushAudioInputStream
-	audioFormat, err := audio.GetDefaultInputFormat()
-	if err != nil {
-		return fmt.Errorf("failed to get default audio format: %v", err)
+	// Ensure FFmpeg exits cleanly once the session ends
+	input := ReaderInput(stdout, AudioInputFormatWAV)
+	input.onClose = func() {
+		ffmpegCmd.Process.Kill()
+		ffmpegCmd.Wait()
 	}
-	defer audioFormat.Close()
 
-	audioStream, err := audio.CreatePushAudioInputStreamFromFormat(audioFormat)
+	return startRecognizer(ctx, subscriptionKey, region, input, opts)
+}
+
+// wavStreamInput fetches a PCM WAV stream over HTTP and converts it to the recognizer's 16 kHz mono input
+func wavStreamInput(ctx context.Context, streamURL string) (AudioInput, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, streamURL, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create push audio input stream: %v", err)
+		return AudioInput{}, fmt.Errorf("failed to create request: %v", err)
 	}
-	defer audioStream.Close()
-
-	// Read the audio stream and send to Azure Speech SDK
-	go func() {
-		buf := bufio.NewReader(stdout)
-		for {
-			data := make([]byte, 4096)
-			n, err := buf.Read(data)
-			if err != nil {
-				if err == io.EOF {
-					break
-				}
-				fmt.Printf("Audio read error: %v\n", err)
-				break
-			}
-			if n > 0 {
-				err = audioStream.Write(data[:n])
-				if err != nil {
-					fmt.Printf("Audio write error: %v\n", err)
-					break
-				}
-			}
-		}
-		audioStream.Close()
-	}()
-
-	// Initialize recognizer with streaming audio input
-	audioConfig, err := audio.NewAudioConfigFromStreamInput(audioStream)
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to create audio config: %v", err)
+		return AudioInput{}, fmt.Errorf("failed to open WAV stream: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return AudioInput{}, fmt.Errorf("failed to open WAV stream: %s", resp.Status)
 	}
-	defer audioConfig.Close()
 
-	speechRecognizer, err := speechsdk.NewSpeechRecognizerFromConfig(speechConfig, audioConfig)
+	header, err := audioutil.ReadWAVHeader(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to create speech recognizer: %v", err)
+		resp.Body.Close()
+		return AudioInput{}, err
+	}
+	reader, err := audioutil.NewReader(resp.Body, header.Format, audioutil.SpeechInputFormat)
+	if err != nil {
+		resp.Body.Close()
+		return AudioInput{}, err
 	}
-	defer speechRecognizer.Close()
-
-	// Continuous transcription
-	fmt.Println("🎤 Transcribing live stream...")
-
-	done := make(chan struct{})
-
-	// Handle intermediate results
-	speechRecognizer.Recognizing(func(event speechsdk.SpeechRecognitionEventArgs) {
-		fmt.Printf("🔵 Partial: %s\n", event.Result.Text)
-	})
-
-	// Handle final results
-	speechRecognizer.Recognized(func(event speechsdk.SpeechRecognitionEventArgs) {
-		fmt.Printf("✅ Final: %s\n", event.Result.Text)
-	})
 
-	// Handle errors
-	speechRecognizer.Canceled(func(event speechsdk.SpeechRecognitionCanceledEventArgs) {
-		fmt.Printf("❌ Canceled: %v\n", event.ErrorDetails)
-		close(done)
-	})
+	input := ReaderInput(reader, AudioInputFormatPCM)
+	input.onClose = func() {
+		resp.Body.Close()
+	}
+	return input, nil
+}
 
-	// Handle session stop
-	speechRecognizer.SessionStopped(func(event speechsdk.SessionEventArgs) {
-		fmt.Println("🔴 Session stopped")
-		close(done)
-	})
+// startRecognizer creates a recognizer on input and starts it
+func startRecognizer(ctx context.Context, subscriptionKey, region string, input AudioInput, opts *RecognizerOptions) (*Recognizer, error) {
+	// The recognizer runs onClose itself when it fails to initialize
+	recognizer, err := NewRecognizer(subscriptionKey, region, input, opts)
+	if err != nil {
+		return nil, err
+	}
 
-	// Start continuous recognition
-	errChan := speechRecognizer.StartContinuousRecognitionAsync()
-	if err := <-errChan; err != nil {
-		return fmt.Errorf("failed to start continuous recognition: %v", err)
+	if err := recognizer.Start(ctx); err != nil {
+		return nil, err
 	}
 
-	<-done
-	return nil
+	return recognizer, nil
 }
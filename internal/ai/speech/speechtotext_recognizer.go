@@ -0,0 +1,727 @@
+package speech
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/audio"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	speechsdk "github.com/Microsoft/cognitive-services-speech-sdk-go/speech"
+	"github.com/ngothientuong/tngo-ai-svcs/internal/audio/audioutil"
+)
+
+// eventBufferSize is the capacity of each Recognizer event channel
+const eventBufferSize = 64
+
+// AudioInput describes where a Recognizer reads audio from
+type AudioInput struct {
+	// Microphone selects a capture device; DeviceName empty means the default microphone
+	Microphone bool
+	DeviceName string
+
+	// FilePath reads a WAV file directly, other formats are streamed through Reader
+	FilePath string
+
+	// Reader is pushed into the recognizer until EOF, encoded as Format
+	Reader io.Reader
+	Format AudioInputFormat
+
+	// onClose releases resources backing Reader once the session ends
+	onClose func()
+}
+
+// MicrophoneInput reads from the named capture device, or the default microphone when deviceName is empty
+func MicrophoneInput(deviceName string) AudioInput {
+	return AudioInput{Microphone: true, DeviceName: deviceName}
+}
+
+// FileInput reads from an audio file, guessing the format from its extension
+func FileInput(filePath string) AudioInput {
+	return AudioInput{FilePath: filePath, Format: AudioInputFormatFromPath(filePath)}
+}
+
+// ReaderInput reads encoded audio from reader until EOF
+func ReaderInput(reader io.Reader, format AudioInputFormat) AudioInput {
+	return AudioInput{Reader: reader, Format: format}
+}
+
+// ProfanityOption controls how the service returns profane words
+type ProfanityOption string
+
+const (
+	ProfanityMasked  ProfanityOption = "masked"
+	ProfanityRemoved ProfanityOption = "removed"
+	ProfanityRaw     ProfanityOption = "raw"
+)
+
+var profanityOptions = map[ProfanityOption]common.ProfanityOption{
+	ProfanityMasked:  common.Masked,
+	ProfanityRemoved: common.Removed,
+	ProfanityRaw:     common.Raw,
+}
+
+// RecognizerOptions tunes a recognition session
+type RecognizerOptions struct {
+	// Language is the BCP-47 recognition locale, the service default (en-US) is used when empty
+	Language string
+
+	// EndpointID recognizes with a Custom Speech endpoint instead of the base model; Language must match its locale
+	EndpointID string
+
+	// Profanity defaults to the service behaviour (masked) when empty
+	Profanity ProfanityOption
+
+	// PhraseList boosts names and domain terms the base model may miss
+	PhraseList []string
+
+	// SegmentationSilenceTimeout is the silence that ends a phrase (100ms to 5s); zero keeps the service default
+	SegmentationSilenceTimeout time.Duration
+
+	// InitialSilenceTimeout and EndSilenceTimeout bound the silence before and after speech; zero keeps the service defaults
+	InitialSilenceTimeout time.Duration
+	EndSilenceTimeout     time.Duration
+
+	// OutputFormat defaults to detailed; simple results carry no confidence, N-best or word timings
+	OutputFormat OutputFormat
+
+	// DisableWordTimestamps skips word-level timings in detailed results
+	DisableWordTimestamps bool
+
+	// CandidateLanguages identifies the spoken language continuously among up to 10 locales, overriding Language.
+	// The detected locale is reported in TranscriptSegment.Language; it cannot be combined with EndpointID
+	CandidateLanguages []string
+
+	// PronunciationAssessment scores final results against a reference text when set
+	PronunciationAssessment *PronunciationAssessmentConfig
+
+	// KeywordModelPath loads a .table keyword model; recognition then waits for the keyword
+	// and only the utterance following each detected keyword is transcribed
+	KeywordModelPath string
+
+	// VoiceActivity pushes only speech into the recognizer so silence is not billed; it needs PCM or WAV
+	// stream or file input. Result offsets are mapped back to positions in the source audio
+	VoiceActivity *audioutil.VADOptions
+}
+
+// RecognitionEvent carries a partial or final recognition result
+type RecognitionEvent struct {
+	SessionID string
+	Segment   TranscriptSegment
+
+	// Pronunciation is set on final results when pronunciation assessment is enabled
+	Pronunciation *PronunciationAssessmentResult
+}
+
+// KeywordEvent reports a detected keyword in keyword recognition mode
+type KeywordEvent struct {
+	SessionID string
+	Keyword   string
+	Offset    time.Duration
+	Duration  time.Duration
+}
+
+// CanceledEvent reports why recognition was canceled
+type CanceledEvent struct {
+	SessionID    string
+	Reason       string
+	ErrorCode    string
+	ErrorDetails string
+}
+
+// SessionEventType identifies a session lifecycle event
+type SessionEventType string
+
+const (
+	SessionStarted      SessionEventType = "SessionStarted"
+	SessionStopped      SessionEventType = "SessionStopped"
+	SpeechStartDetected SessionEventType = "SpeechStartDetected"
+	SpeechEndDetected   SessionEventType = "SpeechEndDetected"
+)
+
+// SessionEvent reports a session lifecycle change
+type SessionEvent struct {
+	SessionID string
+	Type      SessionEventType
+	Offset    time.Duration
+}
+
+/*
+Recognizer is a continuous recognition session whose results are delivered on channels.
+Partial and session events are dropped when their channel is full, final, keyword and canceled events are not,
+so callers must drain Final() and Canceled(), and Keywords() in keyword recognition mode. All channels are closed when the session ends.
+*/
+type Recognizer struct {
+	speechConfig *speechsdk.SpeechConfig
+	audioConfig  *audio.AudioConfig
+	recognizer   *speechsdk.SpeechRecognizer
+	input        AudioInput
+	reader       *bufio.Reader
+
+	assessPronunciation bool
+	identifyLanguage    bool
+	keywordModel        *speechsdk.KeywordRecognitionModel
+
+	// voiceActivity enables gate, which drops silence between the reader and the push stream
+	voiceActivity *audioutil.VADOptions
+	gate          *audioutil.Gate
+
+	// streamMu guards audioStream against writes after the session released it
+	streamMu     sync.Mutex
+	audioStream  *audio.PushAudioInputStream
+	streamClosed bool
+
+	partial  chan RecognitionEvent
+	final    chan RecognitionEvent
+	canceled chan CanceledEvent
+	session  chan SessionEvent
+	keywords chan KeywordEvent
+
+	mu        sync.RWMutex
+	closed    bool
+	closing   chan struct{}
+	done      chan struct{}
+	stopOnce  sync.Once
+	startOnce sync.Once
+	err       error
+}
+
+// NewRecognizer creates a recognition session; call Start to begin recognizing
+func NewRecognizer(subscriptionKey, region string, input AudioInput, opts *RecognizerOptions) (*Recognizer, error) {
+	r := &Recognizer{
+		input:    input,
+		partial:  make(chan RecognitionEvent, eventBufferSize),
+		final:    make(chan RecognitionEvent, eventBufferSize),
+		canceled: make(chan CanceledEvent, eventBufferSize),
+		session:  make(chan SessionEvent, eventBufferSize),
+		keywords: make(chan KeywordEvent, eventBufferSize),
+		closing:  make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	if err := r.init(subscriptionKey, region, opts); err != nil {
+		r.release()
+		return nil, err
+	}
+
+	r.registerHandlers()
+	return r, nil
+}
+
+func (r *Recognizer) init(subscriptionKey, region string, opts *RecognizerOptions) error {
+	var err error
+	r.speechConfig, err = speechsdk.NewSpeechConfigFromSubscription(subscriptionKey, region)
+	if err != nil {
+		return fmt.Errorf("failed to create speech config: %v", err)
+	}
+
+	if err := opts.applyTuning(r.speechConfig); err != nil {
+		return err
+	}
+	if opts != nil && opts.Language != "" && !opts.identifiesLanguage() {
+		if err := r.speechConfig.SetSpeechRecognitionLanguage(opts.Language); err != nil {
+			return fmt.Errorf("failed to set recognition language: %v", err)
+		}
+	}
+	if opts != nil && opts.EndpointID != "" {
+		if err := r.speechConfig.SetEndpointID(opts.EndpointID); err != nil {
+			return fmt.Errorf("failed to set custom endpoint: %v", err)
+		}
+	}
+	if opts != nil && opts.Profanity != "" {
+		profanity, ok := profanityOptions[opts.Profanity]
+		if !ok {
+			return fmt.Errorf("unsupported profanity option: %s", opts.Profanity)
+		}
+		if err := r.speechConfig.SetProfanity(profanity); err != nil {
+			return fmt.Errorf("failed to set profanity option: %v", err)
+		}
+	}
+	if opts != nil && opts.PronunciationAssessment != nil {
+		params, err := opts.PronunciationAssessment.params()
+		if err != nil {
+			return err
+		}
+		if err := r.speechConfig.SetPropertyByString(pronunciationAssessmentParams, params); err != nil {
+			return fmt.Errorf("failed to enable pronunciation assessment: %v", err)
+		}
+		r.assessPronunciation = true
+	}
+	if opts != nil && opts.KeywordModelPath != "" {
+		r.keywordModel, err = speechsdk.NewKeywordRecognitionModelFromFile(opts.KeywordModelPath)
+		if err != nil {
+			return fmt.Errorf("failed to load keyword model: %v", err)
+		}
+	}
+	if opts != nil {
+		r.voiceActivity = opts.VoiceActivity
+	}
+
+	if err := r.initAudio(); err != nil {
+		return err
+	}
+
+	if opts.identifiesLanguage() {
+		languages, err := speechsdk.NewAutoDetectSourceLanguageConfigFromLanguages(opts.CandidateLanguages)
+		if err != nil {
+			return fmt.Errorf("failed to create language identification config: %v", err)
+		}
+		defer languages.Close()
+		r.recognizer, err = speechsdk.NewSpeechRecognizerFomAutoDetectSourceLangConfig(r.speechConfig, languages, r.audioConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create speech recognizer: %v", err)
+		}
+		r.identifyLanguage = true
+	} else {
+		r.recognizer, err = speechsdk.NewSpeechRecognizerFromConfig(r.speechConfig, r.audioConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create speech recognizer: %v", err)
+		}
+	}
+
+	if opts != nil && len(opts.PhraseList) > 0 {
+		if err := addPhraseList(r.recognizer, opts.PhraseList); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// detectedLanguage returns the locale identified for a result, empty unless candidate languages are set
+func (r *Recognizer) detectedLanguage(result *speechsdk.SpeechRecognitionResult) string {
+	if !r.identifyLanguage {
+		return ""
+	}
+	return result.Properties.GetProperty(common.SpeechServiceConnectionAutoDetectSourceLanguageResult, "")
+}
+
+func (r *Recognizer) initAudio() error {
+	var err error
+	input := r.input
+
+	switch {
+	case input.Microphone && r.voiceActivity != nil:
+		return fmt.Errorf("voice activity detection needs stream or file input, not a microphone")
+	case input.Microphone && input.DeviceName == "":
+		r.audioConfig, err = audio.NewAudioConfigFromDefaultMicrophoneInput()
+	case input.Microphone:
+		r.audioConfig, err = audio.NewAudioConfigFromMicrophoneInput(input.DeviceName)
+	case input.FilePath != "" && input.Format == AudioInputFormatWAV && r.voiceActivity == nil:
+		r.audioConfig, err = audio.NewAudioConfigFromWavFileInput(input.FilePath)
+	default:
+		return r.initAudioStream()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create audio config: %v", err)
+	}
+
+	return nil
+}
+
+func (r *Recognizer) initAudioStream() error {
+	reader := r.input.Reader
+	if r.input.FilePath != "" {
+		file, err := os.Open(r.input.FilePath)
+		if err != nil {
+			return fmt.Errorf("failed to open audio file: %v", err)
+		}
+		reader = file
+		r.input.onClose = func() { file.Close() }
+	}
+	if reader == nil {
+		return fmt.Errorf("no audio input specified")
+	}
+	r.reader = bufio.NewReader(reader)
+
+	streamFormat, pcmFormat, err := newAudioStreamFormat(r.reader, r.input.Format)
+	if err != nil {
+		return err
+	}
+	defer streamFormat.Close()
+
+	if r.voiceActivity != nil {
+		if pcmFormat == nil {
+			return fmt.Errorf("voice activity detection needs PCM or WAV input, not %s", r.input.Format)
+		}
+		gated, gate, err := audioutil.NewGatedReader(r.reader, *pcmFormat, r.voiceActivity)
+		if err != nil {
+			return fmt.Errorf("failed to create voice activity gate: %v", err)
+		}
+		r.reader, r.gate = bufio.NewReader(gated), gate
+	}
+
+	r.audioStream, err = audio.CreatePushAudioInputStreamFromFormat(streamFormat)
+	if err != nil {
+		return fmt.Errorf("failed to create push audio input stream: %v", err)
+	}
+
+	r.audioConfig, err = audio.NewAudioConfigFromStreamInput(r.audioStream)
+	if err != nil {
+		return fmt.Errorf("failed to create audio config: %v", err)
+	}
+
+	return nil
+}
+
+func (r *Recognizer) registerHandlers() {
+	r.recognizer.SessionStarted(func(event speechsdk.SessionEventArgs) {
+		defer event.Close()
+		r.sendSession(SessionEvent{SessionID: event.SessionID, Type: SessionStarted})
+	})
+
+	r.recognizer.SessionStopped(func(event speechsdk.SessionEventArgs) {
+		defer event.Close()
+		r.sendSession(SessionEvent{SessionID: event.SessionID, Type: SessionStopped})
+		go r.finish(nil)
+	})
+
+	r.recognizer.SpeechStartDetected(func(event speechsdk.RecognitionEventArgs) {
+		defer event.Close()
+		r.sendSession(SessionEvent{SessionID: event.SessionID, Type: SpeechStartDetected, Offset: r.sourceOffset(time.Duration(event.Offset) * ticksPerDuration)})
+	})
+
+	r.recognizer.SpeechEndDetected(func(event speechsdk.RecognitionEventArgs) {
+		defer event.Close()
+		r.sendSession(SessionEvent{SessionID: event.SessionID, Type: SpeechEndDetected, Offset: r.sourceOffset(time.Duration(event.Offset) * ticksPerDuration)})
+	})
+
+	r.recognizer.Recognizing(func(event speechsdk.SpeechRecognitionEventArgs) {
+		defer event.Close()
+		if event.Result.Reason == common.RecognizingKeyword {
+			// Unverified keyword hypotheses are not reported
+			return
+		}
+		segment := TranscriptSegment{
+			ResultID: event.Result.ResultID,
+			Text:     event.Result.Text,
+			Offset:   r.sourceOffset(event.Result.Offset),
+			Duration: event.Result.Duration,
+			Language: r.detectedLanguage(&event.Result),
+		}
+		r.sendPartial(RecognitionEvent{SessionID: event.SessionID, Segment: segment})
+	})
+
+	r.recognizer.Recognized(func(event speechsdk.SpeechRecognitionEventArgs) {
+		defer event.Close()
+		if event.Result.Reason == common.RecognizedKeyword {
+			r.sendKeyword(KeywordEvent{
+				SessionID: event.SessionID,
+				Keyword:   event.Result.Text,
+				Offset:    r.sourceOffset(event.Result.Offset),
+				Duration:  event.Result.Duration,
+			})
+			return
+		}
+		if event.Result.Reason != common.RecognizedSpeech {
+			return
+		}
+		jsonResult := event.Result.Properties.GetProperty(common.SpeechServiceResponseJSONResult, "")
+		segment, err := parseDetailedResult(event.Result.ResultID, event.Result.Text, event.Result.Offset, event.Result.Duration, jsonResult)
+		if err != nil {
+			// Keep the plain text result, only the detailed fields are missing
+			segment = TranscriptSegment{ResultID: event.Result.ResultID, Text: event.Result.Text, Offset: event.Result.Offset, Duration: event.Result.Duration}
+		}
+		r.restoreOffsets(&segment)
+		segment.Language = r.detectedLanguage(&event.Result)
+		final := RecognitionEvent{SessionID: event.SessionID, Segment: segment}
+		if r.assessPronunciation && jsonResult != "" {
+			if assessment, err := parsePronunciationAssessment(jsonResult); err == nil {
+				final.Pronunciation = assessment
+			}
+		}
+		r.sendFinal(final)
+	})
+
+	r.recognizer.Canceled(func(event speechsdk.SpeechRecognitionCanceledEventArgs) {
+		defer event.Close()
+		canceled := CanceledEvent{
+			SessionID:    event.SessionID,
+			Reason:       event.Reason.String(),
+			ErrorCode:    event.ErrorCode.String(),
+			ErrorDetails: event.ErrorDetails,
+		}
+		r.sendCanceled(canceled)
+
+		var err error
+		if event.Reason == common.Error {
+			err = fmt.Errorf("recognition canceled: %s", event.ErrorDetails)
+		}
+		go r.finish(err)
+	})
+}
+
+// Start begins continuous recognition, or keyword recognition when a keyword model is loaded; cancelling ctx stops the session
+func (r *Recognizer) Start(ctx context.Context) error {
+	var startErr error
+	r.startOnce.Do(func() {
+		var errChan chan error
+		if r.keywordModel != nil {
+			errChan = r.recognizer.StartKeywordRecognitionAsync(*r.keywordModel)
+		} else {
+			errChan = r.recognizer.StartContinuousRecognitionAsync()
+		}
+		if err := <-errChan; err != nil {
+			startErr = fmt.Errorf("failed to start recognition: %v", err)
+			r.finish(startErr)
+			return
+		}
+
+		if r.reader != nil {
+			go r.pump()
+		}
+
+		go func() {
+			select {
+			case <-ctx.Done():
+				r.finish(nil)
+			case <-r.done:
+			}
+		}()
+	})
+	return startErr
+}
+
+// Stop ends the session, waits for the SDK to stop and releases its handles
+func (r *Recognizer) Stop() error {
+	r.finish(nil)
+	return r.Err()
+}
+
+// Wait blocks until the session ends and returns its error, if any
+func (r *Recognizer) Wait() error {
+	<-r.done
+	return r.Err()
+}
+
+// Done is closed once the session has ended and all channels are closed
+func (r *Recognizer) Done() <-chan struct{} {
+	return r.done
+}
+
+// Err returns the error that ended the session, if any
+func (r *Recognizer) Err() error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.err
+}
+
+// Partial delivers intermediate results while speech is being recognized
+func (r *Recognizer) Partial() <-chan RecognitionEvent {
+	return r.partial
+}
+
+// Final delivers final results, one per recognized utterance
+func (r *Recognizer) Final() <-chan RecognitionEvent {
+	return r.final
+}
+
+// Canceled delivers cancellation notices, including the end of an input stream
+func (r *Recognizer) Canceled() <-chan CanceledEvent {
+	return r.canceled
+}
+
+// Keywords delivers detected keywords in keyword recognition mode
+func (r *Recognizer) Keywords() <-chan KeywordEvent {
+	return r.keywords
+}
+
+// Session delivers session and speech detection lifecycle events
+func (r *Recognizer) Session() <-chan SessionEvent {
+	return r.session
+}
+
+// VoiceActivityStats reports how much of the input was speech; ok is false without RecognizerOptions.VoiceActivity
+func (r *Recognizer) VoiceActivityStats() (stats audioutil.VADStats, ok bool) {
+	if r.gate == nil {
+		return stats, false
+	}
+	return r.gate.Stats(), true
+}
+
+// sourceOffset maps an offset in the audio the service received to the source audio
+func (r *Recognizer) sourceOffset(offset time.Duration) time.Duration {
+	if r.gate == nil {
+		return offset
+	}
+	return r.gate.SourceOffset(offset)
+}
+
+// restoreOffsets maps the offsets of a segment and its words to the source audio
+func (r *Recognizer) restoreOffsets(segment *TranscriptSegment) {
+	if r.gate == nil {
+		return
+	}
+	segment.Offset = r.gate.SourceOffset(segment.Offset)
+	for i := range segment.NBest {
+		for j := range segment.NBest[i].Words {
+			segment.NBest[i].Words[j].Offset = r.gate.SourceOffset(segment.NBest[i].Words[j].Offset)
+		}
+	}
+}
+
+func (r *Recognizer) finish(err error) {
+	r.stopOnce.Do(func() {
+		// Unblock handlers waiting on full channels before stopping the SDK
+		close(r.closing)
+
+		if r.recognizer != nil {
+			var stopErr error
+			if r.keywordModel != nil {
+				stopErr = <-r.recognizer.StopKeywordRecognitionAsync()
+			} else {
+				stopErr = <-r.recognizer.StopContinuousRecognitionAsync()
+			}
+			if stopErr != nil && err == nil {
+				err = fmt.Errorf("failed to stop recognition: %v", stopErr)
+			}
+		}
+
+		r.mu.Lock()
+		r.closed = true
+		r.err = err
+		close(r.partial)
+		close(r.final)
+		close(r.canceled)
+		close(r.session)
+		close(r.keywords)
+		r.mu.Unlock()
+
+		r.release()
+		close(r.done)
+	})
+	<-r.done
+}
+
+func (r *Recognizer) release() {
+	if r.recognizer != nil {
+		r.recognizer.Close()
+	}
+	if r.audioConfig != nil {
+		r.audioConfig.Close()
+	}
+	r.streamMu.Lock()
+	if r.audioStream != nil && !r.streamClosed {
+		r.audioStream.Close()
+	}
+	r.streamClosed = true
+	r.streamMu.Unlock()
+	if r.keywordModel != nil {
+		r.keywordModel.Close()
+	}
+	if r.speechConfig != nil {
+		r.speechConfig.Close()
+	}
+	if r.input.onClose != nil {
+		r.input.onClose()
+	}
+}
+
+// pump copies the input reader into the push stream and signals the end of the stream at EOF
+func (r *Recognizer) pump() {
+	defer r.closeAudio()
+
+	data := make([]byte, 4096)
+	for {
+		n, err := r.reader.Read(data)
+		if n > 0 {
+			if writeErr := r.writeAudio(data[:n]); writeErr != nil {
+				go r.finish(writeErr)
+				return
+			}
+		}
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			go r.finish(fmt.Errorf("failed to read audio: %v", err))
+			return
+		}
+	}
+}
+
+// writeAudio pushes encoded audio into the recognizer's input stream
+func (r *Recognizer) writeAudio(data []byte) error {
+	r.streamMu.Lock()
+	defer r.streamMu.Unlock()
+	if r.audioStream == nil || r.streamClosed {
+		return fmt.Errorf("audio stream is closed")
+	}
+	if err := r.audioStream.Write(data); err != nil {
+		return fmt.Errorf("failed to write audio: %v", err)
+	}
+	return nil
+}
+
+// closeAudio signals the end of the input stream so the recognizer can flush its last results
+func (r *Recognizer) closeAudio() {
+	r.streamMu.Lock()
+	defer r.streamMu.Unlock()
+	if r.audioStream != nil && !r.streamClosed {
+		r.audioStream.CloseStream()
+	}
+}
+
+func (r *Recognizer) sendPartial(event RecognitionEvent) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		return
+	}
+	select {
+	case r.partial <- event:
+	default:
+	}
+}
+
+func (r *Recognizer) sendSession(event SessionEvent) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		return
+	}
+	select {
+	case r.session <- event:
+	default:
+	}
+}
+
+func (r *Recognizer) sendFinal(event RecognitionEvent) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		return
+	}
+	select {
+	case r.final <- event:
+	case <-r.closing:
+	}
+}
+
+func (r *Recognizer) sendCanceled(event CanceledEvent) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		return
+	}
+	select {
+	case r.canceled <- event:
+	case <-r.closing:
+	}
+}
+
+func (r *Recognizer) sendKeyword(event KeywordEvent) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		return
+	}
+	select {
+	case r.keywords <- event:
+	case <-r.closing:
+	}
+}
@@ -0,0 +1,412 @@
+package speech
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+TranscriptStore keeps recognition results in an append-only JSON Lines file:
+✅ One record per session start, final segment and session end, so a crash loses at most one line
+✅ A line cut short by a crash is terminated on open and skipped when read, see CorruptLines
+✅ Sessions written concurrently are interleaved and regrouped when read
+✅ Full-text search across sessions and export to SRT, WebVTT, JSON and text
+*/
+
+// transcriptRecord is one line of the store
+type transcriptRecord struct {
+	Type      string             `json:"type"` // session, segment or end
+	SessionID string             `json:"sessionId"`
+	Time      time.Time          `json:"time"`
+	Source    string             `json:"source,omitempty"`
+	Language  string             `json:"language,omitempty"`
+	Segment   *TranscriptSegment `json:"segment,omitempty"`
+}
+
+const (
+	transcriptRecordSession = "session"
+	transcriptRecordSegment = "segment"
+	transcriptRecordEnd     = "end"
+)
+
+// maxTranscriptRecordSize bounds one line, detailed results with N-best word timings can be large
+const maxTranscriptRecordSize = 16 << 20
+
+// TranscriptStore persists transcripts of recognition sessions to a JSONL file
+type TranscriptStore struct {
+	path    string
+	mu      sync.Mutex
+	file    *os.File
+	corrupt []int
+}
+
+// StoredSession summarizes a stored recognition session
+type StoredSession struct {
+	ID           string        `json:"id"`
+	Source       string        `json:"source,omitempty"`
+	Language     string        `json:"language,omitempty"`
+	StartedAt    time.Time     `json:"startedAt"`
+	EndedAt      *time.Time    `json:"endedAt,omitempty"` // Nil while running or after a crash
+	SegmentCount int           `json:"segmentCount"`
+	Duration     time.Duration `json:"duration"`
+}
+
+// StoredSegment is a final result and when it was stored; its Language is the detected locale, or the session language
+type StoredSegment struct {
+	TranscriptSegment
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+// StoredTranscript is a stored session with all of its segments
+type StoredTranscript struct {
+	StoredSession
+	Segments []StoredSegment `json:"segments"`
+}
+
+// TranscriptMatch is a segment found by a search
+type TranscriptMatch struct {
+	Session StoredSession `json:"session"`
+	Segment StoredSegment `json:"segment"`
+}
+
+// OpenTranscriptStore opens or creates the store at path, creating its directory when needed
+func OpenTranscriptStore(path string) (*TranscriptStore, error) {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create transcript store directory: %v", err)
+		}
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open transcript store: %v", err)
+	}
+	if err := terminateLastLine(path, file); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &TranscriptStore{path: path, file: file}, nil
+}
+
+// terminateLastLine ends a line cut short by a crash, so the next record starts on a line of its own
+func terminateLastLine(path string, file *os.File) error {
+	reader, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open transcript store: %v", err)
+	}
+	defer reader.Close()
+
+	info, err := reader.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to read transcript store: %v", err)
+	}
+	if info.Size() == 0 {
+		return nil
+	}
+	last := make([]byte, 1)
+	if _, err := reader.ReadAt(last, info.Size()-1); err != nil {
+		return fmt.Errorf("failed to read transcript store: %v", err)
+	}
+	if last[0] == '\n' {
+		return nil
+	}
+	if _, err := file.Write([]byte{'\n'}); err != nil {
+		return fmt.Errorf("failed to repair transcript store: %v", err)
+	}
+	return nil
+}
+
+// Path returns the file backing the store
+func (s *TranscriptStore) Path() string {
+	return s.path
+}
+
+// Close closes the store file
+func (s *TranscriptStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// CorruptLines returns the line numbers skipped by the last read because they could not be decoded,
+// such as a record cut short by a crash while writing
+func (s *TranscriptStore) CorruptLines() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]int(nil), s.corrupt...)
+}
+
+// StartSession records a new session, such as a file name or stream URL for source, and returns its ID
+func (s *TranscriptStore) StartSession(source, language string) (string, error) {
+	id := uuid.NewString()
+	record := transcriptRecord{Type: transcriptRecordSession, SessionID: id, Time: time.Now().UTC(), Source: source, Language: language}
+	if err := s.write(record); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// AppendSegment records a final result of a session; language is the session locale, used when the segment has no detected language
+func (s *TranscriptStore) AppendSegment(sessionID string, segment TranscriptSegment, language string) error {
+	if segment.Language != "" {
+		language = segment.Language
+	}
+	return s.write(transcriptRecord{Type: transcriptRecordSegment, SessionID: sessionID, Time: time.Now().UTC(), Language: language, Segment: &segment})
+}
+
+// EndSession records that a session has ended
+func (s *TranscriptStore) EndSession(sessionID string) error {
+	return s.write(transcriptRecord{Type: transcriptRecordEnd, SessionID: sessionID, Time: time.Now().UTC()})
+}
+
+// SaveTranscript stores a finished transcript as one session and returns its ID
+func (s *TranscriptStore) SaveTranscript(source, language string, transcript *Transcript) (string, error) {
+	id, err := s.StartSession(source, language)
+	if err != nil {
+		return "", err
+	}
+	for _, segment := range transcript.Segments {
+		if err := s.AppendSegment(id, segment, language); err != nil {
+			return "", err
+		}
+	}
+	return id, s.EndSession(id)
+}
+
+// Record stores the final results of a running session and forwards them on the returned channel,
+// which callers drain instead of the recognizer's Final channel; the session ends with the channel
+func (s *TranscriptStore) Record(recognizer *Recognizer, source, language string) (string, <-chan RecognitionEvent, error) {
+	id, err := s.StartSession(source, language)
+	if err != nil {
+		return "", nil, err
+	}
+
+	final := make(chan RecognitionEvent, eventBufferSize)
+	go func() {
+		defer close(final)
+		defer s.EndSession(id)
+		for event := range recognizer.Final() {
+			// A failed write must not stall recognition, the event is still delivered
+			s.AppendSegment(id, event.Segment, language)
+			final <- event
+		}
+	}()
+	return id, final, nil
+}
+
+func (s *TranscriptStore) write(record transcriptRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode transcript record: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write transcript record: %v", err)
+	}
+	return nil
+}
+
+// Sessions returns a summary of every stored session, most recent first
+func (s *TranscriptStore) Sessions() ([]StoredSession, error) {
+	transcripts, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]StoredSession, len(transcripts))
+	for i, transcript := range transcripts {
+		sessions[i] = transcript.StoredSession
+	}
+	return sessions, nil
+}
+
+// Session returns a stored session by ID or by a unique ID prefix
+func (s *TranscriptStore) Session(id string) (*StoredTranscript, error) {
+	transcripts, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	var found *StoredTranscript
+	for _, transcript := range transcripts {
+		if transcript.ID == id {
+			return transcript, nil
+		}
+		if strings.HasPrefix(transcript.ID, id) {
+			if found != nil {
+				return nil, fmt.Errorf("session ID prefix %s is ambiguous", id)
+			}
+			found = transcript
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("session %s not found", id)
+	}
+	return found, nil
+}
+
+// Search returns the segments containing every word of query, ignoring case, most recent session first;
+// limit <= 0 returns all matches
+func (s *TranscriptStore) Search(query string, limit int) ([]TranscriptMatch, error) {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("search query is empty")
+	}
+
+	transcripts, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []TranscriptMatch
+	for _, transcript := range transcripts {
+		for _, segment := range transcript.Segments {
+			if !containsAll(strings.ToLower(segment.Text), terms) {
+				continue
+			}
+			matches = append(matches, TranscriptMatch{Session: transcript.StoredSession, Segment: segment})
+			if limit > 0 && len(matches) == limit {
+				return matches, nil
+			}
+		}
+	}
+	return matches, nil
+}
+
+func containsAll(text string, terms []string) bool {
+	for _, term := range terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// load reads the whole store and regroups its records by session, skipping lines that cannot be decoded
+func (s *TranscriptStore) load() ([]*StoredTranscript, error) {
+	file, err := os.Open(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open transcript store: %v", err)
+	}
+	defer file.Close()
+
+	bySession := make(map[string]*StoredTranscript)
+	var transcripts []*StoredTranscript
+	session := func(id string) *StoredTranscript {
+		transcript, ok := bySession[id]
+		if !ok {
+			transcript = &StoredTranscript{StoredSession: StoredSession{ID: id}}
+			bySession[id] = transcript
+			transcripts = append(transcripts, transcript)
+		}
+		return transcript
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxTranscriptRecordSize)
+	var corrupt []int
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+
+		// A crash while writing leaves a partial record, the rest of the store is still readable
+		var record transcriptRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil || record.SessionID == "" {
+			corrupt = append(corrupt, line)
+			continue
+		}
+
+		transcript := session(record.SessionID)
+		switch record.Type {
+		case transcriptRecordSession:
+			transcript.Source = record.Source
+			transcript.Language = record.Language
+			transcript.StartedAt = record.Time
+		case transcriptRecordSegment:
+			if record.Segment == nil {
+				continue
+			}
+			segment := *record.Segment
+			if segment.Language == "" {
+				segment.Language = record.Language
+			}
+			transcript.Segments = append(transcript.Segments, StoredSegment{TranscriptSegment: segment, RecordedAt: record.Time})
+			transcript.SegmentCount++
+			if end := record.Segment.Offset + record.Segment.Duration; end > transcript.Duration {
+				transcript.Duration = end
+			}
+		case transcriptRecordEnd:
+			endedAt := record.Time
+			transcript.EndedAt = &endedAt
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read transcript store: %v", err)
+	}
+	s.mu.Lock()
+	s.corrupt = corrupt
+	s.mu.Unlock()
+
+	sort.SliceStable(transcripts, func(i, j int) bool {
+		return transcripts[i].StartedAt.After(transcripts[j].StartedAt)
+	})
+	return transcripts, nil
+}
+
+// Transcript returns the stored segments as a transcript, for captioning and conversation views
+func (t *StoredTranscript) Transcript() *Transcript {
+	transcript := &Transcript{Duration: t.Duration, Segments: make([]TranscriptSegment, len(t.Segments))}
+	for i, segment := range t.Segments {
+		transcript.Segments[i] = segment.TranscriptSegment
+	}
+	return transcript
+}
+
+// Export writes the transcript as srt, vtt, json or txt
+func (t *StoredTranscript) Export(w io.Writer, format string, opts *CaptionOptions) error {
+	switch format {
+	case "srt":
+		return WriteSRT(w, BuildCues(t.Transcript().Segments, opts))
+	case "vtt":
+		return WriteWebVTT(w, BuildCues(t.Transcript().Segments, opts))
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(t); err != nil {
+			return fmt.Errorf("failed to write transcript JSON: %v", err)
+		}
+		return nil
+	case "txt":
+		return t.WriteText(w)
+	}
+	return fmt.Errorf("unsupported export format: %s", format)
+}
+
+// WriteText writes one line per segment with its start time, speaker when known, and language
+func (t *StoredTranscript) WriteText(w io.Writer) error {
+	for _, segment := range t.Segments {
+		label := ""
+		if segment.Speaker != 0 {
+			label = SpeakerLabel(segment.Speaker) + ": "
+		}
+		language := ""
+		if segment.Language != "" && segment.Language != t.Language {
+			language = fmt.Sprintf(" (%s)", segment.Language)
+		}
+		if _, err := fmt.Fprintf(w, "[%s] %s%s%s\n", formatTimestamp(segment.Offset), label, segment.Text, language); err != nil {
+			return fmt.Errorf("failed to write segment: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package speech
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTranscriptStoreRecoversFromPartialWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "transcripts.jsonl")
+	store, err := OpenTranscriptStore(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	first, err := store.SaveTranscript("first.wav", "en-US", &Transcript{Segments: []TranscriptSegment{
+		{Text: "hello world", Offset: 0, Duration: time.Second},
+		{Text: "second segment", Offset: time.Second, Duration: time.Second},
+	}})
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	store.Close()
+
+	// Cut the file in the middle of its last record, as a crash while writing would
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if err := os.WriteFile(path, data[:len(data)-10], 0644); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	store, err = OpenTranscriptStore(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer store.Close()
+	second, err := store.SaveTranscript("second.wav", "fr-FR", &Transcript{Segments: []TranscriptSegment{
+		{Text: "bonjour", Offset: 0, Duration: time.Second},
+	}})
+	if err != nil {
+		t.Fatalf("save after reopen: %v", err)
+	}
+
+	sessions, err := store.Sessions()
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %+v", sessions)
+	}
+	if lines := store.CorruptLines(); !reflect.DeepEqual(lines, []int{4}) {
+		t.Errorf("corrupt lines = %v, want [4]", lines)
+	}
+
+	// The first session lost only its end record
+	transcript, err := store.Session(first)
+	if err != nil {
+		t.Fatalf("first session: %v", err)
+	}
+	if transcript.EndedAt != nil || len(transcript.Segments) != 2 {
+		t.Errorf("first session = %+v", transcript)
+	}
+
+	transcript, err = store.Session(second)
+	if err != nil {
+		t.Fatalf("second session: %v", err)
+	}
+	if transcript.EndedAt == nil || len(transcript.Segments) != 1 || transcript.Segments[0].Text != "bonjour" || transcript.Segments[0].Language != "fr-FR" {
+		t.Errorf("second session = %+v", transcript)
+	}
+}
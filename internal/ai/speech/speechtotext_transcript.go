@@ -0,0 +1,135 @@
+package speech
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ticksPerDuration converts the 100-nanosecond ticks used by the Speech service into time.Duration
+const ticksPerDuration = 100 * time.Nanosecond
+
+// Transcript is the typed result of a transcription
+type Transcript struct {
+	Segments []TranscriptSegment `json:"segments"`
+	Duration time.Duration       `json:"duration"`
+}
+
+// TranscriptSegment is a single final recognition result
+type TranscriptSegment struct {
+	ResultID   string        `json:"resultId"`
+	Text       string        `json:"text"`
+	Offset     time.Duration `json:"offset"`
+	Duration   time.Duration `json:"duration"`
+	Confidence float64       `json:"confidence"`
+	Channel    int           `json:"channel,omitempty"`
+	Speaker    int           `json:"speaker,omitempty"`
+	Language   string        `json:"language,omitempty"` // Detected locale when candidate languages are identified
+	NBest      []NBestEntry  `json:"nBest,omitempty"`
+}
+
+// NBestEntry is one recognition alternative of a segment
+type NBestEntry struct {
+	Confidence float64      `json:"confidence"`
+	Lexical    string       `json:"lexical"`
+	ITN        string       `json:"itn"`
+	MaskedITN  string       `json:"maskedItn"`
+	Display    string       `json:"display"`
+	Words      []WordTiming `json:"words,omitempty"`
+}
+
+// WordTiming is a recognized word with its position in the audio
+type WordTiming struct {
+	Word       string        `json:"word"`
+	Offset     time.Duration `json:"offset"`
+	Duration   time.Duration `json:"duration"`
+	Confidence float64       `json:"confidence"`
+}
+
+// Text returns the display text of all segments joined by spaces
+func (t *Transcript) Text() string {
+	texts := make([]string, 0, len(t.Segments))
+	for _, segment := range t.Segments {
+		if segment.Text != "" {
+			texts = append(texts, segment.Text)
+		}
+	}
+	return strings.Join(texts, " ")
+}
+
+// Words returns the word timings of the best alternative, if the service returned them
+func (s *TranscriptSegment) Words() []WordTiming {
+	if len(s.NBest) == 0 {
+		return nil
+	}
+	return s.NBest[0].Words
+}
+
+// detailedResult mirrors the JSON payload returned with the detailed output format
+type detailedResult struct {
+	ID                string `json:"Id"`
+	RecognitionStatus string `json:"RecognitionStatus"`
+	Offset            int64  `json:"Offset"`
+	Duration          int64  `json:"Duration"`
+	DisplayText       string `json:"DisplayText"`
+	NBest             []struct {
+		Confidence float64 `json:"Confidence"`
+		Lexical    string  `json:"Lexical"`
+		ITN        string  `json:"ITN"`
+		MaskedITN  string  `json:"MaskedITN"`
+		Display    string  `json:"Display"`
+		Words      []struct {
+			Word       string  `json:"Word"`
+			Offset     int64   `json:"Offset"`
+			Duration   int64   `json:"Duration"`
+			Confidence float64 `json:"Confidence"`
+		} `json:"Words"`
+	} `json:"NBest"`
+}
+
+// parseDetailedResult builds a segment from the detailed JSON result of a recognition
+func parseDetailedResult(resultID, text string, offset, duration time.Duration, jsonResult string) (TranscriptSegment, error) {
+	segment := TranscriptSegment{
+		ResultID: resultID,
+		Text:     text,
+		Offset:   offset,
+		Duration: duration,
+	}
+	if jsonResult == "" {
+		return segment, nil
+	}
+
+	var result detailedResult
+	if err := json.Unmarshal([]byte(jsonResult), &result); err != nil {
+		return segment, fmt.Errorf("failed to decode recognition result: %v", err)
+	}
+
+	for _, nbest := range result.NBest {
+		entry := NBestEntry{
+			Confidence: nbest.Confidence,
+			Lexical:    nbest.Lexical,
+			ITN:        nbest.ITN,
+			MaskedITN:  nbest.MaskedITN,
+			Display:    nbest.Display,
+		}
+		for _, word := range nbest.Words {
+			entry.Words = append(entry.Words, WordTiming{
+				Word:       word.Word,
+				Offset:     time.Duration(word.Offset) * ticksPerDuration,
+				Duration:   time.Duration(word.Duration) * ticksPerDuration,
+				Confidence: word.Confidence,
+			})
+		}
+		segment.NBest = append(segment.NBest, entry)
+	}
+
+	if len(segment.NBest) > 0 {
+		segment.Confidence = segment.NBest[0].Confidence
+	}
+	if segment.Text == "" {
+		segment.Text = result.DisplayText
+	}
+
+	return segment, nil
+}
@@ -45,8 +45,13 @@ func NewTextToSpeechClient(endpoint, key, region string) *TextToSpeechClient {
 }
 
 func (c *TextToSpeechClient) SynthesizeSpeech(text, voice, format string) ([]byte, error) {
-	url := fmt.Sprintf("https://%s.tts.speech.microsoft.com/cognitiveservices/v1", c.Region)
 	requestBody := fmt.Sprintf("<speak version='1.0' xml:lang='en-US'><voice xml:lang='en-US' xml:gender='Female' name='%s'>%s</voice></speak>", voice, text)
+	return c.SynthesizeSSML(requestBody, format)
+}
+
+// SynthesizeSSML renders an SSML document in the given output format
+func (c *TextToSpeechClient) SynthesizeSSML(ssml, format string) ([]byte, error) {
+	url := fmt.Sprintf("https://%s.tts.speech.microsoft.com/cognitiveservices/v1", c.Region)
 
 	token, err := aitoken.GetToken(c.Endpoint, c.Key)
 	if err != nil {
@@ -61,7 +66,7 @@ func (c *TextToSpeechClient) SynthesizeSpeech(text, voice, format string) ([]byt
 		"User-Agent":               "curl",
 	}
 
-	resp, err := client.Post(url, bytes.NewBuffer([]byte(requestBody)), headers, nil)
+	resp, err := client.Post(url, bytes.NewBuffer([]byte(ssml)), headers, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %v", err)
 	}
@@ -0,0 +1,121 @@
+package speech
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ngothientuong/tngo-ai-svcs/internal/audio/audioutil"
+)
+
+/*
+Reference: https://learn.microsoft.com/en-us/azure/ai-services/speech-service/rest-text-to-speech#audio-outputs
+The service renders PCM at a fixed set of rates. These helpers request the closest one and convert it locally:
+✅ Parsing riff-* and raw-* PCM output format names
+✅ Converting synthesized audio to any sample rate, channel count and sample size
+✅ Stitching utterances into one file with the synthesizer's leading and trailing silence trimmed
+*/
+
+// synthesisPCMRates are the sample rates of the service's riff-*-16bit-mono-pcm output formats, in ascending order
+var synthesisPCMRates = []int{8000, 16000, 22050, 24000, 44100, 48000}
+
+// ParseSynthesisFormat returns the PCM layout of an output format such as riff-24khz-16bit-mono-pcm; riff reports a WAV header
+func ParseSynthesisFormat(name string) (format audioutil.Format, riff bool, err error) {
+	parts := strings.Split(strings.ToLower(name), "-")
+	if len(parts) != 5 || parts[4] != "pcm" || (parts[0] != "riff" && parts[0] != "raw") {
+		return format, false, fmt.Errorf("not a PCM output format: %s", name)
+	}
+
+	rate := strings.TrimSuffix(parts[1], "hz")
+	multiplier := 1
+	if strings.HasSuffix(rate, "k") {
+		rate, multiplier = strings.TrimSuffix(rate, "k"), 1000
+	}
+	sampleRate, err := strconv.Atoi(rate)
+	if err != nil {
+		return format, false, fmt.Errorf("invalid sample rate in output format %s", name)
+	}
+
+	bits, err := strconv.Atoi(strings.TrimSuffix(parts[2], "bit"))
+	if err != nil {
+		return format, false, fmt.Errorf("invalid sample size in output format %s", name)
+	}
+
+	channels := 1
+	switch parts[3] {
+	case "mono":
+	case "stereo":
+		channels = 2
+	default:
+		return format, false, fmt.Errorf("invalid channel layout in output format %s", name)
+	}
+
+	format = audioutil.Format{SampleRate: sampleRate * multiplier, Channels: channels, BitsPerSample: bits}
+	return format, parts[0] == "riff", format.Validate()
+}
+
+// SynthesisFormatFor returns the riff PCM output format whose rate is the smallest one at or above sampleRate
+func SynthesisFormatFor(sampleRate int) string {
+	rate := synthesisPCMRates[len(synthesisPCMRates)-1]
+	for _, candidate := range synthesisPCMRates {
+		if candidate >= sampleRate {
+			rate = candidate
+			break
+		}
+	}
+	if rate%1000 == 0 {
+		return fmt.Sprintf("riff-%dkhz-16bit-mono-pcm", rate/1000)
+	}
+	return fmt.Sprintf("riff-%dhz-16bit-mono-pcm", rate)
+}
+
+// NormalizeSynthesizedAudio converts audio synthesized in a PCM output format to a WAV file in target
+func NormalizeSynthesizedAudio(data []byte, outputFormat string, target audioutil.Format) ([]byte, error) {
+	format, riff, err := ParseSynthesisFormat(outputFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	pcm := data
+	if riff {
+		format, pcm, err = audioutil.DecodeWAV(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode synthesized audio: %v", err)
+		}
+	}
+
+	converted, err := audioutil.Convert(pcm, format, target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert synthesized audio: %v", err)
+	}
+	return audioutil.EncodeWAV(target, converted)
+}
+
+// SynthesizeSpeechAs synthesizes text and returns a WAV file in target, e.g. audioutil.SpeechInputFormat
+func (c *TextToSpeechClient) SynthesizeSpeechAs(text, voice string, target audioutil.Format) ([]byte, error) {
+	if err := target.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid target format: %v", err)
+	}
+
+	outputFormat := SynthesisFormatFor(target.SampleRate)
+	data, err := c.SynthesizeSpeech(text, voice, outputFormat)
+	if err != nil {
+		return nil, err
+	}
+	return NormalizeSynthesizedAudio(data, outputFormat, target)
+}
+
+// SynthesizeSpeechStitched synthesizes each text separately and joins them into one WAV file in target,
+// trimming the silence around every utterance and separating them by gap
+func (c *TextToSpeechClient) SynthesizeSpeechStitched(texts []string, voice string, target audioutil.Format, gap time.Duration, vad *audioutil.VADOptions) ([]byte, error) {
+	clips := make([][]byte, 0, len(texts))
+	for i, text := range texts {
+		clip, err := c.SynthesizeSpeechAs(text, voice, target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to synthesize text %d: %v", i, err)
+		}
+		clips = append(clips, clip)
+	}
+	return audioutil.StitchWAV(clips, gap, vad)
+}
@@ -2,62 +2,36 @@ package speech
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"iter"
+	"mime/multipart"
 	"net/http"
+	"time"
 
-	"github.com/ngothientuong/tngo-ai-svcs/pkg/aitoken"
 	"github.com/ngothientuong/tngo-ai-svcs/pkg/httpclient"
 )
 
-type TextToSpeechCustomConsentClient struct {
-	Endpoint   string
-	Key        string
-	Region     string
-	APIVersion string
-}
-
 type Consent struct {
-	ID                 string `json:"id"`
-	Description        string `json:"description"`
-	ProjectID          string `json:"projectId"`
-	VoiceTalentName    string `json:"voiceTalentName"`
-	CompanyName        string `json:"companyName"`
-	AudioURL           string `json:"audioUrl"`
-	Locale             string `json:"locale"`
-	Status             string `json:"status"`
-	CreatedDateTime    string `json:"createdDateTime"`
-	LastActionDateTime string `json:"lastActionDateTime"`
+	ID                 string            `json:"id"`
+	Description        string            `json:"description"`
+	ProjectID          string            `json:"projectId"`
+	VoiceTalentName    string            `json:"voiceTalentName"`
+	CompanyName        string            `json:"companyName"`
+	AudioURL           string            `json:"audioUrl"`
+	Locale             string            `json:"locale"`
+	Status             CustomVoiceStatus `json:"status"`
+	CreatedDateTime    string            `json:"createdDateTime"`
+	LastActionDateTime string            `json:"lastActionDateTime"`
 }
 
-type ListConsentsResponse struct {
-	Value []Consent `json:"value"`
-}
-
-func NewTextToSpeechCustomConsentClient(endpoint, key, region, apiVersion string) *TextToSpeechCustomConsentClient {
-	return &TextToSpeechCustomConsentClient{
-		Endpoint:   endpoint,
-		Key:        key,
-		Region:     region,
-		APIVersion: apiVersion,
-	}
-}
-
-func (c *TextToSpeechCustomConsentClient) CheckConsentExists(consentID string) (bool, error) {
+func (c *CustomVoiceClient) CheckConsentExists(consentID string) (bool, error) {
 	url := fmt.Sprintf("%s/customvoice/consents/%s?api-version=%s", c.Endpoint, consentID, c.APIVersion)
 
-	token, err := aitoken.GetToken(c.Endpoint, c.Key)
-	if err != nil {
-		return false, fmt.Errorf("failed to get token: %v", err)
-	}
-
 	client := httpclient.NewClient()
-	headers := map[string]string{
-		"Authorization": "Bearer " + token,
-	}
-
-	resp, err := client.Get(url, headers, nil)
+	resp, err := client.Get(url, c.headers(), nil)
 	if err != nil {
 		return false, fmt.Errorf("failed to send request: %v", err)
 	}
@@ -73,20 +47,11 @@ func (c *TextToSpeechCustomConsentClient) CheckConsentExists(consentID string) (
 	}
 }
 
-func (c *TextToSpeechCustomConsentClient) GetConsent(consentID string) (*Consent, error) {
+func (c *CustomVoiceClient) GetConsent(consentID string) (*Consent, error) {
 	url := fmt.Sprintf("%s/customvoice/consents/%s?api-version=%s", c.Endpoint, consentID, c.APIVersion)
 
-	token, err := aitoken.GetToken(c.Endpoint, c.Key)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get token: %v", err)
-	}
-
 	client := httpclient.NewClient()
-	headers := map[string]string{
-		"Authorization": "Bearer " + token,
-	}
-
-	resp, err := client.Get(url, headers, nil)
+	resp, err := client.Get(url, c.headers(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %v", err)
 	}
@@ -106,14 +71,10 @@ func (c *TextToSpeechCustomConsentClient) GetConsent(consentID string) (*Consent
 	return &consent, nil
 }
 
-func (c *TextToSpeechCustomConsentClient) CreateConsent(consentID, description, projectID, voiceTalentName, companyName, audioURL, locale string) (*Consent, error) {
+// CreateConsent creates a consent from the recorded consent statement at audioURL, a public or SAS URL
+func (c *CustomVoiceClient) CreateConsent(consentID, description, projectID, voiceTalentName, companyName, audioURL, locale string) (*Consent, error) {
 	url := fmt.Sprintf("%s/customvoice/consents/%s?api-version=%s", c.Endpoint, consentID, c.APIVersion)
 
-	token, err := aitoken.GetToken(c.Endpoint, c.Key)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get token: %v", err)
-	}
-
 	consent := Consent{
 		ID:              consentID,
 		Description:     description,
@@ -129,12 +90,7 @@ func (c *TextToSpeechCustomConsentClient) CreateConsent(consentID, description,
 	}
 
 	client := httpclient.NewClient()
-	headers := map[string]string{
-		"Authorization": "Bearer " + token,
-		"Content-Type":  "application/json",
-	}
-
-	resp, err := client.Put(url, bytes.NewBuffer(requestBody), headers, nil)
+	resp, err := client.Put(url, bytes.NewBuffer(requestBody), c.headers(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %v", err)
 	}
@@ -154,20 +110,11 @@ func (c *TextToSpeechCustomConsentClient) CreateConsent(consentID, description,
 	return &createdConsent, nil
 }
 
-func (c *TextToSpeechCustomConsentClient) DeleteConsent(consentID string) error {
+func (c *CustomVoiceClient) DeleteConsent(consentID string) error {
 	url := fmt.Sprintf("%s/customvoice/consents/%s?api-version=%s", c.Endpoint, consentID, c.APIVersion)
 
-	token, err := aitoken.GetToken(c.Endpoint, c.Key)
-	if err != nil {
-		return fmt.Errorf("failed to get token: %v", err)
-	}
-
 	client := httpclient.NewClient()
-	headers := map[string]string{
-		"Authorization": "Bearer " + token,
-	}
-
-	resp, err := client.Delete(url, headers, nil)
+	resp, err := client.Delete(url, c.headers(), nil)
 	if err != nil {
 		return fmt.Errorf("failed to send request: %v", err)
 	}
@@ -181,68 +128,48 @@ func (c *TextToSpeechCustomConsentClient) DeleteConsent(consentID string) error
 	return nil
 }
 
-func (c *TextToSpeechCustomConsentClient) ListConsents() ([]Consent, error) {
-	url := fmt.Sprintf("%s/customvoice/consents?api-version=%s", c.Endpoint, c.APIVersion)
-
-	token, err := aitoken.GetToken(c.Endpoint, c.Key)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get token: %v", err)
-	}
-
-	client := httpclient.NewClient()
-	headers := map[string]string{
-		"Authorization": "Bearer " + token,
-	}
-
-	resp, err := client.Get(url, headers, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("failed to list consents: %s", body)
-	}
-
-	var response ListConsentsResponse
-	err = json.NewDecoder(resp.Body).Decode(&response)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode response: %v", err)
-	}
+// Consents iterates over the consents matching opts, following nextLink
+func (c *CustomVoiceClient) Consents(opts *ListOptions) iter.Seq2[Consent, error] {
+	return customVoiceResources[Consent](c, "consents", opts)
+}
 
-	return response.Value, nil
+// ListConsents returns the consents of a project, or of every project when projectID is empty
+func (c *CustomVoiceClient) ListConsents(projectID string) ([]Consent, error) {
+	return collectCustomVoiceResources(c.Consents(projectListOptions(projectID)))
 }
 
-func (c *TextToSpeechCustomConsentClient) PostConsent(consentID, description, projectID, voiceTalentName, companyName, audioURL, locale string) (*Consent, error) {
+// CreateConsentFromFile creates a consent by uploading the recorded consent statement instead of passing its URL
+func (c *CustomVoiceClient) CreateConsentFromFile(consentID, description, projectID, voiceTalentName, companyName, audioPath, locale string) (*Consent, error) {
 	url := fmt.Sprintf("%s/customvoice/consents/%s?api-version=%s", c.Endpoint, consentID, c.APIVersion)
 
-	token, err := aitoken.GetToken(c.Endpoint, c.Key)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get token: %v", err)
+	var requestBody bytes.Buffer
+	form := multipart.NewWriter(&requestBody)
+	fields := map[string]string{
+		"description":     description,
+		"projectId":       projectID,
+		"voiceTalentName": voiceTalentName,
+		"companyName":     companyName,
+		"locale":          locale,
 	}
-
-	consent := Consent{
-		ID:              consentID,
-		Description:     description,
-		ProjectID:       projectID,
-		VoiceTalentName: voiceTalentName,
-		CompanyName:     companyName,
-		AudioURL:        audioURL,
-		Locale:          locale,
+	for name, value := range fields {
+		if err := form.WriteField(name, value); err != nil {
+			return nil, fmt.Errorf("failed to write form field %s: %v", name, err)
+		}
 	}
-	requestBody, err := json.Marshal(consent)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %v", err)
+	if err := addFormFile(form, "audiodata", audioPath); err != nil {
+		return nil, err
+	}
+	if err := form.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close form: %v", err)
 	}
 
 	client := httpclient.NewClient()
 	headers := map[string]string{
-		"Authorization": "Bearer " + token,
-		"Content-Type":  "application/json",
+		"Ocp-Apim-Subscription-Key": c.Key,
+		"Content-Type":              form.FormDataContentType(),
 	}
 
-	resp, err := client.Post(url, bytes.NewBuffer(requestBody), headers, nil)
+	resp, err := client.Post(url, &requestBody, headers, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %v", err)
 	}
@@ -250,7 +177,7 @@ func (c *TextToSpeechCustomConsentClient) PostConsent(consentID, description, pr
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("failed to post consent: %s", body)
+		return nil, fmt.Errorf("failed to create consent: %s", body)
 	}
 
 	var createdConsent Consent
@@ -261,3 +188,21 @@ func (c *TextToSpeechCustomConsentClient) PostConsent(consentID, description, pr
 
 	return &createdConsent, nil
 }
+
+// WaitForConsent polls the consent until the service has verified the voice talent statement
+func (c *CustomVoiceClient) WaitForConsent(ctx context.Context, consentID string, interval time.Duration) (*Consent, error) {
+	var consent *Consent
+	err := waitForCustomVoiceStatus(ctx, interval, "consent "+consentID, func() (CustomVoiceStatus, error) {
+		var err error
+		consent, err = c.GetConsent(consentID)
+		if err != nil {
+			return "", err
+		}
+		return consent.Status, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return consent, nil
+}
@@ -0,0 +1,186 @@
+package speech
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"time"
+
+	"github.com/ngothientuong/tngo-ai-svcs/pkg/httpclient"
+)
+
+/*
+Reference: https://learn.microsoft.com/en-us/rest/api/aiservices/speechapi/endpoints?view=rest-aiservices-speechapi-2024-02-01-preview
+An endpoint hosts a trained model for synthesis:
+✅ Deploy a model, then get, list and delete endpoints
+✅ Suspend an endpoint to stop hosting charges and resume it later; both run as operations
+Synthesize with the endpoint by passing its ID as the deployment ID and the model's voice name.
+*/
+
+// Endpoint kinds
+const (
+	EndpointKindHighPerformance = "HighPerformance"
+	EndpointKindFastResume      = "FastResume" // Resumes faster after being suspended
+)
+
+type EndpointProperties struct {
+	Kind string `json:"kind,omitempty"`
+}
+
+type Endpoint struct {
+	ID                 string              `json:"id"`
+	DisplayName        string              `json:"displayName,omitempty"`
+	Description        string              `json:"description,omitempty"`
+	ProjectID          string              `json:"projectId"`
+	ModelID            string              `json:"modelId"`
+	Properties         *EndpointProperties `json:"properties,omitempty"`
+	Status             CustomVoiceStatus   `json:"status,omitempty"`
+	CreatedDateTime    string              `json:"createdDateTime,omitempty"`
+	LastActionDateTime string              `json:"lastActionDateTime,omitempty"`
+}
+
+// CreateEndpoint deploys a trained model; kind may be empty for the default HighPerformance endpoint
+func (c *CustomVoiceClient) CreateEndpoint(endpointID, projectID, modelID, kind, description string) (*Endpoint, error) {
+	url := fmt.Sprintf("%s/customvoice/endpoints/%s?api-version=%s", c.Endpoint, endpointID, c.APIVersion)
+
+	endpoint := Endpoint{
+		ID:          endpointID,
+		ProjectID:   projectID,
+		ModelID:     modelID,
+		Description: description,
+	}
+	if kind != "" {
+		endpoint.Properties = &EndpointProperties{Kind: kind}
+	}
+	requestBody, err := json.Marshal(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %v", err)
+	}
+
+	client := httpclient.NewClient()
+	resp, err := client.Put(url, bytes.NewBuffer(requestBody), c.headers(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to create endpoint: %s", body)
+	}
+
+	var createdEndpoint Endpoint
+	err = json.NewDecoder(resp.Body).Decode(&createdEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &createdEndpoint, nil
+}
+
+// LookupEndpoint gets an endpoint, with found set to false when it does not exist
+func (c *CustomVoiceClient) LookupEndpoint(endpointID string) (endpoint *Endpoint, found bool, err error) {
+	return lookupCustomVoiceResource[Endpoint](c, "endpoints", endpointID, "endpoint")
+}
+
+func (c *CustomVoiceClient) GetEndpoint(endpointID string) (*Endpoint, error) {
+	url := fmt.Sprintf("%s/customvoice/endpoints/%s?api-version=%s", c.Endpoint, endpointID, c.APIVersion)
+
+	client := httpclient.NewClient()
+	resp, err := client.Get(url, c.headers(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get endpoint: %s", body)
+	}
+
+	var endpoint Endpoint
+	err = json.NewDecoder(resp.Body).Decode(&endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &endpoint, nil
+}
+
+func (c *CustomVoiceClient) DeleteEndpoint(endpointID string) error {
+	url := fmt.Sprintf("%s/customvoice/endpoints/%s?api-version=%s", c.Endpoint, endpointID, c.APIVersion)
+
+	client := httpclient.NewClient()
+	resp, err := client.Delete(url, c.headers(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete endpoint: %s", body)
+	}
+
+	return nil
+}
+
+// Endpoints iterates over the endpoints matching opts, following nextLink
+func (c *CustomVoiceClient) Endpoints(opts *ListOptions) iter.Seq2[Endpoint, error] {
+	return customVoiceResources[Endpoint](c, "endpoints", opts)
+}
+
+// ListEndpoints returns the endpoints of a project, or of every project when projectID is empty
+func (c *CustomVoiceClient) ListEndpoints(projectID string) ([]Endpoint, error) {
+	return collectCustomVoiceResources(c.Endpoints(projectListOptions(projectID)))
+}
+
+// SuspendEndpoint stops hosting the endpoint and returns the operation ID; the endpoint reports Disabled once suspended
+func (c *CustomVoiceClient) SuspendEndpoint(endpointID string) (string, error) {
+	return c.endpointAction(endpointID, "suspend")
+}
+
+// ResumeEndpoint hosts a suspended endpoint again and returns the operation ID
+func (c *CustomVoiceClient) ResumeEndpoint(endpointID string) (string, error) {
+	return c.endpointAction(endpointID, "resume")
+}
+
+func (c *CustomVoiceClient) endpointAction(endpointID, action string) (string, error) {
+	url := fmt.Sprintf("%s/customvoice/endpoints/%s:%s?api-version=%s", c.Endpoint, endpointID, action, c.APIVersion)
+
+	client := httpclient.NewClient()
+	resp, err := client.Post(url, nil, c.headers(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("failed to %s endpoint: %s", action, body)
+	}
+
+	return resp.Header.Get("Operation-Id"), nil
+}
+
+// WaitForEndpoint polls the endpoint until deployment succeeds or fails, or until a suspended endpoint reports Disabled
+func (c *CustomVoiceClient) WaitForEndpoint(ctx context.Context, endpointID string, interval time.Duration) (*Endpoint, error) {
+	var endpoint *Endpoint
+	err := waitForCustomVoiceStatus(ctx, interval, "endpoint "+endpointID, func() (CustomVoiceStatus, error) {
+		var err error
+		endpoint, err = c.GetEndpoint(endpointID)
+		if err != nil {
+			return "", err
+		}
+		return endpoint.Status, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return endpoint, nil
+}
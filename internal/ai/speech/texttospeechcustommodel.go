@@ -0,0 +1,176 @@
+package speech
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"time"
+
+	"github.com/ngothientuong/tngo-ai-svcs/pkg/httpclient"
+)
+
+/*
+Reference: https://learn.microsoft.com/en-us/rest/api/aiservices/speechapi/models?view=rest-aiservices-speechapi-2024-02-01-preview
+A model is a professional voice trained from a consent and a training set of the same project:
+✅ Create a model with a training recipe, the base the voice is trained from
+✅ Get, list and delete models, and poll training until it succeeds or fails
+Training takes hours; deploy the model with CreateEndpoint once it has succeeded.
+*/
+
+// Model recipe kinds
+const (
+	ModelRecipeDefault      = "Default"      // Voice in the training set locale
+	ModelRecipeCrossLingual = "CrossLingual" // Voice speaking another locale than the training set
+	ModelRecipeMultiStyle   = "MultiStyle"   // Voice with preset and custom speaking styles
+)
+
+// ModelRecipe selects the base model and training method; the service fills in the version it used
+type ModelRecipe struct {
+	Kind              string   `json:"kind"`
+	Version           string   `json:"version,omitempty"`
+	Description       string   `json:"description,omitempty"`
+	MinUtteranceCount int      `json:"minUtteranceCount,omitempty"`
+	DatasetLocales    []string `json:"datasetLocales,omitempty"`
+	ModelLocales      []string `json:"modelLocales,omitempty"`
+}
+
+type ModelProperties struct {
+	PresetStyles        []string          `json:"presetStyles,omitempty"`
+	StyleTrainingSetIDs map[string]string `json:"styleTrainingSetIds,omitempty"` // Custom style name to training set ID
+	VoiceStyles         []string          `json:"voiceStyles,omitempty"`
+	FailureReason       string            `json:"failureReason,omitempty"`
+}
+
+type Model struct {
+	ID                 string            `json:"id"`
+	VoiceName          string            `json:"voiceName"`
+	Description        string            `json:"description,omitempty"`
+	Recipe             ModelRecipe       `json:"recipe"`
+	Locale             string            `json:"locale,omitempty"` // Output locale of CrossLingual voices
+	ProjectID          string            `json:"projectId"`
+	ConsentID          string            `json:"consentId"`
+	TrainingSetID      string            `json:"trainingSetId"`
+	Properties         *ModelProperties  `json:"properties,omitempty"`
+	EngineVersion      string            `json:"engineVersion,omitempty"`
+	Status             CustomVoiceStatus `json:"status,omitempty"`
+	CreatedDateTime    string            `json:"createdDateTime,omitempty"`
+	LastActionDateTime string            `json:"lastActionDateTime,omitempty"`
+}
+
+// CreateModel starts training model.ID; VoiceName, ProjectID, ConsentID, TrainingSetID and the recipe kind are required
+func (c *CustomVoiceClient) CreateModel(model Model) (*Model, error) {
+	url := fmt.Sprintf("%s/customvoice/models/%s?api-version=%s", c.Endpoint, model.ID, c.APIVersion)
+
+	if model.ID == "" || model.VoiceName == "" || model.ProjectID == "" || model.ConsentID == "" || model.TrainingSetID == "" {
+		return nil, fmt.Errorf("model ID, voice name, project, consent and training set are required")
+	}
+	if model.Recipe.Kind == "" {
+		model.Recipe.Kind = ModelRecipeDefault
+	}
+	requestBody, err := json.Marshal(model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %v", err)
+	}
+
+	client := httpclient.NewClient()
+	resp, err := client.Put(url, bytes.NewBuffer(requestBody), c.headers(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to create model: %s", body)
+	}
+
+	var createdModel Model
+	err = json.NewDecoder(resp.Body).Decode(&createdModel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &createdModel, nil
+}
+
+// LookupModel gets a model, with found set to false when it does not exist
+func (c *CustomVoiceClient) LookupModel(modelID string) (model *Model, found bool, err error) {
+	return lookupCustomVoiceResource[Model](c, "models", modelID, "model")
+}
+
+func (c *CustomVoiceClient) GetModel(modelID string) (*Model, error) {
+	url := fmt.Sprintf("%s/customvoice/models/%s?api-version=%s", c.Endpoint, modelID, c.APIVersion)
+
+	client := httpclient.NewClient()
+	resp, err := client.Get(url, c.headers(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get model: %s", body)
+	}
+
+	var model Model
+	err = json.NewDecoder(resp.Body).Decode(&model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &model, nil
+}
+
+func (c *CustomVoiceClient) DeleteModel(modelID string) error {
+	url := fmt.Sprintf("%s/customvoice/models/%s?api-version=%s", c.Endpoint, modelID, c.APIVersion)
+
+	client := httpclient.NewClient()
+	resp, err := client.Delete(url, c.headers(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete model: %s", body)
+	}
+
+	return nil
+}
+
+// Models iterates over the models matching opts, following nextLink
+func (c *CustomVoiceClient) Models(opts *ListOptions) iter.Seq2[Model, error] {
+	return customVoiceResources[Model](c, "models", opts)
+}
+
+// ListModels returns the models of a project, or of every project when projectID is empty
+func (c *CustomVoiceClient) ListModels(projectID string) ([]Model, error) {
+	return collectCustomVoiceResources(c.Models(projectListOptions(projectID)))
+}
+
+// WaitForModel polls the model until training succeeds or fails; a failure includes the reason reported by the service
+func (c *CustomVoiceClient) WaitForModel(ctx context.Context, modelID string, interval time.Duration) (*Model, error) {
+	var model *Model
+	err := waitForCustomVoiceStatus(ctx, interval, "model "+modelID, func() (CustomVoiceStatus, error) {
+		var err error
+		model, err = c.GetModel(modelID)
+		if err != nil {
+			return "", err
+		}
+		if model.Status == CustomVoiceStatusFailed && model.Properties != nil && model.Properties.FailureReason != "" {
+			return "", fmt.Errorf("model %s failed: %s", modelID, model.Properties.FailureReason)
+		}
+		return model.Status, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
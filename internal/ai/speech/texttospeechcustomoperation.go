@@ -0,0 +1,67 @@
+package speech
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ngothientuong/tngo-ai-svcs/pkg/httpclient"
+)
+
+/*
+Reference: https://learn.microsoft.com/en-us/rest/api/aiservices/speechapi/operations?view=rest-aiservices-speechapi-2024-02-01-preview
+Training set uploads, model training and endpoint deployment run asynchronously. Requests that start them return an
+Operation-Id header; the operation and the resource itself both report NotStarted, Running, then Succeeded or Failed.
+*/
+
+type Operation struct {
+	ID                 string            `json:"id"`
+	Status             CustomVoiceStatus `json:"status"`
+	CreatedDateTime    string            `json:"createdDateTime"`
+	LastActionDateTime string            `json:"lastActionDateTime"`
+}
+
+func (c *CustomVoiceClient) GetOperation(operationID string) (*Operation, error) {
+	url := fmt.Sprintf("%s/customvoice/operations/%s?api-version=%s", c.Endpoint, operationID, c.APIVersion)
+
+	client := httpclient.NewClient()
+	resp, err := client.Get(url, c.headers(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get operation: %s", body)
+	}
+
+	var operation Operation
+	err = json.NewDecoder(resp.Body).Decode(&operation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &operation, nil
+}
+
+// WaitForOperation polls an operation until it succeeds or fails
+func (c *CustomVoiceClient) WaitForOperation(ctx context.Context, operationID string, interval time.Duration) (*Operation, error) {
+	var operation *Operation
+	err := waitForCustomVoiceStatus(ctx, interval, "operation "+operationID, func() (CustomVoiceStatus, error) {
+		var err error
+		operation, err = c.GetOperation(operationID)
+		if err != nil {
+			return "", err
+		}
+		return operation.Status, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return operation, nil
+}
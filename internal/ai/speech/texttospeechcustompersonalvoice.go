@@ -0,0 +1,310 @@
+package speech
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"iter"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ngothientuong/tngo-ai-svcs/pkg/httpclient"
+)
+
+/*
+Reference: https://learn.microsoft.com/en-us/azure/ai-services/speech-service/personal-voice-create-voice
+A personal voice is cloned from a few seconds of audio of a speaker who recorded a consent:
+✅ Create a personal voice from audio in a blob container or from local files, then get, list and delete it
+✅ List the base models that can render a personal voice, filtered by capability
+✅ Synthesize with a base model and the voice's speaker profile ID through TextToSpeechClient
+*/
+
+// Base model capabilities
+const (
+	BaseModelCapabilityPersonalVoice = "PersonalVoice"
+)
+
+type PersonalVoiceProperties struct {
+	FailureReason string `json:"failureReason,omitempty"`
+}
+
+type PersonalVoice struct {
+	ID                 string                   `json:"id"`
+	DisplayName        string                   `json:"displayName,omitempty"`
+	Description        string                   `json:"description,omitempty"`
+	ProjectID          string                   `json:"projectId"`
+	ConsentID          string                   `json:"consentId"`
+	Audios             *AzureBlobContentSource  `json:"audios,omitempty"` // Request only
+	SpeakerProfileID   string                   `json:"speakerProfileId,omitempty"`
+	Properties         *PersonalVoiceProperties `json:"properties,omitempty"`
+	Status             CustomVoiceStatus        `json:"status,omitempty"`
+	CreatedDateTime    string                   `json:"createdDateTime,omitempty"`
+	LastActionDateTime string                   `json:"lastActionDateTime,omitempty"`
+}
+
+type BaseModel struct {
+	Name               string   `json:"name"`
+	Description        string   `json:"description"`
+	ReleaseDateTime    string   `json:"releaseDateTime"`
+	ExpirationDateTime string   `json:"expirationDateTime,omitempty"`
+	Capabilities       []string `json:"capabilities"`
+}
+
+// HasCapability reports whether the base model supports a capability such as PersonalVoice, ignoring case
+func (m *BaseModel) HasCapability(capability string) bool {
+	for _, c := range m.Capabilities {
+		if strings.EqualFold(c, capability) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreatePersonalVoice creates a personal voice from audio files in a blob container
+func (c *CustomVoiceClient) CreatePersonalVoice(personalVoiceID, projectID, consentID, description string, audios AzureBlobContentSource) (*PersonalVoice, error) {
+	url := fmt.Sprintf("%s/customvoice/personalvoices/%s?api-version=%s", c.Endpoint, personalVoiceID, c.APIVersion)
+
+	if len(audios.Extensions) == 0 {
+		audios.Extensions = []string{".wav"}
+	}
+	personalVoice := PersonalVoice{
+		ID:          personalVoiceID,
+		ProjectID:   projectID,
+		ConsentID:   consentID,
+		Description: description,
+		Audios:      &audios,
+	}
+	requestBody, err := json.Marshal(personalVoice)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %v", err)
+	}
+
+	return c.sendPersonalVoice(http.MethodPut, url, bytes.NewBuffer(requestBody), c.headers())
+}
+
+// CreatePersonalVoiceFromFiles creates a personal voice by uploading local audio samples
+func (c *CustomVoiceClient) CreatePersonalVoiceFromFiles(personalVoiceID, projectID, consentID, description string, audioPaths []string) (*PersonalVoice, error) {
+	url := fmt.Sprintf("%s/customvoice/personalvoices/%s?api-version=%s", c.Endpoint, personalVoiceID, c.APIVersion)
+
+	if len(audioPaths) == 0 {
+		return nil, fmt.Errorf("at least one audio sample is required")
+	}
+
+	var requestBody bytes.Buffer
+	form := multipart.NewWriter(&requestBody)
+	fields := map[string]string{"projectId": projectID, "consentId": consentID, "description": description}
+	for name, value := range fields {
+		if err := form.WriteField(name, value); err != nil {
+			return nil, fmt.Errorf("failed to write form field %s: %v", name, err)
+		}
+	}
+	for _, path := range audioPaths {
+		if err := addFormFile(form, "audiodata", path); err != nil {
+			return nil, err
+		}
+	}
+	if err := form.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close form: %v", err)
+	}
+
+	headers := map[string]string{
+		"Ocp-Apim-Subscription-Key": c.Key,
+		"Content-Type":              form.FormDataContentType(),
+	}
+	// Multipart uploads are created with POST, unlike the JSON create
+	return c.sendPersonalVoice(http.MethodPost, url, &requestBody, headers)
+}
+
+// addFormFile copies a local file into a multipart form
+func addFormFile(form *multipart.Writer, field, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open audio sample: %v", err)
+	}
+	defer file.Close()
+
+	part, err := form.CreateFormFile(field, filepath.Base(path))
+	if err != nil {
+		return fmt.Errorf("failed to add audio sample %s: %v", path, err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return fmt.Errorf("failed to read audio sample %s: %v", path, err)
+	}
+	return nil
+}
+
+// sendPersonalVoice sends a personal voice create request with PUT or POST
+func (c *CustomVoiceClient) sendPersonalVoice(method, url string, requestBody io.Reader, headers map[string]string) (*PersonalVoice, error) {
+	client := httpclient.NewClient()
+	var resp *http.Response
+	var err error
+	if method == http.MethodPost {
+		resp, err = client.Post(url, requestBody, headers, nil)
+	} else {
+		resp, err = client.Put(url, requestBody, headers, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to create personal voice: %s", body)
+	}
+
+	var createdPersonalVoice PersonalVoice
+	err = json.NewDecoder(resp.Body).Decode(&createdPersonalVoice)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &createdPersonalVoice, nil
+}
+
+func (c *CustomVoiceClient) GetPersonalVoice(personalVoiceID string) (*PersonalVoice, error) {
+	url := fmt.Sprintf("%s/customvoice/personalvoices/%s?api-version=%s", c.Endpoint, personalVoiceID, c.APIVersion)
+
+	client := httpclient.NewClient()
+	resp, err := client.Get(url, c.headers(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get personal voice: %s", body)
+	}
+
+	var personalVoice PersonalVoice
+	err = json.NewDecoder(resp.Body).Decode(&personalVoice)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &personalVoice, nil
+}
+
+func (c *CustomVoiceClient) DeletePersonalVoice(personalVoiceID string) error {
+	url := fmt.Sprintf("%s/customvoice/personalvoices/%s?api-version=%s", c.Endpoint, personalVoiceID, c.APIVersion)
+
+	client := httpclient.NewClient()
+	resp, err := client.Delete(url, c.headers(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete personal voice: %s", body)
+	}
+
+	return nil
+}
+
+// PersonalVoices iterates over the personal voices matching opts, following nextLink
+func (c *CustomVoiceClient) PersonalVoices(opts *ListOptions) iter.Seq2[PersonalVoice, error] {
+	return customVoiceResources[PersonalVoice](c, "personalvoices", opts)
+}
+
+// ListPersonalVoices returns the personal voices of a project, or of every project when projectID is empty
+func (c *CustomVoiceClient) ListPersonalVoices(projectID string) ([]PersonalVoice, error) {
+	return collectCustomVoiceResources(c.PersonalVoices(projectListOptions(projectID)))
+}
+
+// WaitForPersonalVoice polls the personal voice until it succeeds or fails
+func (c *CustomVoiceClient) WaitForPersonalVoice(ctx context.Context, personalVoiceID string, interval time.Duration) (*PersonalVoice, error) {
+	var personalVoice *PersonalVoice
+	err := waitForCustomVoiceStatus(ctx, interval, "personal voice "+personalVoiceID, func() (CustomVoiceStatus, error) {
+		var err error
+		personalVoice, err = c.GetPersonalVoice(personalVoiceID)
+		if err != nil {
+			return "", err
+		}
+		if personalVoice.Status == CustomVoiceStatusFailed && personalVoice.Properties != nil && personalVoice.Properties.FailureReason != "" {
+			return "", fmt.Errorf("personal voice %s failed: %s", personalVoiceID, personalVoice.Properties.FailureReason)
+		}
+		return personalVoice.Status, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return personalVoice, nil
+}
+
+// ListBaseModels returns the base models, only those with capability when it is not empty
+func (c *CustomVoiceClient) ListBaseModels(capability string) ([]BaseModel, error) {
+	url := fmt.Sprintf("%s/customvoice/basemodels?api-version=%s", c.Endpoint, c.APIVersion)
+
+	client := httpclient.NewClient()
+	resp, err := client.Get(url, c.headers(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to list base models: %s", body)
+	}
+
+	var baseModels []BaseModel
+	err = json.NewDecoder(resp.Body).Decode(&baseModels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	if capability == "" {
+		return baseModels, nil
+	}
+	filtered := baseModels[:0]
+	for _, baseModel := range baseModels {
+		if baseModel.HasCapability(capability) {
+			filtered = append(filtered, baseModel)
+		}
+	}
+	return filtered, nil
+}
+
+// PersonalVoiceSSML builds the SSML that renders text with a personal voice on a base model such as DragonLatestNeural
+func PersonalVoiceSSML(text, baseModel, speakerProfileID, locale string) (string, error) {
+	var escaped strings.Builder
+	if err := xml.EscapeText(&escaped, []byte(text)); err != nil {
+		return "", fmt.Errorf("failed to escape text: %v", err)
+	}
+	if locale == "" {
+		locale = "en-US"
+	}
+	return fmt.Sprintf("<speak version='1.0' xmlns='http://www.w3.org/2001/10/synthesis' xmlns:mstts='http://www.w3.org/2001/mstts' xml:lang='%s'>"+
+		"<voice name='%s'><mstts:ttsembedding speakerProfileId='%s'><lang xml:lang='%s'>%s</lang></mstts:ttsembedding></voice></speak>",
+		locale, xmlAttr(baseModel), xmlAttr(speakerProfileID), locale, escaped.String()), nil
+}
+
+// xmlAttr escapes a value for a single-quoted XML attribute
+func xmlAttr(value string) string {
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(value))
+	return escaped.String()
+}
+
+// SynthesizePersonalVoice synthesizes text with a personal voice's speaker profile ID on a base model
+func (c *TextToSpeechClient) SynthesizePersonalVoice(text, baseModel, speakerProfileID, locale, format string) ([]byte, error) {
+	if baseModel == "" || speakerProfileID == "" {
+		return nil, fmt.Errorf("a base model and a speaker profile ID are required")
+	}
+	ssml, err := PersonalVoiceSSML(text, baseModel, speakerProfileID, locale)
+	if err != nil {
+		return nil, err
+	}
+	return c.SynthesizeSSML(ssml, format)
+}
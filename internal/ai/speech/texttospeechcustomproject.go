@@ -5,47 +5,32 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"iter"
 	"net/http"
 
 	"github.com/ngothientuong/tngo-ai-svcs/pkg/httpclient"
 )
 
-type TextToSpeechCustomNeuralClient struct {
-	Endpoint   string
-	Key        string
-	Region     string
-	APIVersion string
-}
+// Project kinds
+const (
+	ProjectKindProfessionalVoice = "ProfessionalVoice"
+	ProjectKindPersonalVoice     = "PersonalVoice"
+)
 
 type Project struct {
-	ID          string `json:"id"`
-	Kind        string `json:"kind"`
-	Description string `json:"description,omitempty"`
+	ID              string `json:"id"`
+	Kind            string `json:"kind"`
+	DisplayName     string `json:"displayName,omitempty"`
+	Description     string `json:"description,omitempty"`
+	CreatedDateTime string `json:"createdDateTime,omitempty"`
 }
 
-type ListProjectsResponse struct {
-	Value []Project `json:"value"`
-}
-
-func NewTextToSpeechCustomNeuralClient(endpoint, key, region, apiVersion string) *TextToSpeechCustomNeuralClient {
-	return &TextToSpeechCustomNeuralClient{
-		Endpoint:   endpoint,
-		Key:        key,
-		Region:     region,
-		APIVersion: apiVersion,
-	}
-}
-
-func (c *TextToSpeechCustomNeuralClient) CheckProjectExists(projectID string) (bool, error) {
+func (c *CustomVoiceClient) CheckProjectExists(projectID string) (bool, error) {
 	url := fmt.Sprintf("%s/customvoice/projects/%s?api-version=%s", c.Endpoint, projectID, c.APIVersion)
 
 	client := httpclient.NewClient()
-	headers := map[string]string{
-		"Ocp-Apim-Subscription-Key": c.Key,
-		"Content-Type":              "application/json",
-	}
 
-	resp, err := client.Get(url, headers, nil)
+	resp, err := client.Get(url, c.headers(), nil)
 	if err != nil {
 		return false, fmt.Errorf("failed to send request: %v", err)
 	}
@@ -61,42 +46,31 @@ func (c *TextToSpeechCustomNeuralClient) CheckProjectExists(projectID string) (b
 	}
 }
 
-func (c *TextToSpeechCustomNeuralClient) GetProjectID(projectName string) (string, error) {
-	url := fmt.Sprintf("%s/customvoice/projects?api-version=%s", c.Endpoint, c.APIVersion)
+func (c *CustomVoiceClient) GetProject(projectID string) (*Project, error) {
+	url := fmt.Sprintf("%s/customvoice/projects/%s?api-version=%s", c.Endpoint, projectID, c.APIVersion)
 
 	client := httpclient.NewClient()
-	headers := map[string]string{
-		"Ocp-Apim-Subscription-Key": c.Key,
-		"Content-Type":              "application/json",
-	}
-
-	resp, err := client.Get(url, headers, nil)
+	resp, err := client.Get(url, c.headers(), nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %v", err)
+		return nil, fmt.Errorf("failed to send request: %v", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("failed to get projects: %s", body)
+		return nil, fmt.Errorf("failed to get project: %s", body)
 	}
 
-	var response ListProjectsResponse
-	err = json.NewDecoder(resp.Body).Decode(&response)
+	var project Project
+	err = json.NewDecoder(resp.Body).Decode(&project)
 	if err != nil {
-		return "", fmt.Errorf("failed to decode response: %v", err)
-	}
-
-	for _, project := range response.Value {
-		if project.Kind == projectName {
-			return project.ID, nil
-		}
+		return nil, fmt.Errorf("failed to decode response: %v", err)
 	}
 
-	return "", fmt.Errorf("project with name %s not found", projectName)
+	return &project, nil
 }
 
-func (c *TextToSpeechCustomNeuralClient) CreateProject(projectID, kind, description string) (*Project, error) {
+func (c *CustomVoiceClient) CreateProject(projectID, kind, description string) (*Project, error) {
 	url := fmt.Sprintf("%s/customvoice/projects/%s?api-version=%s", c.Endpoint, projectID, c.APIVersion)
 
 	project := Project{
@@ -110,12 +84,8 @@ func (c *TextToSpeechCustomNeuralClient) CreateProject(projectID, kind, descript
 	}
 
 	client := httpclient.NewClient()
-	headers := map[string]string{
-		"Ocp-Apim-Subscription-Key": c.Key,
-		"Content-Type":              "application/json",
-	}
 
-	resp, err := client.Put(url, bytes.NewBuffer(requestBody), headers, nil)
+	resp, err := client.Put(url, bytes.NewBuffer(requestBody), c.headers(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %v", err)
 	}
@@ -135,16 +105,12 @@ func (c *TextToSpeechCustomNeuralClient) CreateProject(projectID, kind, descript
 	return &createdProject, nil
 }
 
-func (c *TextToSpeechCustomNeuralClient) DeleteProject(projectID string) error {
+func (c *CustomVoiceClient) DeleteProject(projectID string) error {
 	url := fmt.Sprintf("%s/customvoice/projects/%s?api-version=%s", c.Endpoint, projectID, c.APIVersion)
 
 	client := httpclient.NewClient()
-	headers := map[string]string{
-		"Ocp-Apim-Subscription-Key": c.Key,
-		"Content-Type":              "application/json",
-	}
 
-	resp, err := client.Delete(url, headers, nil)
+	resp, err := client.Delete(url, c.headers(), nil)
 	if err != nil {
 		return fmt.Errorf("failed to send request: %v", err)
 	}
@@ -158,31 +124,12 @@ func (c *TextToSpeechCustomNeuralClient) DeleteProject(projectID string) error {
 	return nil
 }
 
-func (c *TextToSpeechCustomNeuralClient) ListProjects() ([]Project, error) {
-	url := fmt.Sprintf("%s/customvoice/projects?api-version=%s", c.Endpoint, c.APIVersion)
-
-	client := httpclient.NewClient()
-	headers := map[string]string{
-		"Ocp-Apim-Subscription-Key": c.Key,
-		"Content-Type":              "application/json",
-	}
-
-	resp, err := client.Get(url, headers, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("failed to list projects: %s", body)
-	}
-
-	var response ListProjectsResponse
-	err = json.NewDecoder(resp.Body).Decode(&response)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode response: %v", err)
-	}
+// Projects iterates over the projects matching opts, following nextLink
+func (c *CustomVoiceClient) Projects(opts *ListOptions) iter.Seq2[Project, error] {
+	return customVoiceResources[Project](c, "projects", opts)
+}
 
-	return response.Value, nil
+// ListProjects returns every project
+func (c *CustomVoiceClient) ListProjects() ([]Project, error) {
+	return collectCustomVoiceResources(c.Projects(nil))
 }
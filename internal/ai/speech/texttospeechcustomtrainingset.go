@@ -0,0 +1,215 @@
+package speech
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"time"
+
+	"github.com/ngothientuong/tngo-ai-svcs/pkg/httpclient"
+)
+
+/*
+Reference: https://learn.microsoft.com/en-us/rest/api/aiservices/speechapi/training-sets?view=rest-aiservices-speechapi-2024-02-01-preview
+A training set groups the recordings of a professional voice project:
+✅ Create, get, list and delete training sets
+✅ Upload audio and scripts from Azure Blob Storage containers (SAS URLs); the service imports them asynchronously
+✅ Poll the training set until the import succeeds or fails
+Validate the recordings locally with ValidateTrainingSetDir before uploading them.
+*/
+
+// Training set data kinds
+const (
+	TrainingSetKindAudioAndScript = "AudioAndScript" // Utterance WAV files with matching script lines
+	TrainingSetKindLongAudio      = "LongAudio"      // Long recordings with scripts, segmented by the service
+	TrainingSetKindAudioOnly      = "AudioOnly"      // Recordings transcribed by the service
+)
+
+type TrainingSet struct {
+	ID                 string                 `json:"id"`
+	DisplayName        string                 `json:"displayName,omitempty"`
+	Description        string                 `json:"description,omitempty"`
+	ProjectID          string                 `json:"projectId"`
+	Locale             string                 `json:"locale"`
+	Status             CustomVoiceStatus      `json:"status,omitempty"`
+	CreatedDateTime    string                 `json:"createdDateTime,omitempty"`
+	LastActionDateTime string                 `json:"lastActionDateTime,omitempty"`
+	Properties         *TrainingSetProperties `json:"properties,omitempty"`
+}
+
+type TrainingSetProperties struct {
+	UtteranceCount int `json:"utteranceCount"`
+}
+
+// AzureBlobContentSource selects files in a blob container, the container URL must carry a SAS token with read and list permissions
+type AzureBlobContentSource struct {
+	ContainerURL string   `json:"containerUrl"`
+	Prefix       string   `json:"prefix,omitempty"`
+	Extensions   []string `json:"extensions"`
+}
+
+// TrainingSetUpload describes the data imported into a training set
+type TrainingSetUpload struct {
+	Kind    string                  `json:"kind"`
+	Audios  AzureBlobContentSource  `json:"audios"`
+	Scripts *AzureBlobContentSource `json:"scripts,omitempty"` // Not used for AudioOnly
+}
+
+func (c *CustomVoiceClient) CreateTrainingSet(trainingSetID, projectID, locale, description string) (*TrainingSet, error) {
+	url := fmt.Sprintf("%s/customvoice/trainingsets/%s?api-version=%s", c.Endpoint, trainingSetID, c.APIVersion)
+
+	trainingSet := TrainingSet{
+		ID:          trainingSetID,
+		ProjectID:   projectID,
+		Locale:      locale,
+		Description: description,
+	}
+	requestBody, err := json.Marshal(trainingSet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %v", err)
+	}
+
+	client := httpclient.NewClient()
+	resp, err := client.Put(url, bytes.NewBuffer(requestBody), c.headers(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to create training set: %s", body)
+	}
+
+	var createdTrainingSet TrainingSet
+	err = json.NewDecoder(resp.Body).Decode(&createdTrainingSet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &createdTrainingSet, nil
+}
+
+// LookupTrainingSet gets a training set, with found set to false when it does not exist
+func (c *CustomVoiceClient) LookupTrainingSet(trainingSetID string) (trainingSet *TrainingSet, found bool, err error) {
+	return lookupCustomVoiceResource[TrainingSet](c, "trainingsets", trainingSetID, "training set")
+}
+
+func (c *CustomVoiceClient) GetTrainingSet(trainingSetID string) (*TrainingSet, error) {
+	url := fmt.Sprintf("%s/customvoice/trainingsets/%s?api-version=%s", c.Endpoint, trainingSetID, c.APIVersion)
+
+	client := httpclient.NewClient()
+	resp, err := client.Get(url, c.headers(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get training set: %s", body)
+	}
+
+	var trainingSet TrainingSet
+	err = json.NewDecoder(resp.Body).Decode(&trainingSet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &trainingSet, nil
+}
+
+func (c *CustomVoiceClient) DeleteTrainingSet(trainingSetID string) error {
+	url := fmt.Sprintf("%s/customvoice/trainingsets/%s?api-version=%s", c.Endpoint, trainingSetID, c.APIVersion)
+
+	client := httpclient.NewClient()
+	resp, err := client.Delete(url, c.headers(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete training set: %s", body)
+	}
+
+	return nil
+}
+
+// TrainingSets iterates over the training sets matching opts, following nextLink
+func (c *CustomVoiceClient) TrainingSets(opts *ListOptions) iter.Seq2[TrainingSet, error] {
+	return customVoiceResources[TrainingSet](c, "trainingsets", opts)
+}
+
+// ListTrainingSets returns the training sets of a project, or of every project when projectID is empty, following nextLink
+func (c *CustomVoiceClient) ListTrainingSets(projectID string) ([]TrainingSet, error) {
+	return collectCustomVoiceResources(c.TrainingSets(projectListOptions(projectID)))
+}
+
+// UploadTrainingSetData starts importing audio and scripts into a training set and returns the operation ID
+func (c *CustomVoiceClient) UploadTrainingSetData(trainingSetID string, upload TrainingSetUpload) (string, error) {
+	url := fmt.Sprintf("%s/customvoice/trainingsets/%s:upload?api-version=%s", c.Endpoint, trainingSetID, c.APIVersion)
+
+	switch upload.Kind {
+	case TrainingSetKindAudioAndScript, TrainingSetKindLongAudio:
+		if upload.Scripts == nil {
+			return "", fmt.Errorf("%s uploads need a scripts source", upload.Kind)
+		}
+	case TrainingSetKindAudioOnly:
+	default:
+		return "", fmt.Errorf("unsupported training set kind: %s", upload.Kind)
+	}
+	if upload.Audios.ContainerURL == "" {
+		return "", fmt.Errorf("an audio container URL is required")
+	}
+	if len(upload.Audios.Extensions) == 0 {
+		upload.Audios.Extensions = []string{".wav"}
+	}
+	if upload.Scripts != nil && len(upload.Scripts.Extensions) == 0 {
+		scripts := *upload.Scripts
+		scripts.Extensions = []string{".txt"}
+		upload.Scripts = &scripts
+	}
+
+	requestBody, err := json.Marshal(upload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request body: %v", err)
+	}
+
+	client := httpclient.NewClient()
+	resp, err := client.Post(url, bytes.NewBuffer(requestBody), c.headers(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("failed to upload training set data: %s", body)
+	}
+
+	return resp.Header.Get("Operation-Id"), nil
+}
+
+// WaitForTrainingSet polls the training set until its data import succeeds or fails
+func (c *CustomVoiceClient) WaitForTrainingSet(ctx context.Context, trainingSetID string, interval time.Duration) (*TrainingSet, error) {
+	var trainingSet *TrainingSet
+	err := waitForCustomVoiceStatus(ctx, interval, "training set "+trainingSetID, func() (CustomVoiceStatus, error) {
+		var err error
+		trainingSet, err = c.GetTrainingSet(trainingSetID)
+		if err != nil {
+			return "", err
+		}
+		return trainingSet.Status, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return trainingSet, nil
+}
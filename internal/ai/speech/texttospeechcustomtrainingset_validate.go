@@ -0,0 +1,277 @@
+package speech
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ngothientuong/tngo-ai-svcs/internal/audio/audioutil"
+)
+
+/*
+Reference: https://learn.microsoft.com/en-us/azure/ai-services/speech-service/how-to-custom-voice-training-data
+Pre-flight checks for an AudioAndScript training set laid out as in the portal:
+✅ <id>.wav utterances: RIFF PCM, 16-bit, mono, at least 24 kHz, shorter than 15 seconds
+✅ .txt scripts: UTF-8, one "<id><TAB><transcript>" line per utterance
+✅ Every script line has a recording and every recording has a script line
+✅ Recordings contain speech, with duration statistics for the whole set
+*/
+
+// TrainingSetValidationOptions sets the limits checked; zero values use the service requirements
+type TrainingSetValidationOptions struct {
+	MinSampleRate        int           // 24000 by default
+	MaxUtteranceDuration time.Duration // 15 seconds by default
+	MinUtterances        int           // Fewer utterances only warn, 300 by default
+	MaxSilence           time.Duration // Leading or trailing silence that warns, 1 second by default
+}
+
+func (o *TrainingSetValidationOptions) withDefaults() TrainingSetValidationOptions {
+	options := TrainingSetValidationOptions{}
+	if o != nil {
+		options = *o
+	}
+	if options.MinSampleRate <= 0 {
+		options.MinSampleRate = 24000
+	}
+	if options.MaxUtteranceDuration <= 0 {
+		options.MaxUtteranceDuration = 15 * time.Second
+	}
+	if options.MinUtterances <= 0 {
+		options.MinUtterances = 300
+	}
+	if options.MaxSilence <= 0 {
+		options.MaxSilence = time.Second
+	}
+	return options
+}
+
+// Training set issue severities; errors make the service reject the data, warnings lower voice quality
+const (
+	TrainingSetIssueError   = "error"
+	TrainingSetIssueWarning = "warning"
+)
+
+// TrainingSetIssue is a problem found in one file, Line is set for script issues
+type TrainingSetIssue struct {
+	Severity string `json:"severity"`
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (i TrainingSetIssue) String() string {
+	location := i.File
+	if i.Line > 0 {
+		location = fmt.Sprintf("%s:%d", i.File, i.Line)
+	}
+	return fmt.Sprintf("%s: %s: %s", i.Severity, location, i.Message)
+}
+
+// TrainingSetReport is the result of validating a training set directory
+type TrainingSetReport struct {
+	Utterances     int                `json:"utterances"` // Paired recordings and script lines
+	TotalDuration  time.Duration      `json:"totalDuration"`
+	MinDuration    time.Duration      `json:"minDuration"`
+	MaxDuration    time.Duration      `json:"maxDuration"`
+	MeanDuration   time.Duration      `json:"meanDuration"`
+	SpeechDuration time.Duration      `json:"speechDuration"`
+	SampleRates    map[int]int        `json:"sampleRates"` // Recordings per sample rate
+	Issues         []TrainingSetIssue `json:"issues,omitempty"`
+}
+
+// Valid reports whether the set has no errors; warnings are allowed
+func (r *TrainingSetReport) Valid() bool {
+	return len(r.Errors()) == 0
+}
+
+// Errors returns the issues that make the service reject the data
+func (r *TrainingSetReport) Errors() []TrainingSetIssue {
+	var errors []TrainingSetIssue
+	for _, issue := range r.Issues {
+		if issue.Severity == TrainingSetIssueError {
+			errors = append(errors, issue)
+		}
+	}
+	return errors
+}
+
+func (r *TrainingSetReport) addIssue(severity, file string, line int, format string, args ...any) {
+	r.Issues = append(r.Issues, TrainingSetIssue{Severity: severity, File: file, Line: line, Message: fmt.Sprintf(format, args...)})
+}
+
+// scriptLine is one utterance of a script file
+type scriptLine struct {
+	file string
+	line int
+	text string
+}
+
+// ValidateTrainingSetDir checks the recordings and scripts in dir before they are uploaded
+func ValidateTrainingSetDir(dir string, opts *TrainingSetValidationOptions) (*TrainingSetReport, error) {
+	options := opts.withDefaults()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read training set directory: %v", err)
+	}
+
+	report := &TrainingSetReport{SampleRates: make(map[int]int)}
+	recordings := make(map[string]string)
+	scripts := make(map[string]scriptLine)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		switch strings.ToLower(filepath.Ext(name)) {
+		case ".wav":
+			recordings[strings.TrimSuffix(name, filepath.Ext(name))] = name
+		case ".txt":
+			if err := readTrainingScript(filepath.Join(dir, name), name, scripts, report); err != nil {
+				return nil, err
+			}
+		default:
+			report.addIssue(TrainingSetIssueWarning, name, 0, "ignored, only .wav recordings and .txt scripts are uploaded")
+		}
+	}
+
+	// Walk utterances in ID order so the report is stable
+	ids := make([]string, 0, len(recordings))
+	for id := range recordings {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		name := recordings[id]
+		if _, ok := scripts[id]; !ok {
+			report.addIssue(TrainingSetIssueError, name, 0, "no script line with ID %s", id)
+			continue
+		}
+		duration, ok := validateRecording(filepath.Join(dir, name), name, options, report)
+		if !ok {
+			continue
+		}
+
+		report.Utterances++
+		report.TotalDuration += duration
+		if report.MinDuration == 0 || duration < report.MinDuration {
+			report.MinDuration = duration
+		}
+		report.MaxDuration = max(report.MaxDuration, duration)
+	}
+
+	scriptIDs := make([]string, 0, len(scripts))
+	for id := range scripts {
+		if _, ok := recordings[id]; !ok {
+			scriptIDs = append(scriptIDs, id)
+		}
+	}
+	sort.Strings(scriptIDs)
+	for _, id := range scriptIDs {
+		line := scripts[id]
+		report.addIssue(TrainingSetIssueError, line.file, line.line, "no recording %s.wav for this line", id)
+	}
+
+	if report.Utterances > 0 {
+		report.MeanDuration = report.TotalDuration / time.Duration(report.Utterances)
+	}
+	if report.Utterances < options.MinUtterances {
+		report.addIssue(TrainingSetIssueWarning, dir, 0, "%d utterances, at least %d are recommended for a natural voice", report.Utterances, options.MinUtterances)
+	}
+	return report, nil
+}
+
+// readTrainingScript adds the utterances of one script file, reporting malformed and duplicate lines
+func readTrainingScript(path, name string, scripts map[string]scriptLine, report *TrainingSetReport) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read script %s: %v", name, err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(data) {
+		report.addIssue(TrainingSetIssueError, name, 0, "script is not UTF-8 encoded")
+		return nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		id, transcript, ok := strings.Cut(text, "\t")
+		switch {
+		case !ok:
+			report.addIssue(TrainingSetIssueError, name, line, "expected \"<id><TAB><transcript>\"")
+		case strings.TrimSpace(transcript) == "":
+			report.addIssue(TrainingSetIssueError, name, line, "empty transcript for %s", id)
+		default:
+			if previous, exists := scripts[id]; exists {
+				report.addIssue(TrainingSetIssueError, name, line, "duplicate ID %s, first used at %s:%d", id, previous.file, previous.line)
+				continue
+			}
+			scripts[id] = scriptLine{file: name, line: line, text: transcript}
+		}
+	}
+	return scanner.Err()
+}
+
+// validateRecording checks one utterance and returns its duration; ok is false when it cannot be used
+func validateRecording(path, name string, options TrainingSetValidationOptions, report *TrainingSetReport) (time.Duration, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		report.addIssue(TrainingSetIssueError, name, 0, "failed to read recording: %v", err)
+		return 0, false
+	}
+	format, pcm, err := audioutil.DecodeWAV(data)
+	if err != nil {
+		report.addIssue(TrainingSetIssueError, name, 0, "%v", err)
+		return 0, false
+	}
+
+	report.SampleRates[format.SampleRate]++
+	ok := true
+	if format.SampleRate < options.MinSampleRate {
+		report.addIssue(TrainingSetIssueError, name, 0, "sample rate %d Hz is below %d Hz", format.SampleRate, options.MinSampleRate)
+		ok = false
+	}
+	if format.BitsPerSample != 16 {
+		report.addIssue(TrainingSetIssueError, name, 0, "%d-bit samples, 16-bit is required", format.BitsPerSample)
+		ok = false
+	}
+	if format.Channels != 1 {
+		report.addIssue(TrainingSetIssueError, name, 0, "%d channels, mono is required", format.Channels)
+		ok = false
+	}
+
+	duration := format.Duration(int64(len(pcm)))
+	if duration > options.MaxUtteranceDuration {
+		report.addIssue(TrainingSetIssueError, name, 0, "%s long, utterances must be shorter than %s", duration.Round(time.Millisecond), options.MaxUtteranceDuration)
+		ok = false
+	}
+	if !ok {
+		return duration, false
+	}
+
+	segments, stats, err := audioutil.DetectSpeech(pcm, format, &audioutil.VADOptions{Padding: audioutil.NoPadding})
+	if err != nil {
+		report.addIssue(TrainingSetIssueError, name, 0, "failed to analyze recording: %v", err)
+		return duration, false
+	}
+	if len(segments) == 0 {
+		report.addIssue(TrainingSetIssueError, name, 0, "no speech detected")
+		return duration, false
+	}
+	report.SpeechDuration += stats.SpeechDuration
+	if lead, trail := segments[0].Start, duration-segments[len(segments)-1].End; lead > options.MaxSilence || trail > options.MaxSilence {
+		report.addIssue(TrainingSetIssueWarning, name, 0, "%s of leading and %s of trailing silence, keep both under %s", lead.Round(time.Millisecond), trail.Round(time.Millisecond), options.MaxSilence)
+	}
+	return duration, true
+}
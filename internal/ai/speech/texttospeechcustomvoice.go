@@ -0,0 +1,231 @@
+package speech
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ngothientuong/tngo-ai-svcs/pkg/httpclient"
+	"github.com/ngothientuong/tngo-ai-svcs/pkg/poller"
+)
+
+/*
+Reference: https://learn.microsoft.com/en-us/rest/api/aiservices/speechapi/operation-groups?view=rest-aiservices-speechapi-2024-02-01-preview
+CustomVoiceClient covers every custom voice resource with the resource key:
+✅ Projects, consents, training sets, models, endpoints, personal voices, base models and operations
+✅ Typed resource statuses shared by every resource and operation
+✅ Iterators over list responses that follow nextLink, with OData filter, top and skip
+*/
+
+type CustomVoiceClient struct {
+	Endpoint   string
+	Key        string
+	Region     string
+	APIVersion string
+}
+
+func NewCustomVoiceClient(endpoint, key, region, apiVersion string) *CustomVoiceClient {
+	return &CustomVoiceClient{
+		Endpoint:   endpoint,
+		Key:        key,
+		Region:     region,
+		APIVersion: apiVersion,
+	}
+}
+
+func (c *CustomVoiceClient) headers() map[string]string {
+	return map[string]string{
+		"Ocp-Apim-Subscription-Key": c.Key,
+		"Content-Type":              "application/json",
+	}
+}
+
+// CustomVoiceStatus is the status of a custom voice resource or operation
+type CustomVoiceStatus string
+
+// Custom voice resource and operation statuses
+const (
+	CustomVoiceStatusNotStarted CustomVoiceStatus = "NotStarted"
+	CustomVoiceStatusRunning    CustomVoiceStatus = "Running"
+	CustomVoiceStatusSucceeded  CustomVoiceStatus = "Succeeded"
+	CustomVoiceStatusFailed     CustomVoiceStatus = "Failed"
+	CustomVoiceStatusDisabling  CustomVoiceStatus = "Disabling" // Endpoint being suspended
+	CustomVoiceStatusDisabled   CustomVoiceStatus = "Disabled"  // Suspended endpoint
+)
+
+// Done reports whether the status will not change without another request
+func (s CustomVoiceStatus) Done() bool {
+	switch s {
+	case CustomVoiceStatusSucceeded, CustomVoiceStatusFailed, CustomVoiceStatusDisabled:
+		return true
+	}
+	return false
+}
+
+// DefaultCustomVoicePollInterval is a polling interval suited to training and deployment, which take minutes to hours
+const DefaultCustomVoicePollInterval = 30 * time.Second
+
+// ListOptions narrows a list request; a nil *ListOptions lists everything
+type ListOptions struct {
+	Filter      string // OData filter such as ProjectFilter(projectID)
+	Top         int    // Maximum number of resources, 0 for all
+	Skip        int    // Resources to skip before the first one returned
+	MaxPageSize int    // Resources per page, 0 for the service default
+}
+
+// ProjectFilter returns the OData filter selecting the resources of a project
+func ProjectFilter(projectID string) string {
+	return fmt.Sprintf("projectId eq '%s'", projectID)
+}
+
+// projectListOptions filters by project when projectID is set
+func projectListOptions(projectID string) *ListOptions {
+	if projectID == "" {
+		return nil
+	}
+	return &ListOptions{Filter: ProjectFilter(projectID)}
+}
+
+func (o *ListOptions) query() string {
+	if o == nil {
+		return ""
+	}
+	params := url.Values{}
+	if o.Filter != "" {
+		params.Set("filter", o.Filter)
+	}
+	if o.Top > 0 {
+		params.Set("top", strconv.Itoa(o.Top))
+	}
+	if o.Skip > 0 {
+		params.Set("skip", strconv.Itoa(o.Skip))
+	}
+	if o.MaxPageSize > 0 {
+		params.Set("maxpagesize", strconv.Itoa(o.MaxPageSize))
+	}
+	if len(params) == 0 {
+		return ""
+	}
+	return "&" + params.Encode()
+}
+
+// customVoiceList is one page of a custom voice list response
+type customVoiceList[T any] struct {
+	Value    []T    `json:"value"`
+	NextLink string `json:"nextLink"`
+}
+
+// customVoiceResources iterates over a collection such as "models", requesting the next page only when the previous one is consumed.
+// The iteration stops after the first error.
+func customVoiceResources[T any](c *CustomVoiceClient, collection string, opts *ListOptions) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		listURL := fmt.Sprintf("%s/customvoice/%s?api-version=%s%s", c.Endpoint, collection, c.APIVersion, opts.query())
+
+		client := httpclient.NewClient()
+		count := 0
+		for listURL != "" {
+			page, err := getCustomVoicePage[T](client, listURL, c.headers(), collection)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			for _, resource := range page.Value {
+				if !yield(resource, nil) {
+					return
+				}
+				count++
+				if opts != nil && opts.Top > 0 && count >= opts.Top {
+					return
+				}
+			}
+			listURL = page.NextLink
+		}
+	}
+}
+
+func getCustomVoicePage[T any](client *httpclient.Client, listURL string, headers map[string]string, collection string) (*customVoiceList[T], error) {
+	resp, err := client.Get(listURL, headers, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to list %s: %s", collection, body)
+	}
+
+	var page customVoiceList[T]
+	err = json.NewDecoder(resp.Body).Decode(&page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &page, nil
+}
+
+// lookupCustomVoiceResource gets one resource of a collection such as "models"; found is false when it does not exist
+func lookupCustomVoiceResource[T any](c *CustomVoiceClient, collection, id, resource string) (*T, bool, error) {
+	url := fmt.Sprintf("%s/customvoice/%s/%s?api-version=%s", c.Endpoint, collection, id, c.APIVersion)
+
+	client := httpclient.NewClient()
+	resp, err := client.Get(url, c.headers(), nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, false, fmt.Errorf("failed to get %s: %s", resource, body)
+	}
+
+	var value T
+	err = json.NewDecoder(resp.Body).Decode(&value)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &value, true, nil
+}
+
+// collectCustomVoiceResources drains an iterator into a slice
+func collectCustomVoiceResources[T any](resources iter.Seq2[T, error]) ([]T, error) {
+	var all []T
+	for resource, err := range resources {
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, resource)
+	}
+	return all, nil
+}
+
+// waitForCustomVoiceStatus polls get until the resource is done; Failed ends the wait with an error, while any
+// other final status, such as a suspended endpoint's Disabled, ends it successfully
+func waitForCustomVoiceStatus(ctx context.Context, interval time.Duration, resource string, get func() (CustomVoiceStatus, error)) error {
+	if interval <= 0 {
+		interval = DefaultCustomVoicePollInterval
+	}
+	return poller.Poll(ctx, interval, func() (bool, error) {
+		status, err := get()
+		if err != nil {
+			return false, err
+		}
+		if status == CustomVoiceStatusFailed {
+			return false, fmt.Errorf("%s %s", resource, status)
+		}
+		return status.Done(), nil
+	})
+}
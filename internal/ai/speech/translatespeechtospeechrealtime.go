@@ -1,68 +1,767 @@
 package speech
 
 import (
+	"context"
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/Microsoft/cognitive-services-speech-sdk-go/audio"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
 	speechsdk "github.com/Microsoft/cognitive-services-speech-sdk-go/speech"
-	"github.com/gorilla/websocket"
+	"github.com/google/uuid"
+	localaudio "github.com/ngothientuong/tngo-ai-svcs/internal/audio"
 )
 
-var (
-	clients               = make(map[*websocket.Conn]bool)
-	mu                    sync.Mutex
-	translationRecognizer *speechsdk.TranslationRecognizer
+// TranslationSessionState is the lifecycle state of a translation session
+type TranslationSessionState string
+
+const (
+	TranslationSessionRunning TranslationSessionState = "running"
+	TranslationSessionStopped TranslationSessionState = "stopped"
+	TranslationSessionFailed  TranslationSessionState = "failed"
+)
+
+// TranslationMode selects what a session delivers for each translated utterance
+type TranslationMode string
+
+const (
+	TranslationModeTextOnly   TranslationMode = "text-only"
+	TranslationModeSpeechOnly TranslationMode = "speech-only"
+	TranslationModeBoth       TranslationMode = "both"
 )
 
-// TranslateSpeechFromMicrophone starts speech translation
-func TranslateSpeechFromMicrophone(speechKey, speechRegion, translationKey, translationEndpoint, targetLanguage, mode string) error {
+// translationAudioFormat is the format of synthesized translation audio, one WAV file per utterance
+const translationAudioFormat = "riff-24khz-16bit-mono-pcm"
+
+// TranslationSessionConfig describes a speech translation session
+type TranslationSessionConfig struct {
+	SpeechKey       string
+	SpeechRegion    string
+	SourceLanguage  string            // Defaults to en-US
+	EndpointID      string            // Custom Speech endpoint recognizing SourceLanguage, the base model when empty
+	TargetLanguages []string          // Translator language codes such as "vi" or "fr"
+	Voices          map[string]string // Target language to voice name; languages without a voice get no audio, voices of other languages are ignored
+	Mode            TranslationMode   // Defaults to both
+	DeviceName      string            // Capture device, the default microphone when empty
+
+	// InputDevice captures through PortAudio instead of the SDK microphone, selected by index or name
+	// ("default" for the default input), and pushes 16 kHz mono PCM into the session
+	InputDevice string
+
+	// OutputDevice plays synthesized translations on a PortAudio device, selected like InputDevice.
+	// Audio events are still published. PortAudio must be initialized for either device.
+	OutputDevice string
+
+	// StreamFormat switches the session from a microphone to audio pushed with WriteAudio,
+	// such as frames from a browser: raw 16 kHz 16-bit mono PCM, Ogg/Opus, or any GStreamer-decodable format
+	StreamFormat AudioInputFormat
+}
+
+// TranslationEventType identifies a translation session event
+type TranslationEventType string
+
+const (
+	TranslationRecognizing TranslationEventType = "recognizing"
+	TranslationRecognized  TranslationEventType = "recognized"
+	TranslationAudio       TranslationEventType = "audio"
+	TranslationCanceled    TranslationEventType = "canceled"
+	TranslationStopped     TranslationEventType = "stopped"
+)
+
+// TranslationEvent is delivered to the subscribers of a session.
+// Audio events carry one synthesized utterance for Language, encoded as AudioFormat.
+type TranslationEvent struct {
+	SessionID    string               `json:"sessionId"`
+	Type         TranslationEventType `json:"type"`
+	SourceText   string               `json:"sourceText,omitempty"`
+	Translations map[string]string    `json:"translations,omitempty"`
+	Language     string               `json:"language,omitempty"`
+	Audio        []byte               `json:"audio,omitempty"`
+	AudioFormat  string               `json:"audioFormat,omitempty"`
+	Error        string               `json:"error,omitempty"`
+}
+
+// TranslationSessionStatus is a snapshot of a session
+type TranslationSessionStatus struct {
+	ID              string                  `json:"id"`
+	State           TranslationSessionState `json:"state"`
+	Mode            TranslationMode         `json:"mode"`
+	SourceLanguage  string                  `json:"sourceLanguage"`
+	TargetLanguages []string                `json:"targetLanguages"`
+	StartedAt       time.Time               `json:"startedAt"`
+	StoppedAt       time.Time               `json:"stoppedAt,omitempty"`
+	Subscribers     int                     `json:"subscribers"`
+	Error           string                  `json:"error,omitempty"`
+}
+
+/*
+TranslationSession owns one translation recognizer with its configs, one synthesizer per voiced
+target language, and its subscribers. Translated utterances are synthesized in order by a single
+worker so SDK callbacks never wait for synthesis: when the queue is full the utterance is dropped and
+reported with an audio error event. Subscribers that fall behind miss events rather
+than blocking the SDK callbacks. All SDK handles are released exactly once, when the session is
+stopped or ends by itself.
+*/
+type TranslationSession struct {
+	id     string
+	config TranslationSessionConfig
+
+	speechConfig *speechsdk.SpeechTranslationConfig
+	audioConfig  *audio.AudioConfig
+	recognizer   *speechsdk.TranslationRecognizer
+	synthesizers map[string]*translationSynthesizer
+
+	// streamMu guards audioStream against writes after the session released it
+	streamMu     sync.Mutex
+	audioStream  *audio.PushAudioInputStream
+	streamClosed bool
+
+	// capture feeds audioStream from a PortAudio input device, player plays synthesized audio
+	capture     *localaudio.Capture
+	captureDone chan struct{}
+	player      *localaudio.Player
+	ctx         context.Context
+	cancel      context.CancelFunc
+
+	synthesis     chan synthesisJob
+	synthesisDone chan struct{}
+	closing       chan struct{}
+
+	mu          sync.Mutex
+	mode        TranslationMode
+	state       TranslationSessionState
+	err         error
+	startedAt   time.Time
+	stoppedAt   time.Time
+	subscribers map[int]chan TranslationEvent
+	nextID      int
+
+	// onFinish lets the manager forget the session once it has ended, however it ended
+	onFinish func()
+
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// TranslationSessionManager creates isolated translation sessions and tracks them by ID
+type TranslationSessionManager struct {
+	mu       sync.Mutex
+	sessions map[string]*TranslationSession
+}
+
+func NewTranslationSessionManager() *TranslationSessionManager {
+	return &TranslationSessionManager{sessions: make(map[string]*TranslationSession)}
+}
+
+// Start creates a session and begins continuous translation
+func (m *TranslationSessionManager) Start(config TranslationSessionConfig) (*TranslationSession, error) {
+	session, err := newTranslationSession(config)
+	if err != nil {
+		return nil, err
+	}
+
+	session.onFinish = func() { m.remove(session.id) }
+	m.mu.Lock()
+	m.sessions[session.id] = session
+	m.mu.Unlock()
+
+	if err := session.start(); err != nil {
+		m.remove(session.id)
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// Get returns a session by ID
+func (m *TranslationSessionManager) Get(id string) (*TranslationSession, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	session, ok := m.sessions[id]
+	return session, ok
+}
+
+// Stop stops a session and forgets it
+func (m *TranslationSessionManager) Stop(id string) error {
+	session, ok := m.Get(id)
+	if !ok {
+		return fmt.Errorf("translation session %s not found", id)
+	}
+	m.remove(id)
+	return session.Stop()
+}
+
+// Status returns a snapshot of a session
+func (m *TranslationSessionManager) Status(id string) (TranslationSessionStatus, error) {
+	session, ok := m.Get(id)
+	if !ok {
+		return TranslationSessionStatus{}, fmt.Errorf("translation session %s not found", id)
+	}
+	return session.Status(), nil
+}
+
+// List returns a snapshot of every tracked session, oldest first
+func (m *TranslationSessionManager) List() []TranslationSessionStatus {
+	m.mu.Lock()
+	sessions := make([]*TranslationSession, 0, len(m.sessions))
+	for _, session := range m.sessions {
+		sessions = append(sessions, session)
+	}
+	m.mu.Unlock()
+
+	statuses := make([]TranslationSessionStatus, 0, len(sessions))
+	for _, session := range sessions {
+		statuses = append(statuses, session.Status())
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].StartedAt.Before(statuses[j].StartedAt) })
+	return statuses
+}
+
+// StopAll stops every session, for use on shutdown
+func (m *TranslationSessionManager) StopAll() {
+	m.mu.Lock()
+	sessions := m.sessions
+	m.sessions = make(map[string]*TranslationSession)
+	m.mu.Unlock()
+
+	for _, session := range sessions {
+		session.Stop()
+	}
+}
+
+func (m *TranslationSessionManager) remove(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, id)
+}
+
+// translationSynthesizer speaks the translations of one target language
+type translationSynthesizer struct {
+	config      *speechsdk.SpeechConfig
+	synthesizer *speechsdk.SpeechSynthesizer
+}
+
+// synthesisJob is a translated utterance waiting for synthesis
+type synthesisJob struct {
+	language string
+	text     string
+}
+
+func newTranslationSession(config TranslationSessionConfig) (*TranslationSession, error) {
+	if len(config.TargetLanguages) == 0 {
+		return nil, fmt.Errorf("at least one target language is required")
+	}
+	if config.SourceLanguage == "" {
+		config.SourceLanguage = "en-US"
+	}
+	if config.Mode == "" {
+		config.Mode = TranslationModeBoth
+	}
+	if !validTranslationMode(config.Mode) {
+		return nil, fmt.Errorf("unsupported translation mode: %s", config.Mode)
+	}
+
+	if config.InputDevice != "" && config.StreamFormat != "" {
+		return nil, fmt.Errorf("an input device and a stream format cannot be combined")
+	}
+
+	s := &TranslationSession{
+		id:            uuid.NewString(),
+		config:        config,
+		mode:          config.Mode,
+		synthesizers:  make(map[string]*translationSynthesizer),
+		synthesis:     make(chan synthesisJob, eventBufferSize),
+		synthesisDone: make(chan struct{}),
+		closing:       make(chan struct{}),
+		subscribers:   make(map[int]chan TranslationEvent),
+		done:          make(chan struct{}),
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+
+	if err := s.init(); err != nil {
+		s.release()
+		return nil, err
+	}
+
+	s.registerHandlers()
+	return s, nil
+}
+
+func (s *TranslationSession) init() error {
+	var err error
+
 	// Create speech translation config
-	speechConfig, err := speechsdk.NewSpeechTranslationConfigFromSubscription(speechKey, speechRegion)
+	s.speechConfig, err = speechsdk.NewSpeechTranslationConfigFromSubscription(s.config.SpeechKey, s.config.SpeechRegion)
 	if err != nil {
 		return fmt.Errorf("failed to create speech translation config: %v", err)
 	}
-	defer speechConfig.Close()
+	if err := s.speechConfig.SetSpeechRecognitionLanguage(s.config.SourceLanguage); err != nil {
+		return fmt.Errorf("failed to set recognition language: %v", err)
+	}
+	if s.config.EndpointID != "" {
+		if err := s.speechConfig.SetEndpointID(s.config.EndpointID); err != nil {
+			return fmt.Errorf("failed to set custom endpoint: %v", err)
+		}
+	}
+	for _, language := range s.config.TargetLanguages {
+		if err := s.speechConfig.AddTargetLanguage(language); err != nil {
+			return fmt.Errorf("failed to add target language %s: %v", language, err)
+		}
+	}
+
+	// Create a synthesizer per voiced target language, without audio output so the audio is returned
+	for _, language := range s.config.TargetLanguages {
+		voice := s.config.Voices[language]
+		if voice == "" || s.synthesizers[language] != nil {
+			continue
+		}
+		synthesizer, err := newTranslationSynthesizer(s.config.SpeechKey, s.config.SpeechRegion, voice)
+		if err != nil {
+			return fmt.Errorf("failed to create synthesizer for %s: %v", language, err)
+		}
+		s.synthesizers[language] = synthesizer
+	}
+
+	if s.config.OutputDevice != "" {
+		device, err := localaudio.FindOutputDevice(deviceSelector(s.config.OutputDevice))
+		if err != nil {
+			return fmt.Errorf("failed to select output device: %v", err)
+		}
+		if s.player, err = localaudio.NewPlayer(device); err != nil {
+			return err
+		}
+	}
+
+	// Create microphone or push stream audio config
+	if s.config.InputDevice != "" {
+		device, err := localaudio.FindInputDevice(deviceSelector(s.config.InputDevice))
+		if err != nil {
+			return fmt.Errorf("failed to select input device: %v", err)
+		}
+		if s.capture, err = localaudio.NewCapture(device, nil); err != nil {
+			return err
+		}
+		s.captureDone = make(chan struct{})
+		s.config.StreamFormat = AudioInputFormatPCM
+		if err := s.initAudioStream(); err != nil {
+			return err
+		}
+	} else if s.config.StreamFormat != "" {
+		if err := s.initAudioStream(); err != nil {
+			return err
+		}
+	} else {
+		if s.config.DeviceName == "" {
+			s.audioConfig, err = audio.NewAudioConfigFromDefaultMicrophoneInput()
+		} else {
+			s.audioConfig, err = audio.NewAudioConfigFromMicrophoneInput(s.config.DeviceName)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create audio config: %v", err)
+		}
+	}
+
+	// Create translation recognizer
+	s.recognizer, err = speechsdk.NewTranslationRecognizerFromConfig(s.speechConfig, s.audioConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create translation recognizer: %v", err)
+	}
+
+	return nil
+}
+
+func (s *TranslationSession) initAudioStream() error {
+	if s.config.StreamFormat == AudioInputFormatWAV {
+		return fmt.Errorf("WAV is not supported for pushed audio, send raw PCM frames instead")
+	}
+	streamFormat, _, err := newAudioStreamFormat(nil, s.config.StreamFormat)
+	if err != nil {
+		return err
+	}
+	defer streamFormat.Close()
 
-	err = speechConfig.AddTargetLanguage(targetLanguage)
+	s.audioStream, err = audio.CreatePushAudioInputStreamFromFormat(streamFormat)
 	if err != nil {
-		return fmt.Errorf("failed to add target language: %v", err)
+		return fmt.Errorf("failed to create push audio input stream: %v", err)
 	}
 
-	// Create microphone audio config
-	audioConfig, err := audio.NewAudioConfigFromDefaultMicrophoneInput()
+	s.audioConfig, err = audio.NewAudioConfigFromStreamInput(s.audioStream)
 	if err != nil {
 		return fmt.Errorf("failed to create audio config: %v", err)
 	}
-	defer audioConfig.Close()
 
-	// Create translation recognizer
-	translationRecognizer, err = speechsdk.NewTranslationRecognizerFromConfig(speechConfig, audioConfig)
+	return nil
+}
+
+// deviceSelector maps the "default" device name to the empty selector of the default device
+func deviceSelector(name string) string {
+	if name == "default" {
+		return ""
+	}
+	return name
+}
+
+func newTranslationSynthesizer(speechKey, speechRegion, voice string) (*translationSynthesizer, error) {
+	config, err := speechsdk.NewSpeechConfigFromSubscription(speechKey, speechRegion)
 	if err != nil {
-		return fmt.Errorf("failed to create translation recognizer: %v", err)
+		return nil, fmt.Errorf("failed to create speech config: %v", err)
+	}
+	if err := config.SetSpeechSynthesisVoiceName(voice); err != nil {
+		config.Close()
+		return nil, fmt.Errorf("failed to set voice: %v", err)
+	}
+	if err := config.SetSpeechSynthesisOutputFormat(common.Riff24Khz16BitMonoPcm); err != nil {
+		config.Close()
+		return nil, fmt.Errorf("failed to set output format: %v", err)
+	}
+
+	synthesizer, err := speechsdk.NewSpeechSynthesizerFromConfig(config, nil)
+	if err != nil {
+		config.Close()
+		return nil, fmt.Errorf("failed to create speech synthesizer: %v", err)
+	}
+
+	return &translationSynthesizer{config: config, synthesizer: synthesizer}, nil
+}
+
+func (t *translationSynthesizer) close() {
+	t.synthesizer.Close()
+	t.config.Close()
+}
+
+func validTranslationMode(mode TranslationMode) bool {
+	return mode == TranslationModeTextOnly || mode == TranslationModeSpeechOnly || mode == TranslationModeBoth
+}
+
+func (s *TranslationSession) registerHandlers() {
+	s.recognizer.Recognizing(func(event speechsdk.TranslationRecognitionEventArgs) {
+		defer event.Close()
+		if s.Mode() == TranslationModeSpeechOnly {
+			return
+		}
+		s.publish(TranslationEvent{
+			Type:         TranslationRecognizing,
+			SourceText:   event.Result.Text,
+			Translations: event.Result.GetTranslations(),
+		})
+	})
+
+	s.recognizer.Recognized(func(event speechsdk.TranslationRecognitionEventArgs) {
+		defer event.Close()
+		if event.Result.Reason != common.TranslatedSpeech {
+			return
+		}
+
+		mode := s.Mode()
+		translations := event.Result.GetTranslations()
+		if mode != TranslationModeSpeechOnly {
+			s.publish(TranslationEvent{
+				Type:         TranslationRecognized,
+				SourceText:   event.Result.Text,
+				Translations: translations,
+			})
+		}
+		if mode == TranslationModeTextOnly {
+			return
+		}
+		for _, language := range s.config.TargetLanguages {
+			if _, voiced := s.synthesizers[language]; !voiced || translations[language] == "" {
+				continue
+			}
+			select {
+			case s.synthesis <- synthesisJob{language: language, text: translations[language]}:
+			case <-s.closing:
+				return
+			default:
+				s.publish(TranslationEvent{Type: TranslationAudio, Language: language, Error: "synthesis queue is full, translation dropped"})
+			}
+		}
+	})
+
+	s.recognizer.Canceled(func(event speechsdk.TranslationRecognitionCanceledEventArgs) {
+		defer event.Close()
+		var err error
+		if event.Reason == common.Error {
+			err = fmt.Errorf("translation canceled: %s", event.ErrorDetails)
+			s.publish(TranslationEvent{Type: TranslationCanceled, Error: event.ErrorDetails})
+		}
+		go s.finish(err)
+	})
+
+	s.recognizer.SessionStopped(func(event speechsdk.SessionEventArgs) {
+		defer event.Close()
+		go s.finish(nil)
+	})
+}
+
+func (s *TranslationSession) start() error {
+	s.mu.Lock()
+	s.state = TranslationSessionRunning
+	s.startedAt = time.Now()
+	s.mu.Unlock()
+
+	go s.synthesize()
+	if s.capture != nil {
+		go s.runCapture()
 	}
 
 	// Start continuous recognition
-	errChan := translationRecognizer.StartContinuousRecognitionAsync()
+	errChan := s.recognizer.StartContinuousRecognitionAsync()
 	if err := <-errChan; err != nil {
-		return fmt.Errorf("failed to start continuous recognition: %v", err)
+		err = fmt.Errorf("failed to start continuous recognition: %v", err)
+		s.finish(err)
+		return err
 	}
 
 	return nil
 }
 
-// StopSpeechRecognition stops translation
-func StopSpeechRecognition() error {
-	if translationRecognizer == nil {
-		return fmt.Errorf("Recognizer not running")
+// runCapture pushes PortAudio input into the session until it ends
+func (s *TranslationSession) runCapture() {
+	defer close(s.captureDone)
+	if err := s.capture.Run(s.ctx, s.pushAudio); err != nil && s.ctx.Err() == nil {
+		go s.finish(fmt.Errorf("audio capture failed: %v", err))
 	}
+}
 
-	errChan := translationRecognizer.StopContinuousRecognitionAsync()
-	if err := <-errChan; err != nil {
-		return fmt.Errorf("Failed to stop recognition: %v", err)
+// ID returns the session identifier
+func (s *TranslationSession) ID() string {
+	return s.id
+}
+
+// WriteAudio pushes encoded audio into a session created with a StreamFormat
+func (s *TranslationSession) WriteAudio(data []byte) error {
+	if s.capture != nil {
+		return fmt.Errorf("translation session %s reads from input device %s", s.id, s.capture.Device().Name)
 	}
+	return s.pushAudio(data)
+}
 
-	translationRecognizer.Close()
-	translationRecognizer = nil
+func (s *TranslationSession) pushAudio(data []byte) error {
+	s.streamMu.Lock()
+	defer s.streamMu.Unlock()
+	if s.audioStream == nil {
+		return fmt.Errorf("translation session %s reads from a microphone", s.id)
+	}
+	if s.streamClosed {
+		return fmt.Errorf("audio stream is closed")
+	}
+	if err := s.audioStream.Write(data); err != nil {
+		return fmt.Errorf("failed to write audio: %v", err)
+	}
 	return nil
 }
+
+// CloseAudio signals the end of pushed audio; the session stops once the last utterance is translated
+func (s *TranslationSession) CloseAudio() {
+	s.streamMu.Lock()
+	defer s.streamMu.Unlock()
+	if s.audioStream != nil && !s.streamClosed {
+		s.audioStream.CloseStream()
+	}
+}
+
+// Mode returns what the session currently delivers
+func (s *TranslationSession) Mode() TranslationMode {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mode
+}
+
+// SetMode switches between text-only, speech-only and both while the session runs
+func (s *TranslationSession) SetMode(mode TranslationMode) error {
+	if !validTranslationMode(mode) {
+		return fmt.Errorf("unsupported translation mode: %s", mode)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mode = mode
+	return nil
+}
+
+// Status returns a snapshot of the session
+func (s *TranslationSession) Status() TranslationSessionStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := TranslationSessionStatus{
+		ID:              s.id,
+		State:           s.state,
+		Mode:            s.mode,
+		SourceLanguage:  s.config.SourceLanguage,
+		TargetLanguages: s.config.TargetLanguages,
+		StartedAt:       s.startedAt,
+		StoppedAt:       s.stoppedAt,
+		Subscribers:     len(s.subscribers),
+	}
+	if s.err != nil {
+		status.Error = s.err.Error()
+	}
+	return status
+}
+
+// Subscribe registers a subscriber; the channel is closed when the session ends or unsubscribe is called
+func (s *TranslationSession) Subscribe() (<-chan TranslationEvent, func()) {
+	events := make(chan TranslationEvent, eventBufferSize)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != TranslationSessionRunning {
+		close(events)
+		return events, func() {}
+	}
+	id := s.nextID
+	s.nextID++
+	s.subscribers[id] = events
+
+	return events, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if subscriber, ok := s.subscribers[id]; ok {
+			delete(s.subscribers, id)
+			close(subscriber)
+		}
+	}
+}
+
+// Stop ends the session and releases its SDK handles
+func (s *TranslationSession) Stop() error {
+	s.finish(nil)
+	return s.Err()
+}
+
+// Done is closed once the session has ended and its handles are released
+func (s *TranslationSession) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns the error that ended the session, if any
+func (s *TranslationSession) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// synthesize speaks queued translations in order until the session closes
+func (s *TranslationSession) synthesize() {
+	defer close(s.synthesisDone)
+	for {
+		select {
+		case <-s.closing:
+			return
+		case job := <-s.synthesis:
+			if s.Mode() == TranslationModeTextOnly {
+				continue
+			}
+			audioData, err := s.synthesizers[job.language].speak(job.text)
+			if err != nil {
+				s.publish(TranslationEvent{Type: TranslationAudio, Language: job.language, Error: err.Error()})
+				continue
+			}
+			s.publish(TranslationEvent{
+				Type:        TranslationAudio,
+				Language:    job.language,
+				Audio:       audioData,
+				AudioFormat: translationAudioFormat,
+			})
+			if s.player != nil {
+				if err := s.player.PlayWAV(s.ctx, audioData); err != nil && s.ctx.Err() == nil {
+					s.publish(TranslationEvent{Type: TranslationAudio, Language: job.language, Error: err.Error()})
+				}
+			}
+		}
+	}
+}
+
+// speak synthesizes text and returns the audio
+func (t *translationSynthesizer) speak(text string) ([]byte, error) {
+	outcome := <-t.synthesizer.SpeakTextAsync(text)
+	defer outcome.Close()
+	if outcome.Error != nil {
+		return nil, fmt.Errorf("failed to synthesize translation: %v", outcome.Error)
+	}
+	if outcome.Result.Reason != common.SynthesizingAudioCompleted {
+		return nil, fmt.Errorf("translation synthesis did not complete: %v", outcome.Result.Reason)
+	}
+	return outcome.Result.AudioData, nil
+}
+
+func (s *TranslationSession) publish(event TranslationEvent) {
+	event.SessionID = s.id
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, subscriber := range s.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+		}
+	}
+}
+
+func (s *TranslationSession) finish(err error) {
+	s.stopOnce.Do(func() {
+		// Unblock the recognized handler and the synthesis worker before stopping the SDK
+		close(s.closing)
+		s.cancel()
+
+		if s.recognizer != nil {
+			if stopErr := <-s.recognizer.StopContinuousRecognitionAsync(); stopErr != nil && err == nil {
+				err = fmt.Errorf("failed to stop recognition: %v", stopErr)
+			}
+		}
+
+		s.publish(TranslationEvent{Type: TranslationStopped})
+
+		s.mu.Lock()
+		s.state = TranslationSessionStopped
+		if err != nil {
+			s.state = TranslationSessionFailed
+		}
+		s.err = err
+		s.stoppedAt = time.Now()
+		started := !s.startedAt.IsZero()
+		for id, subscriber := range s.subscribers {
+			delete(s.subscribers, id)
+			close(subscriber)
+		}
+		s.mu.Unlock()
+
+		if started {
+			<-s.synthesisDone
+			if s.capture != nil {
+				<-s.captureDone
+			}
+		}
+		s.release()
+		if s.onFinish != nil {
+			s.onFinish()
+		}
+		close(s.done)
+	})
+	<-s.done
+}
+
+func (s *TranslationSession) release() {
+	s.cancel()
+	if s.recognizer != nil {
+		s.recognizer.Close()
+	}
+	for _, synthesizer := range s.synthesizers {
+		synthesizer.close()
+	}
+	if s.audioConfig != nil {
+		s.audioConfig.Close()
+	}
+	s.streamMu.Lock()
+	if s.audioStream != nil && !s.streamClosed {
+		s.audioStream.Close()
+	}
+	s.streamClosed = true
+	s.streamMu.Unlock()
+	if s.speechConfig != nil {
+		s.speechConfig.Close()
+	}
+}
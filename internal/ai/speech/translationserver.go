@@ -0,0 +1,349 @@
+package speech
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/ngothientuong/tngo-ai-svcs/internal/ai/speech/protocol"
+	"github.com/ngothientuong/tngo-ai-svcs/pkg/httpauth"
+)
+
+// TranslationServerConfig configures the WebSocket translation server
+type TranslationServerConfig struct {
+	SpeechKey     string
+	SpeechRegion  string
+	DefaultVoices map[string]string // Voices for target languages that start does not give one
+	InputDevice   string            // PortAudio capture device of sessions started without an audio format
+	OutputDevice  string            // PortAudio device that also plays synthesized translations
+	Conn          *protocol.ConnOptions
+	CheckOrigin   func(r *http.Request) bool // Allows only same-host origins when nil
+}
+
+/*
+TranslationServer serves the versioned translation protocol over WebSocket, see docs/TranslationWebSocketProtocol.md.
+Each connection says hello, then owns at most one session at a time; the session is stopped when the
+connection ends. Binary frames carry the client's microphone audio into the running session.
+*/
+type TranslationServer struct {
+	config   TranslationServerConfig
+	sessions *TranslationSessionManager
+	upgrader websocket.Upgrader
+
+	mu           sync.Mutex
+	conns        map[*protocol.Conn]bool
+	shuttingDown bool
+}
+
+// NewTranslationServer creates a server whose sessions are tracked by sessions
+func NewTranslationServer(sessions *TranslationSessionManager, config TranslationServerConfig) *TranslationServer {
+	checkOrigin := config.CheckOrigin
+	if checkOrigin == nil {
+		checkOrigin = httpauth.OriginChecker(nil)
+	}
+	return &TranslationServer{
+		config:   config,
+		sessions: sessions,
+		upgrader: websocket.Upgrader{CheckOrigin: checkOrigin},
+		conns:    make(map[*protocol.Conn]bool),
+	}
+}
+
+// Shutdown closes every connection, which stops the sessions they own, and refuses new ones.
+// It suits http.Server.RegisterOnShutdown, since http.Server.Shutdown does not close WebSockets.
+func (s *TranslationServer) Shutdown() {
+	s.mu.Lock()
+	s.shuttingDown = true
+	conns := make([]*protocol.Conn, 0, len(s.conns))
+	for conn := range s.conns {
+		conns = append(conns, conn)
+	}
+	s.mu.Unlock()
+
+	for _, conn := range conns {
+		conn.CloseGoingAway("server shutting down")
+	}
+}
+
+// ActiveConnections returns the number of open protocol connections
+func (s *TranslationServer) ActiveConnections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+// track registers conn, refusing it when the server is shutting down
+func (s *TranslationServer) track(conn *protocol.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.shuttingDown {
+		return false
+	}
+	s.conns[conn] = true
+	return true
+}
+
+func (s *TranslationServer) untrack(conn *protocol.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.conns, conn)
+}
+
+// ServeHTTP upgrades the request and runs the protocol until the connection ends
+func (s *TranslationServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ws, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Println("WebSocket upgrade failed:", err)
+		return
+	}
+	conn := protocol.NewConn(ws, s.config.Conn)
+	if !s.track(conn) {
+		conn.CloseGoingAway("server shutting down")
+		return
+	}
+	defer s.untrack(conn)
+
+	if !s.handshake(conn) {
+		return
+	}
+
+	var session *TranslationSession
+	defer func() {
+		if session != nil {
+			s.sessions.Stop(session.ID())
+		}
+		conn.Close()
+	}()
+
+	for {
+		frame, err := conn.ReadFrame()
+		if err != nil {
+			var invalid *protocol.ValidationError
+			if errors.As(err, &invalid) {
+				conn.Send(protocol.ErrorMessage("", invalid.Code, invalid.Message))
+				continue
+			}
+			return
+		}
+
+		// A session that ended by itself, such as after a cancellation, no longer belongs to the connection
+		if session != nil && isDone(session) {
+			session = nil
+		}
+
+		if frame.Binary {
+			if session == nil {
+				conn.Send(protocol.ErrorMessage("", protocol.ErrorNoSession, "audio received without a running session"))
+				continue
+			}
+			if err := session.WriteAudio(frame.Audio); err != nil {
+				conn.Send(protocol.ErrorMessage(session.ID(), protocol.ErrorAudioRejected, err.Error()))
+			}
+			continue
+		}
+
+		message := frame.Message
+		if err := protocol.ValidateClientMessage(message); err != nil {
+			var invalid *protocol.ValidationError
+			errors.As(err, &invalid)
+			conn.Send(protocol.ErrorMessage(message.SessionID, invalid.Code, invalid.Message))
+			continue
+		}
+
+		switch message.Type {
+		case protocol.TypeHello:
+			conn.Send(protocol.ErrorMessage("", protocol.ErrorInvalidMessage, "hello was already received"))
+		case protocol.TypeStart:
+			if session != nil {
+				conn.Send(protocol.ErrorMessage(session.ID(), protocol.ErrorSessionExists, "stop the running session first"))
+				continue
+			}
+			session = s.start(conn, message)
+		case protocol.TypeStop:
+			if session == nil {
+				conn.Send(protocol.ErrorMessage(message.SessionID, protocol.ErrorNoSession, "no session is running"))
+				continue
+			}
+			// The event forwarder reports stopped once the session has released its handles
+			s.sessions.Stop(session.ID())
+			session = nil
+		case protocol.TypeConfig:
+			if session == nil {
+				conn.Send(protocol.ErrorMessage(message.SessionID, protocol.ErrorNoSession, "no session is running"))
+				continue
+			}
+			var config protocol.Config
+			message.Decode(&config)
+			if err := session.SetMode(TranslationMode(config.Mode)); err != nil {
+				conn.Send(protocol.ErrorMessage(session.ID(), protocol.ErrorInvalidMessage, err.Error()))
+			}
+		case protocol.TypeHeartbeat:
+			reply, _ := protocol.NewMessage(protocol.TypeHeartbeat, message.SessionID, protocol.Heartbeat{Timestamp: time.Now().UnixMilli()})
+			conn.Send(reply)
+		}
+	}
+}
+
+// handshake requires hello as the first message and answers with the server capabilities
+func (s *TranslationServer) handshake(conn *protocol.Conn) bool {
+	frame, err := conn.ReadFrame()
+	if err != nil {
+		conn.Close()
+		return false
+	}
+
+	message := frame.Message
+	if frame.Binary || message.Type != protocol.TypeHello {
+		conn.Send(protocol.ErrorMessage("", protocol.ErrorHelloRequired, "the first message must be hello"))
+		conn.CloseWithError(protocol.ErrorHelloRequired)
+		return false
+	}
+	if err := protocol.ValidateClientMessage(message); err != nil {
+		var invalid *protocol.ValidationError
+		errors.As(err, &invalid)
+		conn.Send(protocol.ErrorMessage("", invalid.Code, invalid.Message))
+		conn.CloseWithError(invalid.Code)
+		return false
+	}
+
+	options := conn.Options()
+	capabilities, _ := protocol.NewMessage(protocol.TypeCapabilities, "", protocol.Capabilities{
+		Version:             protocol.Version,
+		AudioFormats:        protocol.AudioFormats,
+		Modes:               protocol.Modes,
+		MaxFrameBytes:       int(options.MaxMessageSize),
+		HeartbeatIntervalMs: int(options.PingInterval / time.Millisecond),
+	})
+	if err := conn.Send(capabilities); err != nil {
+		conn.Close()
+		return false
+	}
+	return true
+}
+
+// start opens the session requested by message and forwards its events to the connection
+func (s *TranslationServer) start(conn *protocol.Conn, message protocol.Message) *TranslationSession {
+	var start protocol.Start
+	message.Decode(&start)
+
+	voices := make(map[string]string)
+	for _, language := range start.TargetLanguages {
+		if voice := start.Voices[language]; voice != "" {
+			voices[language] = voice
+		} else if voice := s.config.DefaultVoices[language]; voice != "" {
+			voices[language] = voice
+		}
+	}
+
+	// Clients that stream their own audio never use the server's input device
+	inputDevice := ""
+	if start.AudioFormat == "" {
+		inputDevice = s.config.InputDevice
+	}
+
+	session, err := s.sessions.Start(TranslationSessionConfig{
+		SpeechKey:       s.config.SpeechKey,
+		SpeechRegion:    s.config.SpeechRegion,
+		SourceLanguage:  start.SourceLanguage,
+		TargetLanguages: start.TargetLanguages,
+		Voices:          voices,
+		Mode:            TranslationMode(start.Mode),
+		StreamFormat:    AudioInputFormat(start.AudioFormat),
+		InputDevice:     inputDevice,
+		OutputDevice:    s.config.OutputDevice,
+	})
+	if err != nil {
+		log.Printf("Translation failed: %v", err)
+		conn.Send(protocol.ErrorMessage("", protocol.ErrorStartFailed, err.Error()))
+		return nil
+	}
+
+	// Subscribe before confirming so no event precedes started
+	events, unsubscribe := session.Subscribe()
+	status := session.Status()
+	started, _ := protocol.NewMessage(protocol.TypeStarted, session.ID(), protocol.Started{
+		SourceLanguage:  status.SourceLanguage,
+		TargetLanguages: status.TargetLanguages,
+		Mode:            string(status.Mode),
+	})
+	if err := conn.Send(started); err != nil {
+		unsubscribe()
+		s.sessions.Stop(session.ID())
+		return nil
+	}
+
+	go forwardTranslationEvents(conn, session, events, unsubscribe)
+	return session
+}
+
+// forwardTranslationEvents converts session events to protocol messages until the session ends
+func forwardTranslationEvents(conn *protocol.Conn, session *TranslationSession, events <-chan TranslationEvent, unsubscribe func()) {
+	defer unsubscribe()
+
+	id := session.ID()
+	for event := range events {
+		var messages []protocol.Message
+		switch event.Type {
+		case TranslationRecognizing:
+			messages = append(messages, newProtocolMessage(protocol.TypePartial, id, protocol.Recognition{
+				Text:         event.SourceText,
+				Translations: event.Translations,
+			}))
+		case TranslationRecognized:
+			messages = append(messages, newProtocolMessage(protocol.TypeFinal, id, protocol.Recognition{Text: event.SourceText}))
+			if len(event.Translations) > 0 {
+				messages = append(messages, newProtocolMessage(protocol.TypeTranslation, id, protocol.Translation{
+					SourceText:   event.SourceText,
+					Translations: event.Translations,
+				}))
+			}
+		case TranslationAudio:
+			if event.Error != "" {
+				messages = append(messages, protocol.ErrorMessage(id, protocol.ErrorSynthesis, fmt.Sprintf("%s: %s", event.Language, event.Error)))
+				break
+			}
+			messages = append(messages, newProtocolMessage(protocol.TypeAudio, id, protocol.Audio{
+				Language: event.Language,
+				Format:   event.AudioFormat,
+				Data:     event.Audio,
+			}))
+		case TranslationCanceled:
+			messages = append(messages, protocol.ErrorMessage(id, protocol.ErrorRecognition, event.Error))
+		}
+
+		for _, message := range messages {
+			if err := conn.Send(message); err != nil {
+				return
+			}
+		}
+	}
+
+	// The channel closes once the session has ended and its error is final
+	<-session.Done()
+	stopped := protocol.Stopped{}
+	if err := session.Err(); err != nil {
+		stopped.Error = err.Error()
+	}
+	conn.Send(newProtocolMessage(protocol.TypeStopped, id, stopped))
+}
+
+func newProtocolMessage(messageType protocol.MessageType, sessionID string, payload interface{}) protocol.Message {
+	message, err := protocol.NewMessage(messageType, sessionID, payload)
+	if err != nil {
+		return protocol.ErrorMessage(sessionID, protocol.ErrorInvalidMessage, err.Error())
+	}
+	return message
+}
+
+func isDone(session *TranslationSession) bool {
+	select {
+	case <-session.Done():
+		return true
+	default:
+		return false
+	}
+}
@@ -27,235 +27,99 @@ func NewTranslatorClient(endpoint, key, region, apiVersion string) *TranslatorCl
 	}
 }
 
-func (c *TranslatorClient) GetLanguages(scope string) ([]byte, error) {
-	if scope == "" {
-		scope = "translation,transliteration,dictionary"
-	}
-	url := fmt.Sprintf("%s/languages?api-version=%s&scope=%s", c.Endpoint, c.APIVersion, scope)
-	client := httpclient.NewClient()
-	headers := map[string]string{
-		"Ocp-Apim-Subscription-Key":    c.Key,
-		"Ocp-Apim-Subscription-Region": c.Region,
-		"Content-Type":                 "application/json",
-	}
-
-	resp, err := client.Get(url, headers, nil)
+// GetLanguages lists the supported languages of each scope (translation, transliteration, dictionary), all when scope is empty
+func (c *TranslatorClient) GetLanguages(scope string) (*Languages, error) {
+	languages, err := decodeResponse[Languages](c.GetLanguagesRaw(scope))
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
+	return &languages, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("request failed: %s", string(bodyBytes))
+func (c *TranslatorClient) GetLanguagesRaw(scope string) ([]byte, error) {
+	if scope == "" {
+		scope = "translation,transliteration,dictionary"
 	}
+	url := fmt.Sprintf("%s/languages?api-version=%s&scope=%s", c.Endpoint, c.APIVersion, scope)
+	return c.send(http.MethodGet, url, nil)
+}
 
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %v", err)
-	}
+// Translate returns one result per text, each holding a translation per target language; opts may be nil
+func (c *TranslatorClient) Translate(texts []string, to []string, opts *TranslateOptions) ([]TranslationResult, error) {
+	return decodeResponse[[]TranslationResult](c.TranslateRaw(texts, to, opts))
+}
 
-	prettyJSON, err := json.MarshalIndent(json.RawMessage(bodyBytes), "", "    ")
-	if err != nil {
-		return nil, fmt.Errorf("failed to format JSON: %v", err)
+func (c *TranslatorClient) TranslateRaw(texts []string, to []string, opts *TranslateOptions) ([]byte, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
 	}
 
-	return prettyJSON, nil
-}
-
-func (c *TranslatorClient) Translate(texts []string, to []string) ([]byte, error) {
 	urlStr := fmt.Sprintf("%s/translate?api-version=%s", c.Endpoint, c.APIVersion)
 	params := url.Values{}
 	for _, lang := range to {
 		params.Add("to", lang)
 	}
+	opts.query(params)
 	urlStr = fmt.Sprintf("%s&%s", urlStr, params.Encode())
 
-	var body []map[string]interface{}
-	for _, text := range texts {
-		body = append(body, map[string]interface{}{
-			"Text": text,
-		})
-	}
-	bodyBytes, err := json.Marshal(body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %v", err)
-	}
-
-	client := httpclient.NewClient()
-	headers := map[string]string{
-		"Ocp-Apim-Subscription-Key":    c.Key,
-		"Ocp-Apim-Subscription-Region": c.Region,
-		"Content-Type":                 "application/json; charset=UTF-8",
-	}
-
-	resp, err := client.Post(urlStr, bytes.NewBuffer(bodyBytes), headers, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("request failed: %s", string(bodyBytes))
-	}
-
-	bodyBytes, err = io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %v", err)
-	}
-
-	prettyJSON, err := json.MarshalIndent(json.RawMessage(bodyBytes), "", "    ")
-	if err != nil {
-		return nil, fmt.Errorf("failed to format JSON: %v", err)
-	}
+	return c.send(http.MethodPost, urlStr, textItems(texts))
+}
 
-	return prettyJSON, nil
+// Transliterate converts each text of language from fromScript to toScript
+func (c *TranslatorClient) Transliterate(texts []string, language, fromScript, toScript string) ([]Transliteration, error) {
+	return decodeResponse[[]Transliteration](c.TransliterateRaw(texts, language, fromScript, toScript))
 }
 
-func (c *TranslatorClient) Transliterate(texts []string, language, fromScript, toScript string) ([]byte, error) {
+func (c *TranslatorClient) TransliterateRaw(texts []string, language, fromScript, toScript string) ([]byte, error) {
 	urlStr := fmt.Sprintf("%s/transliterate?api-version=%s&language=%s&fromScript=%s&toScript=%s", c.Endpoint, c.APIVersion, language, fromScript, toScript)
+	return c.send(http.MethodPost, urlStr, textItems(texts))
+}
 
-	var body []map[string]interface{}
-	for _, text := range texts {
-		body = append(body, map[string]interface{}{
-			"Text": text,
-		})
-	}
-	bodyBytes, err := json.Marshal(body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %v", err)
-	}
-
-	client := httpclient.NewClient()
-	headers := map[string]string{
-		"Ocp-Apim-Subscription-Key":    c.Key,
-		"Ocp-Apim-Subscription-Region": c.Region,
-		"Content-Type":                 "application/json; charset=UTF-8",
-	}
-
-	resp, err := client.Post(urlStr, bytes.NewBuffer(bodyBytes), headers, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("request failed: %s", string(bodyBytes))
-	}
-
-	bodyBytes, err = io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %v", err)
-	}
-
-	prettyJSON, err := json.MarshalIndent(json.RawMessage(bodyBytes), "", "    ")
-	if err != nil {
-		return nil, fmt.Errorf("failed to format JSON: %v", err)
-	}
-
-	return prettyJSON, nil
+// Detect identifies the language of each text
+func (c *TranslatorClient) Detect(texts []string) ([]DetectResult, error) {
+	return decodeResponse[[]DetectResult](c.DetectRaw(texts))
 }
 
-func (c *TranslatorClient) Detect(texts []string) ([]byte, error) {
+func (c *TranslatorClient) DetectRaw(texts []string) ([]byte, error) {
 	url := fmt.Sprintf("%s/detect?api-version=%s", c.Endpoint, c.APIVersion)
+	return c.send(http.MethodPost, url, textItems(texts))
+}
 
-	var body []map[string]interface{}
-	for _, text := range texts {
-		body = append(body, map[string]interface{}{
-			"Text": text,
-		})
-	}
-	bodyBytes, err := json.Marshal(body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %v", err)
-	}
-
-	client := httpclient.NewClient()
-	headers := map[string]string{
-		"Ocp-Apim-Subscription-Key":    c.Key,
-		"Ocp-Apim-Subscription-Region": c.Region,
-		"Content-Type":                 "application/json; charset=UTF-8",
-	}
-
-	resp, err := client.Post(url, bytes.NewBuffer(bodyBytes), headers, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("request failed: %s", string(bodyBytes))
-	}
-
-	bodyBytes, err = io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %v", err)
-	}
-
-	prettyJSON, err := json.MarshalIndent(json.RawMessage(bodyBytes), "", "    ")
-	if err != nil {
-		return nil, fmt.Errorf("failed to format JSON: %v", err)
-	}
-
-	return prettyJSON, nil
+// DictionaryLookup returns the alternative translations of each word or phrase, with their back-translations
+func (c *TranslatorClient) DictionaryLookup(texts []string, from, to string) ([]DictionaryLookupResult, error) {
+	return decodeResponse[[]DictionaryLookupResult](c.DictionaryLookupRaw(texts, from, to))
 }
 
-func (c *TranslatorClient) DictionaryLookup(texts []string, from, to string) ([]byte, error) {
+func (c *TranslatorClient) DictionaryLookupRaw(texts []string, from, to string) ([]byte, error) {
 	urlStr := fmt.Sprintf("%s/dictionary/lookup?api-version=%s&from=%s&to=%s", c.Endpoint, c.APIVersion, from, to)
+	return c.send(http.MethodPost, urlStr, textItems(texts))
+}
 
-	var body []map[string]interface{}
-	for _, text := range texts {
-		body = append(body, map[string]interface{}{
-			"Text": text,
-		})
-	}
-	bodyBytes, err := json.Marshal(body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %v", err)
-	}
-
-	client := httpclient.NewClient()
-	headers := map[string]string{
-		"Ocp-Apim-Subscription-Key":    c.Key,
-		"Ocp-Apim-Subscription-Region": c.Region,
-		"Content-Type":                 "application/json; charset=UTF-8",
-	}
-
-	resp, err := client.Post(urlStr, bytes.NewBuffer(bodyBytes), headers, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("request failed: %s", string(bodyBytes))
-	}
-
-	bodyBytes, err = io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %v", err)
-	}
-
-	prettyJSON, err := json.MarshalIndent(json.RawMessage(bodyBytes), "", "    ")
-	if err != nil {
-		return nil, fmt.Errorf("failed to format JSON: %v", err)
-	}
-
-	return prettyJSON, nil
+// DictionaryExamples returns usage examples of each term and translation pair, see DictionaryLookupResult.ExampleRequests
+func (c *TranslatorClient) DictionaryExamples(requests []DictionaryExampleRequest, from, to string) ([]DictionaryExamplesResult, error) {
+	return decodeResponse[[]DictionaryExamplesResult](c.DictionaryExamplesRaw(requests, from, to))
 }
 
-func (c *TranslatorClient) DictionaryExamples(texts []map[string]string, from, to string) ([]byte, error) {
+func (c *TranslatorClient) DictionaryExamplesRaw(requests []DictionaryExampleRequest, from, to string) ([]byte, error) {
 	urlStr := fmt.Sprintf("%s/dictionary/examples?api-version=%s&from=%s&to=%s", c.Endpoint, c.APIVersion, from, to)
+	return c.send(http.MethodPost, urlStr, requests)
+}
 
-	bodyBytes, err := json.Marshal(texts)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %v", err)
+// textItem is an element of a request body listing texts
+type textItem struct {
+	Text string `json:"Text"`
+}
+
+func textItems(texts []string) []textItem {
+	items := make([]textItem, len(texts))
+	for i, text := range texts {
+		items[i] = textItem{Text: text}
 	}
+	return items
+}
 
+// send calls the API with payload as JSON body, nil for a GET, and returns the indented JSON response
+func (c *TranslatorClient) send(method, urlStr string, payload interface{}) ([]byte, error) {
 	client := httpclient.NewClient()
 	headers := map[string]string{
 		"Ocp-Apim-Subscription-Key":    c.Key,
@@ -263,7 +127,17 @@ func (c *TranslatorClient) DictionaryExamples(texts []map[string]string, from, t
 		"Content-Type":                 "application/json; charset=UTF-8",
 	}
 
-	resp, err := client.Post(urlStr, bytes.NewBuffer(bodyBytes), headers, nil)
+	var resp *http.Response
+	var err error
+	if method == http.MethodGet {
+		resp, err = client.Get(urlStr, headers, nil)
+	} else {
+		bodyBytes, marshalErr := json.Marshal(payload)
+		if marshalErr != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %v", marshalErr)
+		}
+		resp, err = client.Post(urlStr, bytes.NewBuffer(bodyBytes), headers, nil)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %v", err)
 	}
@@ -274,7 +148,7 @@ func (c *TranslatorClient) DictionaryExamples(texts []map[string]string, from, t
 		return nil, fmt.Errorf("request failed: %s", string(bodyBytes))
 	}
 
-	bodyBytes, err = io.ReadAll(resp.Body)
+	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %v", err)
 	}
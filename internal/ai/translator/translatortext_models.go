@@ -0,0 +1,247 @@
+package translator
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/*
+Reference: https://learn.microsoft.com/en-us/azure/ai-services/translator/reference/v3-0-reference
+Typed responses of the Translator text API:
+✅ Translate: translations per target language with alignment, sentence lengths and transliteration
+✅ Transliterate, Detect with alternatives, Languages per scope
+✅ Dictionary lookup with back-translations, and dictionary examples
+Each XxxRaw method returns the indented JSON response instead, for fields not modelled here.
+*/
+
+// DetectedLanguage is the language identified for an input text
+type DetectedLanguage struct {
+	Language string  `json:"language"`
+	Score    float64 `json:"score"`
+}
+
+// TranslationResult holds the translations of one input text
+type TranslationResult struct {
+	DetectedLanguage *DetectedLanguage `json:"detectedLanguage,omitempty"` // Set when no source language is given
+	SourceText       *SourceText       `json:"sourceText,omitempty"`       // Set when the input is not in its language's usual script
+	Translations     []Translation     `json:"translations"`
+}
+
+// SourceText is the input text transliterated to the default script of the source language
+type SourceText struct {
+	Text string `json:"text"`
+}
+
+// Translation is the translation of an input text to one target language
+type Translation struct {
+	To              string           `json:"to"`
+	Text            string           `json:"text"`
+	Transliteration *Transliteration `json:"transliteration,omitempty"`
+	Alignment       *Alignment       `json:"alignment,omitempty"`
+	SentenceLengths *SentenceLengths `json:"sentLen,omitempty"`
+}
+
+// Alignment maps source to translated text as space-separated "start:end-start:end" character ranges
+type Alignment struct {
+	Projection string `json:"proj"`
+}
+
+// AlignedSpan pairs a character range of the source text with one of the translation, ends included
+type AlignedSpan struct {
+	SourceStart, SourceEnd int
+	TargetStart, TargetEnd int
+}
+
+// Spans parses the alignment projection
+func (a *Alignment) Spans() ([]AlignedSpan, error) {
+	var spans []AlignedSpan
+	for _, pair := range strings.Fields(a.Projection) {
+		source, target, ok := strings.Cut(pair, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid alignment %q", pair)
+		}
+		var span AlignedSpan
+		var err error
+		if span.SourceStart, span.SourceEnd, err = parseRange(source); err != nil {
+			return nil, fmt.Errorf("invalid alignment %q: %v", pair, err)
+		}
+		if span.TargetStart, span.TargetEnd, err = parseRange(target); err != nil {
+			return nil, fmt.Errorf("invalid alignment %q: %v", pair, err)
+		}
+		spans = append(spans, span)
+	}
+	return spans, nil
+}
+
+// parseRange parses a "start:end" character range
+func parseRange(value string) (int, int, error) {
+	startValue, endValue, ok := strings.Cut(value, ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("missing ':' in %q", value)
+	}
+	start, err := strconv.Atoi(startValue)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := strconv.Atoi(endValue)
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+// SentenceLengths holds the character length of each sentence in the source and translated texts
+type SentenceLengths struct {
+	Source []int `json:"srcSentLen"`
+	Target []int `json:"transSentLen"`
+}
+
+// Transliteration is a text converted to another script
+type Transliteration struct {
+	Text   string `json:"text"`
+	Script string `json:"script"`
+}
+
+// DetectResult is the language detected for one input text
+type DetectResult struct {
+	Language                   string  `json:"language"`
+	Score                      float64 `json:"score"`
+	IsTranslationSupported     bool    `json:"isTranslationSupported"`
+	IsTransliterationSupported bool    `json:"isTransliterationSupported"`
+
+	// Alternatives are other likely languages, most likely first
+	Alternatives []DetectAlternative `json:"alternatives,omitempty"`
+}
+
+// DetectAlternative is a less likely language for a detected text
+type DetectAlternative struct {
+	Language                   string  `json:"language"`
+	Score                      float64 `json:"score"`
+	IsTranslationSupported     bool    `json:"isTranslationSupported"`
+	IsTransliterationSupported bool    `json:"isTransliterationSupported"`
+}
+
+// DictionaryLookupResult holds the alternative translations of one word or phrase
+type DictionaryLookupResult struct {
+	NormalizedSource string                  `json:"normalizedSource"`
+	DisplaySource    string                  `json:"displaySource"`
+	Translations     []DictionaryTranslation `json:"translations"`
+}
+
+// DictionaryTranslation is one translation of a dictionary term
+type DictionaryTranslation struct {
+	NormalizedTarget string            `json:"normalizedTarget"`
+	DisplayTarget    string            `json:"displayTarget"`
+	PosTag           string            `json:"posTag"`
+	Confidence       float64           `json:"confidence"`
+	PrefixWord       string            `json:"prefixWord"`
+	BackTranslations []BackTranslation `json:"backTranslations"`
+}
+
+// BackTranslation is a source-language translation of a dictionary translation
+type BackTranslation struct {
+	NormalizedText string `json:"normalizedText"`
+	DisplayText    string `json:"displayText"`
+	NumExamples    int    `json:"numExamples"`
+	FrequencyCount int    `json:"frequencyCount"`
+}
+
+// ExampleRequests pairs the term with each of its translations, ready for DictionaryExamples
+func (r *DictionaryLookupResult) ExampleRequests() []DictionaryExampleRequest {
+	requests := make([]DictionaryExampleRequest, 0, len(r.Translations))
+	for _, translation := range r.Translations {
+		requests = append(requests, DictionaryExampleRequest{Text: r.NormalizedSource, Translation: translation.NormalizedTarget})
+	}
+	return requests
+}
+
+// DictionaryExampleRequest asks for usage examples of a term and one of its normalized translations
+type DictionaryExampleRequest struct {
+	Text        string `json:"Text"`
+	Translation string `json:"Translation"`
+}
+
+// DictionaryExamplesResult holds the usage examples of one term and translation pair
+type DictionaryExamplesResult struct {
+	NormalizedSource string              `json:"normalizedSource"`
+	NormalizedTarget string              `json:"normalizedTarget"`
+	Examples         []DictionaryExample `json:"examples"`
+}
+
+// DictionaryExample is a sentence pair; prefix + term + suffix forms each sentence
+type DictionaryExample struct {
+	SourcePrefix string `json:"sourcePrefix"`
+	SourceTerm   string `json:"sourceTerm"`
+	SourceSuffix string `json:"sourceSuffix"`
+	TargetPrefix string `json:"targetPrefix"`
+	TargetTerm   string `json:"targetTerm"`
+	TargetSuffix string `json:"targetSuffix"`
+}
+
+// Source returns the full source sentence
+func (e *DictionaryExample) Source() string {
+	return e.SourcePrefix + e.SourceTerm + e.SourceSuffix
+}
+
+// Target returns the full translated sentence
+func (e *DictionaryExample) Target() string {
+	return e.TargetPrefix + e.TargetTerm + e.TargetSuffix
+}
+
+// Languages lists the languages of each requested scope, keyed by language code
+type Languages struct {
+	Translation     map[string]TranslationLanguage     `json:"translation,omitempty"`
+	Transliteration map[string]TransliterationLanguage `json:"transliteration,omitempty"`
+	Dictionary      map[string]DictionaryLanguage      `json:"dictionary,omitempty"`
+}
+
+// TranslationLanguage is a language that can be translated to and from
+type TranslationLanguage struct {
+	Name       string `json:"name"`
+	NativeName string `json:"nativeName"`
+	Dir        string `json:"dir"` // ltr or rtl
+}
+
+// TransliterationLanguage lists the scripts a language can be transliterated from and to
+type TransliterationLanguage struct {
+	Name       string         `json:"name"`
+	NativeName string         `json:"nativeName"`
+	Scripts    []SourceScript `json:"scripts"`
+}
+
+// Script is a writing system
+type Script struct {
+	Code       string `json:"code"`
+	Name       string `json:"name"`
+	NativeName string `json:"nativeName"`
+	Dir        string `json:"dir"`
+}
+
+// SourceScript is a script that can be transliterated to ToScripts
+type SourceScript struct {
+	Script
+	ToScripts []Script `json:"toScripts"`
+}
+
+// DictionaryLanguage lists the languages a source language has dictionary entries for
+type DictionaryLanguage struct {
+	Name         string               `json:"name"`
+	NativeName   string               `json:"nativeName"`
+	Dir          string               `json:"dir"`
+	Translations []DictionaryLanguage `json:"translations,omitempty"`
+	Code         string               `json:"code,omitempty"` // Set on target languages only
+}
+
+// decodeResponse decodes a JSON response returned by an XxxRaw method
+func decodeResponse[T any](body []byte, err error) (T, error) {
+	var result T
+	if err != nil {
+		return result, err
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return result, fmt.Errorf("failed to decode response: %v", err)
+	}
+	return result, nil
+}
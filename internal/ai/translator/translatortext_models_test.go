@@ -0,0 +1,297 @@
+package translator
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// Recorded responses of the Translator v3 text API
+
+const translateFixture = `[
+    {
+        "detectedLanguage": {"language": "en", "score": 1.0},
+        "translations": [
+            {
+                "text": "Bonjour le monde. Comment allez-vous ?",
+                "to": "fr",
+                "alignment": {"proj": "0:4-0:6 6:10-8:16 12:14-18:23 16:18-33:37"},
+                "sentLen": {"srcSentLen": [12, 12], "transSentLen": [17, 21]}
+            },
+            {
+                "text": "Hallo Welt. Wie geht es dir?",
+                "to": "de",
+                "transliteration": {"script": "Latn", "text": "Hallo Welt. Wie geht es dir?"}
+            }
+        ]
+    }
+]`
+
+const detectFixture = `[
+    {
+        "language": "de",
+        "score": 0.92,
+        "isTranslationSupported": true,
+        "isTransliterationSupported": false,
+        "alternatives": [
+            {"language": "pt", "score": 0.23, "isTranslationSupported": true, "isTransliterationSupported": false},
+            {"language": "sk", "score": 0.23, "isTranslationSupported": true, "isTransliterationSupported": false}
+        ]
+    }
+]`
+
+const dictionaryLookupFixture = `[
+    {
+        "normalizedSource": "fly",
+        "displaySource": "fly",
+        "translations": [
+            {
+                "normalizedTarget": "volar",
+                "displayTarget": "volar",
+                "posTag": "VERB",
+                "confidence": 0.4081,
+                "prefixWord": "",
+                "backTranslations": [
+                    {"normalizedText": "fly", "displayText": "fly", "numExamples": 15, "frequencyCount": 4637},
+                    {"normalizedText": "flying", "displayText": "flying", "numExamples": 15, "frequencyCount": 1365}
+                ]
+            },
+            {
+                "normalizedTarget": "mosca",
+                "displayTarget": "mosca",
+                "posTag": "NOUN",
+                "confidence": 0.2668,
+                "prefixWord": "",
+                "backTranslations": [
+                    {"normalizedText": "fly", "displayText": "fly", "numExamples": 15, "frequencyCount": 1697}
+                ]
+            }
+        ]
+    }
+]`
+
+const dictionaryExamplesFixture = `[
+    {
+        "normalizedSource": "fly",
+        "normalizedTarget": "volar",
+        "examples": [
+            {
+                "sourcePrefix": "They need machines to ",
+                "sourceTerm": "fly",
+                "sourceSuffix": ".",
+                "targetPrefix": "Necesitan máquinas para ",
+                "targetTerm": "volar",
+                "targetSuffix": "."
+            }
+        ]
+    }
+]`
+
+const languagesFixture = `{
+    "translation": {
+        "af": {"name": "Afrikaans", "nativeName": "Afrikaans", "dir": "ltr"},
+        "ar": {"name": "Arabic", "nativeName": "العربية", "dir": "rtl"}
+    },
+    "transliteration": {
+        "ar": {
+            "name": "Arabic",
+            "nativeName": "العربية",
+            "scripts": [
+                {
+                    "code": "Arab", "name": "Arabic", "nativeName": "العربية", "dir": "rtl",
+                    "toScripts": [{"code": "Latn", "name": "Latin", "nativeName": "اللاتينية", "dir": "ltr"}]
+                }
+            ]
+        }
+    },
+    "dictionary": {
+        "af": {
+            "name": "Afrikaans",
+            "nativeName": "Afrikaans",
+            "dir": "ltr",
+            "translations": [{"name": "English", "nativeName": "English", "dir": "ltr", "code": "en"}]
+        }
+    }
+}`
+
+func TestDecodeTranslate(t *testing.T) {
+	results, err := decodeResponse[[]TranslationResult]([]byte(translateFixture), nil)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(results) != 1 || len(results[0].Translations) != 2 {
+		t.Fatalf("expected 1 result with 2 translations, got %+v", results)
+	}
+
+	result := results[0]
+	if result.DetectedLanguage == nil || result.DetectedLanguage.Language != "en" || result.DetectedLanguage.Score != 1.0 {
+		t.Errorf("detected language = %+v", result.DetectedLanguage)
+	}
+
+	fr := result.Translations[0]
+	if fr.To != "fr" || fr.Text != "Bonjour le monde. Comment allez-vous ?" {
+		t.Errorf("translation = %q to %q", fr.Text, fr.To)
+	}
+	if fr.SentenceLengths == nil {
+		t.Fatal("sentence lengths missing")
+	}
+	if !reflect.DeepEqual(fr.SentenceLengths.Source, []int{12, 12}) || !reflect.DeepEqual(fr.SentenceLengths.Target, []int{17, 21}) {
+		t.Errorf("sentence lengths = %+v", fr.SentenceLengths)
+	}
+	if fr.Alignment == nil {
+		t.Fatal("alignment missing")
+	}
+	spans, err := fr.Alignment.Spans()
+	if err != nil {
+		t.Fatalf("spans: %v", err)
+	}
+	wantSpans := []AlignedSpan{
+		{SourceStart: 0, SourceEnd: 4, TargetStart: 0, TargetEnd: 6},
+		{SourceStart: 6, SourceEnd: 10, TargetStart: 8, TargetEnd: 16},
+		{SourceStart: 12, SourceEnd: 14, TargetStart: 18, TargetEnd: 23},
+		{SourceStart: 16, SourceEnd: 18, TargetStart: 33, TargetEnd: 37},
+	}
+	if !reflect.DeepEqual(spans, wantSpans) {
+		t.Errorf("spans = %+v, want %+v", spans, wantSpans)
+	}
+
+	de := result.Translations[1]
+	if de.Alignment != nil || de.SentenceLengths != nil {
+		t.Errorf("unexpected alignment or sentence lengths on %q", de.To)
+	}
+	if de.Transliteration == nil || de.Transliteration.Script != "Latn" {
+		t.Errorf("transliteration = %+v", de.Transliteration)
+	}
+}
+
+func TestAlignmentSpansInvalid(t *testing.T) {
+	for _, projection := range []string{"0:4", "0:4-x:6", "0-0:6", "0:4-0:"} {
+		alignment := Alignment{Projection: projection}
+		if _, err := alignment.Spans(); err == nil {
+			t.Errorf("Spans(%q) succeeded, want an error", projection)
+		}
+	}
+}
+
+func TestDecodeDetect(t *testing.T) {
+	results, err := decodeResponse[[]DetectResult]([]byte(detectFixture), nil)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+
+	result := results[0]
+	if result.Language != "de" || result.Score != 0.92 || !result.IsTranslationSupported || result.IsTransliterationSupported {
+		t.Errorf("result = %+v", result)
+	}
+	want := []DetectAlternative{
+		{Language: "pt", Score: 0.23, IsTranslationSupported: true},
+		{Language: "sk", Score: 0.23, IsTranslationSupported: true},
+	}
+	if !reflect.DeepEqual(result.Alternatives, want) {
+		t.Errorf("alternatives = %+v, want %+v", result.Alternatives, want)
+	}
+}
+
+func TestDecodeDictionaryLookup(t *testing.T) {
+	results, err := decodeResponse[[]DictionaryLookupResult]([]byte(dictionaryLookupFixture), nil)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(results) != 1 || len(results[0].Translations) != 2 {
+		t.Fatalf("expected 1 result with 2 translations, got %+v", results)
+	}
+
+	result := results[0]
+	if result.NormalizedSource != "fly" || result.DisplaySource != "fly" {
+		t.Errorf("source = %q / %q", result.NormalizedSource, result.DisplaySource)
+	}
+
+	volar := result.Translations[0]
+	if volar.NormalizedTarget != "volar" || volar.PosTag != "VERB" || volar.Confidence != 0.4081 {
+		t.Errorf("translation = %+v", volar)
+	}
+	wantBack := []BackTranslation{
+		{NormalizedText: "fly", DisplayText: "fly", NumExamples: 15, FrequencyCount: 4637},
+		{NormalizedText: "flying", DisplayText: "flying", NumExamples: 15, FrequencyCount: 1365},
+	}
+	if !reflect.DeepEqual(volar.BackTranslations, wantBack) {
+		t.Errorf("back-translations = %+v, want %+v", volar.BackTranslations, wantBack)
+	}
+
+	wantRequests := []DictionaryExampleRequest{
+		{Text: "fly", Translation: "volar"},
+		{Text: "fly", Translation: "mosca"},
+	}
+	if requests := result.ExampleRequests(); !reflect.DeepEqual(requests, wantRequests) {
+		t.Errorf("example requests = %+v, want %+v", requests, wantRequests)
+	}
+}
+
+func TestDecodeDictionaryExamples(t *testing.T) {
+	results, err := decodeResponse[[]DictionaryExamplesResult]([]byte(dictionaryExamplesFixture), nil)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(results) != 1 || len(results[0].Examples) != 1 {
+		t.Fatalf("expected 1 result with 1 example, got %+v", results)
+	}
+
+	result := results[0]
+	if result.NormalizedSource != "fly" || result.NormalizedTarget != "volar" {
+		t.Errorf("pair = %q -> %q", result.NormalizedSource, result.NormalizedTarget)
+	}
+	example := result.Examples[0]
+	if example.SourceTerm != "fly" || example.TargetTerm != "volar" {
+		t.Errorf("terms = %q / %q", example.SourceTerm, example.TargetTerm)
+	}
+	if got := example.Source(); got != "They need machines to fly." {
+		t.Errorf("Source() = %q", got)
+	}
+	if got := example.Target(); got != "Necesitan máquinas para volar." {
+		t.Errorf("Target() = %q", got)
+	}
+}
+
+func TestDecodeLanguages(t *testing.T) {
+	languages, err := decodeResponse[Languages]([]byte(languagesFixture), nil)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	if len(languages.Translation) != 2 {
+		t.Errorf("expected 2 translation languages, got %d", len(languages.Translation))
+	}
+	if ar := languages.Translation["ar"]; ar.Name != "Arabic" || ar.Dir != "rtl" {
+		t.Errorf("translation ar = %+v", ar)
+	}
+
+	arabic, ok := languages.Transliteration["ar"]
+	if !ok || len(arabic.Scripts) != 1 {
+		t.Fatalf("transliteration ar = %+v", arabic)
+	}
+	script := arabic.Scripts[0]
+	if script.Code != "Arab" || script.Dir != "rtl" || len(script.ToScripts) != 1 || script.ToScripts[0].Code != "Latn" {
+		t.Errorf("script = %+v", script)
+	}
+
+	afrikaans, ok := languages.Dictionary["af"]
+	if !ok || len(afrikaans.Translations) != 1 {
+		t.Fatalf("dictionary af = %+v", afrikaans)
+	}
+	if target := afrikaans.Translations[0]; target.Code != "en" || target.Name != "English" {
+		t.Errorf("dictionary target = %+v", target)
+	}
+}
+
+func TestDecodeResponseErrors(t *testing.T) {
+	if _, err := decodeResponse[[]DetectResult]([]byte(`{"error": `), nil); err == nil {
+		t.Error("decoding truncated JSON succeeded, want an error")
+	}
+	errRequest := errors.New("request failed")
+	if _, err := decodeResponse[[]DetectResult](nil, errRequest); err != errRequest {
+		t.Errorf("error = %v, want the request error", err)
+	}
+}
@@ -0,0 +1,125 @@
+package translator
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+/*
+Reference: https://learn.microsoft.com/en-us/azure/ai-services/translator/reference/v3-0-translate
+Optional parameters of the translate operation, each sent as the query parameter of the same name:
+✅ from, suggestedFrom, fromScript, toScript
+✅ textType (plain or html), category (Custom Translator), allowFallback
+✅ profanityAction, profanityMarker
+✅ includeAlignment, includeSentenceLength
+*/
+
+// TextType tells whether the texts are plain text or HTML, whose markup is kept as is
+type TextType string
+
+const (
+	TextTypePlain TextType = "plain"
+	TextTypeHTML  TextType = "html"
+)
+
+// ProfanityAction controls how profanities are rendered in translations
+type ProfanityAction string
+
+const (
+	ProfanityActionNoAction ProfanityAction = "NoAction"
+	ProfanityActionMarked   ProfanityAction = "Marked"
+	ProfanityActionDeleted  ProfanityAction = "Deleted"
+)
+
+// ProfanityMarker controls how profanities are marked with ProfanityActionMarked
+type ProfanityMarker string
+
+const (
+	ProfanityMarkerAsterisk ProfanityMarker = "Asterisk" // Replaced with ***
+	ProfanityMarkerTag      ProfanityMarker = "Tag"      // Wrapped in <profanity> tags
+)
+
+// TranslateOptions tunes a translate call; zero values keep the service defaults
+type TranslateOptions struct {
+	// From is the source language, detected when empty; SuggestedFrom is the fallback when detection fails
+	From          string
+	SuggestedFrom string
+
+	// FromScript and ToScript transliterate the input and the translations
+	FromScript string
+	ToScript   string
+
+	TextType TextType
+
+	// Category selects a Custom Translator model or general (the default)
+	Category string
+
+	// AllowFallback set to false fails the call instead of falling back to the general model when Category has no model for a language pair
+	AllowFallback *bool
+
+	ProfanityAction ProfanityAction
+	ProfanityMarker ProfanityMarker
+
+	// IncludeAlignment fills Translation.Alignment, IncludeSentenceLength fills Translation.SentenceLengths
+	IncludeAlignment      bool
+	IncludeSentenceLength bool
+}
+
+// Validate checks the enumerated options
+func (o *TranslateOptions) Validate() error {
+	if o == nil {
+		return nil
+	}
+	switch o.TextType {
+	case "", TextTypePlain, TextTypeHTML:
+	default:
+		return fmt.Errorf("unsupported text type: %s", o.TextType)
+	}
+	switch o.ProfanityAction {
+	case "", ProfanityActionNoAction, ProfanityActionMarked, ProfanityActionDeleted:
+	default:
+		return fmt.Errorf("unsupported profanity action: %s", o.ProfanityAction)
+	}
+	switch o.ProfanityMarker {
+	case "", ProfanityMarkerAsterisk, ProfanityMarkerTag:
+	default:
+		return fmt.Errorf("unsupported profanity marker: %s", o.ProfanityMarker)
+	}
+	if o.ProfanityMarker != "" && o.ProfanityAction != ProfanityActionMarked {
+		return fmt.Errorf("a profanity marker needs the %s profanity action", ProfanityActionMarked)
+	}
+	if o.AllowFallback != nil && !*o.AllowFallback && o.Category == "" {
+		return fmt.Errorf("disabling fallback needs a custom category")
+	}
+	return nil
+}
+
+// query adds the options that are set to params
+func (o *TranslateOptions) query(params url.Values) {
+	if o == nil {
+		return
+	}
+	set := func(name, value string) {
+		if value != "" {
+			params.Set(name, value)
+		}
+	}
+	set("from", o.From)
+	set("suggestedFrom", o.SuggestedFrom)
+	set("fromScript", o.FromScript)
+	set("toScript", o.ToScript)
+	set("textType", string(o.TextType))
+	set("category", o.Category)
+	set("profanityAction", string(o.ProfanityAction))
+	set("profanityMarker", string(o.ProfanityMarker))
+	if o.AllowFallback != nil {
+		params.Set("allowFallback", strconv.FormatBool(*o.AllowFallback))
+	}
+	if o.IncludeAlignment {
+		params.Set("includeAlignment", "true")
+	}
+	if o.IncludeSentenceLength {
+		params.Set("includeSentenceLength", "true")
+	}
+}
@@ -0,0 +1,78 @@
+package translator
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestTranslateOptionsQuery(t *testing.T) {
+	enabled, disabled := true, false
+	tests := []struct {
+		name string
+		opts *TranslateOptions
+		want url.Values
+	}{
+		{"nil options", nil, url.Values{}},
+		{"zero options", &TranslateOptions{}, url.Values{}},
+		{"from", &TranslateOptions{From: "en"}, url.Values{"from": {"en"}}},
+		{"suggested from", &TranslateOptions{SuggestedFrom: "fr"}, url.Values{"suggestedFrom": {"fr"}}},
+		{"scripts", &TranslateOptions{FromScript: "Cyrl", ToScript: "Latn"}, url.Values{"fromScript": {"Cyrl"}, "toScript": {"Latn"}}},
+		{"text type", &TranslateOptions{TextType: TextTypeHTML}, url.Values{"textType": {"html"}}},
+		{"category", &TranslateOptions{Category: "model-1"}, url.Values{"category": {"model-1"}}},
+		{"fallback allowed", &TranslateOptions{AllowFallback: &enabled}, url.Values{"allowFallback": {"true"}}},
+		{"fallback disabled", &TranslateOptions{Category: "model-1", AllowFallback: &disabled}, url.Values{"category": {"model-1"}, "allowFallback": {"false"}}},
+		{"profanity", &TranslateOptions{ProfanityAction: ProfanityActionMarked, ProfanityMarker: ProfanityMarkerTag}, url.Values{"profanityAction": {"Marked"}, "profanityMarker": {"Tag"}}},
+		{"alignment", &TranslateOptions{IncludeAlignment: true}, url.Values{"includeAlignment": {"true"}}},
+		{"sentence length", &TranslateOptions{IncludeSentenceLength: true}, url.Values{"includeSentenceLength": {"true"}}},
+	}
+	for _, test := range tests {
+		params := url.Values{}
+		test.opts.query(params)
+		if !reflect.DeepEqual(params, test.want) {
+			t.Errorf("%s: query = %v, want %v", test.name, params, test.want)
+		}
+	}
+
+	// Options never remove the parameters already set
+	params := url.Values{"api-version": {"3.0"}, "to": {"fr", "de"}}
+	(&TranslateOptions{From: "en"}).query(params)
+	want := url.Values{"api-version": {"3.0"}, "to": {"fr", "de"}, "from": {"en"}}
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("query = %v, want %v", params, want)
+	}
+}
+
+func TestTranslateOptionsValidate(t *testing.T) {
+	enabled, disabled := true, false
+	tests := []struct {
+		name  string
+		opts  *TranslateOptions
+		valid bool
+	}{
+		{"nil options", nil, true},
+		{"zero options", &TranslateOptions{}, true},
+		{"plain text", &TranslateOptions{TextType: TextTypePlain}, true},
+		{"html", &TranslateOptions{TextType: TextTypeHTML}, true},
+		{"unknown text type", &TranslateOptions{TextType: "markdown"}, false},
+		{"deleted profanity", &TranslateOptions{ProfanityAction: ProfanityActionDeleted}, true},
+		{"unknown profanity action", &TranslateOptions{ProfanityAction: "Hidden"}, false},
+		{"marked with a marker", &TranslateOptions{ProfanityAction: ProfanityActionMarked, ProfanityMarker: ProfanityMarkerAsterisk}, true},
+		{"marked without a marker", &TranslateOptions{ProfanityAction: ProfanityActionMarked}, true},
+		{"unknown profanity marker", &TranslateOptions{ProfanityAction: ProfanityActionMarked, ProfanityMarker: "Bold"}, false},
+		{"marker without an action", &TranslateOptions{ProfanityMarker: ProfanityMarkerTag}, false},
+		{"marker with another action", &TranslateOptions{ProfanityAction: ProfanityActionDeleted, ProfanityMarker: ProfanityMarkerTag}, false},
+		{"fallback allowed without category", &TranslateOptions{AllowFallback: &enabled}, true},
+		{"fallback disabled with category", &TranslateOptions{Category: "model-1", AllowFallback: &disabled}, true},
+		{"fallback disabled without category", &TranslateOptions{AllowFallback: &disabled}, false},
+	}
+	for _, test := range tests {
+		err := test.opts.Validate()
+		if test.valid && err != nil {
+			t.Errorf("%s: rejected: %v", test.name, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%s: accepted", test.name)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package audioutil
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// DecodeSamples converts PCM to interleaved samples in [-1, 1]; a trailing partial sample is ignored
+func DecodeSamples(pcm []byte, bitsPerSample int) ([]float64, error) {
+	size := bitsPerSample / 8
+	if size < 1 || size > 4 || bitsPerSample%8 != 0 {
+		return nil, fmt.Errorf("unsupported sample size: %d bits", bitsPerSample)
+	}
+
+	samples := make([]float64, len(pcm)/size)
+	for i := range samples {
+		b := pcm[i*size:]
+		switch size {
+		case 1:
+			samples[i] = (float64(b[0]) - 128) / 128
+		case 2:
+			samples[i] = float64(int16(binary.LittleEndian.Uint16(b))) / 32768
+		case 3:
+			value := int32(b[0]) | int32(b[1])<<8 | int32(int8(b[2]))<<16
+			samples[i] = float64(value) / 8388608
+		case 4:
+			samples[i] = float64(int32(binary.LittleEndian.Uint32(b))) / 2147483648
+		}
+	}
+	return samples, nil
+}
+
+// EncodeSamples converts samples in [-1, 1] to PCM, clipping values outside the range
+func EncodeSamples(samples []float64, bitsPerSample int) ([]byte, error) {
+	size := bitsPerSample / 8
+	if size < 1 || size > 4 || bitsPerSample%8 != 0 {
+		return nil, fmt.Errorf("unsupported sample size: %d bits", bitsPerSample)
+	}
+
+	pcm := make([]byte, len(samples)*size)
+	for i, sample := range samples {
+		sample = math.Max(-1, math.Min(1, sample))
+		b := pcm[i*size:]
+		switch size {
+		case 1:
+			b[0] = uint8(math.Round(sample*127) + 128)
+		case 2:
+			binary.LittleEndian.PutUint16(b, uint16(int16(math.Round(sample*32767))))
+		case 3:
+			value := int32(math.Round(sample * 8388607))
+			b[0], b[1], b[2] = byte(value), byte(value>>8), byte(value>>16)
+		case 4:
+			binary.LittleEndian.PutUint32(b, uint32(int32(math.Round(sample*2147483647))))
+		}
+	}
+	return pcm, nil
+}
+
+// ConvertBitDepth re-encodes PCM from one sample size to another
+func ConvertBitDepth(pcm []byte, fromBits, toBits int) ([]byte, error) {
+	if fromBits == toBits {
+		return pcm, nil
+	}
+	samples, err := DecodeSamples(pcm, fromBits)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeSamples(samples, toBits)
+}
+
+// ConvertChannels changes the layout of interleaved samples: mono is duplicated to every channel,
+// several channels are averaged down to mono, and other layouts keep the first channels, padding with silence
+func ConvertChannels(samples []float64, from, to int) ([]float64, error) {
+	if from <= 0 || to <= 0 {
+		return nil, fmt.Errorf("invalid channel conversion from %d to %d", from, to)
+	}
+	if from == to {
+		return samples, nil
+	}
+
+	frames := len(samples) / from
+	converted := make([]float64, frames*to)
+	for frame := 0; frame < frames; frame++ {
+		in := samples[frame*from : frame*from+from]
+		out := converted[frame*to : frame*to+to]
+		switch {
+		case from == 1:
+			for c := range out {
+				out[c] = in[0]
+			}
+		case to == 1:
+			sum := 0.0
+			for _, sample := range in {
+				sum += sample
+			}
+			out[0] = sum / float64(from)
+		default:
+			copy(out, in)
+		}
+	}
+	return converted, nil
+}
+
+// SplitChannels separates interleaved PCM into one mono stream per channel
+func SplitChannels(pcm []byte, format Format) ([][]byte, error) {
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+
+	size, frameSize := format.BytesPerSample(), format.BytesPerFrame()
+	frames := len(pcm) / frameSize
+	channels := make([][]byte, format.Channels)
+	for c := range channels {
+		channels[c] = make([]byte, frames*size)
+		for frame := 0; frame < frames; frame++ {
+			copy(channels[c][frame*size:(frame+1)*size], pcm[frame*frameSize+c*size:])
+		}
+	}
+	return channels, nil
+}
+
+// InterleaveChannels merges mono streams of equal sample size into one interleaved stream, truncated to the shortest
+func InterleaveChannels(channels [][]byte, bitsPerSample int) ([]byte, error) {
+	size := bitsPerSample / 8
+	if len(channels) == 0 || size < 1 {
+		return nil, fmt.Errorf("nothing to interleave")
+	}
+
+	frames := len(channels[0]) / size
+	for _, channel := range channels[1:] {
+		frames = min(frames, len(channel)/size)
+	}
+	pcm := make([]byte, frames*size*len(channels))
+	for frame := 0; frame < frames; frame++ {
+		for c, channel := range channels {
+			copy(pcm[(frame*len(channels)+c)*size:], channel[frame*size:(frame+1)*size])
+		}
+	}
+	return pcm, nil
+}
+
+// Convert changes the sample rate, channel layout and sample size of PCM in one pass
+func Convert(pcm []byte, from, to Format) ([]byte, error) {
+	converter, err := NewConverter(from, to)
+	if err != nil {
+		return nil, err
+	}
+	converted, err := converter.Write(pcm)
+	if err != nil {
+		return nil, err
+	}
+	rest, err := converter.Flush()
+	if err != nil {
+		return nil, err
+	}
+	return append(converted, rest...), nil
+}
+
+// ConvertWAV converts a WAV file to another format
+func ConvertWAV(data []byte, to Format) ([]byte, error) {
+	from, pcm, err := DecodeWAV(data)
+	if err != nil {
+		return nil, err
+	}
+	converted, err := Convert(pcm, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeWAV(to, converted)
+}
@@ -0,0 +1,247 @@
+package audioutil
+
+import (
+	"fmt"
+	"io"
+	"math"
+)
+
+// resampleTaps is the half width, in input samples, of the interpolation kernel when upsampling
+const resampleTaps = 16
+
+/*
+Resampler converts interleaved samples between rates with a Hann-windowed sinc kernel.
+When downsampling, the kernel cutoff follows the output Nyquist frequency so high frequencies
+are filtered instead of aliased. It keeps state across calls so audio can be streamed in chunks.
+*/
+type Resampler struct {
+	channels  int
+	step      float64 // Input samples per output sample
+	cutoff    float64 // Kernel cutoff relative to the input Nyquist frequency
+	halfWidth int
+
+	buffers   [][]float64 // Pending input per channel, starting halfWidth samples before position
+	position  float64     // Time of the next output sample in input samples, relative to buffers
+	inFrames  int64
+	outFrames int64
+	fromRate  int
+	toRate    int
+}
+
+// NewResampler creates a resampler for interleaved audio with the given channel count
+func NewResampler(channels, fromRate, toRate int) (*Resampler, error) {
+	if channels <= 0 || fromRate <= 0 || toRate <= 0 {
+		return nil, fmt.Errorf("invalid resampling from %d Hz to %d Hz with %d channel(s)", fromRate, toRate, channels)
+	}
+
+	r := &Resampler{
+		channels: channels,
+		step:     float64(fromRate) / float64(toRate),
+		cutoff:   math.Min(1, float64(toRate)/float64(fromRate)),
+		buffers:  make([][]float64, channels),
+		fromRate: fromRate,
+		toRate:   toRate,
+	}
+	r.halfWidth = int(math.Ceil(resampleTaps / r.cutoff))
+
+	// Leading silence lets the first outputs see a full kernel
+	for c := range r.buffers {
+		r.buffers[c] = make([]float64, r.halfWidth)
+	}
+	r.position = float64(r.halfWidth)
+	return r, nil
+}
+
+// Process resamples a chunk of interleaved samples; output lags input by the kernel half width
+func (r *Resampler) Process(samples []float64) []float64 {
+	if r.fromRate == r.toRate {
+		return samples
+	}
+
+	frames := len(samples) / r.channels
+	for c := range r.buffers {
+		for frame := 0; frame < frames; frame++ {
+			r.buffers[c] = append(r.buffers[c], samples[frame*r.channels+c])
+		}
+	}
+	r.inFrames += int64(frames)
+	return r.drain()
+}
+
+// Flush returns the remaining output, so the total length matches the input duration
+func (r *Resampler) Flush() []float64 {
+	if r.fromRate == r.toRate {
+		return nil
+	}
+
+	for c := range r.buffers {
+		r.buffers[c] = append(r.buffers[c], make([]float64, r.halfWidth+1)...)
+	}
+	expected := int64(math.Round(float64(r.inFrames) / r.step))
+	out := r.drain()
+	if excess := min(r.outFrames-expected, int64(len(out)/r.channels)); excess > 0 {
+		out = out[:len(out)-int(excess)*r.channels]
+		r.outFrames -= excess
+	}
+	return out
+}
+
+// drain produces every output sample whose kernel is covered by the buffered input
+func (r *Resampler) drain() []float64 {
+	var out []float64
+	for int(math.Floor(r.position))+r.halfWidth < len(r.buffers[0]) {
+		for c := range r.buffers {
+			out = append(out, r.interpolate(r.buffers[c], r.position))
+		}
+		r.position += r.step
+		r.outFrames++
+	}
+
+	// Drop input no future kernel reaches
+	if drop := int(math.Floor(r.position)) - r.halfWidth; drop > 0 {
+		for c := range r.buffers {
+			r.buffers[c] = append(r.buffers[c][:0], r.buffers[c][drop:]...)
+		}
+		r.position -= float64(drop)
+	}
+	return out
+}
+
+func (r *Resampler) interpolate(buffer []float64, position float64) float64 {
+	center := int(math.Floor(position))
+	sum := 0.0
+	for i := center - r.halfWidth + 1; i <= center+r.halfWidth; i++ {
+		if i < 0 || i >= len(buffer) {
+			continue
+		}
+		x := position - float64(i)
+		sum += buffer[i] * r.kernel(x)
+	}
+	return sum
+}
+
+// kernel is the windowed sinc low-pass filter at distance x input samples
+func (r *Resampler) kernel(x float64) float64 {
+	width := float64(r.halfWidth)
+	if math.Abs(x) >= width {
+		return 0
+	}
+	window := 0.5 + 0.5*math.Cos(math.Pi*x/width)
+	t := x * r.cutoff
+	if t == 0 {
+		return r.cutoff * window
+	}
+	return r.cutoff * math.Sin(math.Pi*t) / (math.Pi * t) * window
+}
+
+// Resample converts interleaved samples from one rate to another
+func Resample(samples []float64, channels, fromRate, toRate int) ([]float64, error) {
+	resampler, err := NewResampler(channels, fromRate, toRate)
+	if err != nil {
+		return nil, err
+	}
+	out := resampler.Process(samples)
+	return append(out, resampler.Flush()...), nil
+}
+
+// Converter changes the format of a PCM stream written to it in chunks of any size
+type Converter struct {
+	from, to  Format
+	resampler *Resampler
+	pending   []byte // Partial frame carried to the next Write
+}
+
+// NewConverter creates a streaming converter between two PCM formats
+func NewConverter(from, to Format) (*Converter, error) {
+	if err := from.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid source format: %v", err)
+	}
+	if err := to.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid target format: %v", err)
+	}
+	resampler, err := NewResampler(to.Channels, from.SampleRate, to.SampleRate)
+	if err != nil {
+		return nil, err
+	}
+	return &Converter{from: from, to: to, resampler: resampler}, nil
+}
+
+// Write converts a chunk and returns the PCM that is ready; resampled output lags slightly behind
+func (c *Converter) Write(pcm []byte) ([]byte, error) {
+	if c.from == c.to {
+		return pcm, nil
+	}
+
+	data := append(c.pending, pcm...)
+	whole := len(data) - len(data)%c.from.BytesPerFrame()
+	c.pending = append([]byte(nil), data[whole:]...)
+
+	samples, err := DecodeSamples(data[:whole], c.from.BitsPerSample)
+	if err != nil {
+		return nil, err
+	}
+	samples, err = ConvertChannels(samples, c.from.Channels, c.to.Channels)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeSamples(c.resampler.Process(samples), c.to.BitsPerSample)
+}
+
+// Flush returns the PCM still held by the resampler at the end of the stream
+func (c *Converter) Flush() ([]byte, error) {
+	if c.from == c.to {
+		return nil, nil
+	}
+	return EncodeSamples(c.resampler.Flush(), c.to.BitsPerSample)
+}
+
+// converterReader converts PCM read from an underlying reader
+type converterReader struct {
+	source    io.Reader
+	converter *Converter
+	buffer    []byte
+	chunk     []byte
+	done      bool
+}
+
+// NewReader converts the PCM read from r from one format to another
+func NewReader(r io.Reader, from, to Format) (io.Reader, error) {
+	converter, err := NewConverter(from, to)
+	if err != nil {
+		return nil, err
+	}
+	// Read about 100 ms of source audio at a time
+	chunkSize := max(from.ByteRate()/10/from.BytesPerFrame(), 1) * from.BytesPerFrame()
+	return &converterReader{source: r, converter: converter, chunk: make([]byte, chunkSize)}, nil
+}
+
+func (r *converterReader) Read(p []byte) (int, error) {
+	for len(r.buffer) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+
+		n, err := r.source.Read(r.chunk)
+		if n > 0 {
+			converted, convertErr := r.converter.Write(r.chunk[:n])
+			if convertErr != nil {
+				return 0, convertErr
+			}
+			r.buffer = append(r.buffer, converted...)
+		}
+		if err == io.EOF {
+			rest, flushErr := r.converter.Flush()
+			if flushErr != nil {
+				return 0, flushErr
+			}
+			r.buffer = append(r.buffer, rest...)
+			r.done = true
+		} else if err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.buffer)
+	r.buffer = r.buffer[n:]
+	return n, nil
+}
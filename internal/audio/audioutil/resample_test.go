@@ -0,0 +1,90 @@
+package audioutil
+
+import (
+	"math"
+	"testing"
+)
+
+// tone returns seconds of a sine wave at frequency Hz, duplicated to every channel
+func tone(frequency float64, sampleRate, channels int, seconds float64) []float64 {
+	frames := int(float64(sampleRate) * seconds)
+	samples := make([]float64, 0, frames*channels)
+	for i := 0; i < frames; i++ {
+		sample := 0.5 * math.Sin(2*math.Pi*frequency*float64(i)/float64(sampleRate))
+		for c := 0; c < channels; c++ {
+			samples = append(samples, sample)
+		}
+	}
+	return samples
+}
+
+func TestResampleLength(t *testing.T) {
+	tests := []struct {
+		channels, fromRate, toRate int
+	}{
+		{1, 48000, 16000},
+		{1, 16000, 48000},
+		{1, 44100, 16000},
+		{1, 22050, 24000},
+		{2, 48000, 16000},
+		{2, 16000, 16000},
+	}
+	for _, test := range tests {
+		input := tone(440, test.fromRate, test.channels, 1.5)
+		out, err := Resample(input, test.channels, test.fromRate, test.toRate)
+		if err != nil {
+			t.Fatalf("%d -> %d Hz: %v", test.fromRate, test.toRate, err)
+		}
+		want := int(math.Round(1.5*float64(test.toRate))) * test.channels
+		if len(out) != want {
+			t.Errorf("%d -> %d Hz, %d channel(s): %d samples, want %d", test.fromRate, test.toRate, test.channels, len(out), want)
+		}
+	}
+
+	if _, err := Resample(nil, 1, 0, 16000); err == nil {
+		t.Error("a zero input rate was accepted")
+	}
+}
+
+func TestResamplerStreaming(t *testing.T) {
+	input := tone(440, 48000, 1, 1)
+	whole, err := Resample(input, 1, 48000, 16000)
+	if err != nil {
+		t.Fatalf("resample: %v", err)
+	}
+
+	// Chunks of odd sizes produce the same audio as one call
+	resampler, err := NewResampler(1, 48000, 16000)
+	if err != nil {
+		t.Fatalf("new resampler: %v", err)
+	}
+	var streamed []float64
+	for start := 0; start < len(input); start += 1001 {
+		streamed = append(streamed, resampler.Process(input[start:min(start+1001, len(input))])...)
+	}
+	streamed = append(streamed, resampler.Flush()...)
+
+	if len(streamed) != len(whole) {
+		t.Fatalf("streamed %d samples, want %d", len(streamed), len(whole))
+	}
+	for i := range whole {
+		if math.Abs(streamed[i]-whole[i]) > 1e-9 {
+			t.Fatalf("sample %d = %f, want %f", i, streamed[i], whole[i])
+		}
+	}
+}
+
+func TestResampleKeepsTone(t *testing.T) {
+	out, err := Resample(tone(440, 48000, 1, 1), 1, 48000, 16000)
+	if err != nil {
+		t.Fatalf("resample: %v", err)
+	}
+	want := tone(440, 16000, 1, 1)
+
+	// Away from the edges the output follows the same tone at the new rate
+	for i := 1000; i < len(want)-1000; i++ {
+		if math.Abs(out[i]-want[i]) > 0.01 {
+			t.Fatalf("sample %d = %f, want %f", i, out[i], want[i])
+		}
+	}
+}
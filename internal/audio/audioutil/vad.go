@@ -0,0 +1,519 @@
+package audioutil
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+/*
+Voice activity detection classifies short frames by energy and zero-crossing rate:
+✅ A frame is voiced when its level clears both a fixed threshold and the tracked noise floor,
+   and it crosses zero less often than broadband noise such as hiss
+✅ Speech opens after MinSpeech of voiced frames and closes after MinSilence without them,
+   so short clicks and pauses between words do not split segments
+✅ Segments are padded so word onsets and trailing consonants are kept
+*/
+
+// VADOptions tunes voice activity detection; zero values use the defaults noted on each field
+type VADOptions struct {
+	FrameDuration       time.Duration // Analysis frame length, 20 ms by default
+	Threshold           float64       // Minimum speech level in dBFS, -45 by default
+	NoiseMargin         float64       // Required level above the noise floor in dB, 10 by default
+	MaxZeroCrossingRate float64       // Zero crossings per sample above which a frame is noise, 0.45 by default
+	MinSpeech           time.Duration // Voiced audio needed to open a segment, 100 ms by default
+	MinSilence          time.Duration // Unvoiced audio needed to close a segment, 500 ms by default
+	Padding             time.Duration // Audio kept before and after each segment, 200 ms by default; NoPadding or any negative value keeps none
+}
+
+// NoPadding disables segment padding, so segments cover only the voiced frames
+const NoPadding time.Duration = -1
+
+func (o *VADOptions) withDefaults() VADOptions {
+	options := VADOptions{}
+	if o != nil {
+		options = *o
+	}
+	if options.FrameDuration <= 0 {
+		options.FrameDuration = 20 * time.Millisecond
+	}
+	if options.Threshold == 0 {
+		options.Threshold = -45
+	}
+	if options.NoiseMargin == 0 {
+		options.NoiseMargin = 10
+	}
+	if options.MaxZeroCrossingRate <= 0 {
+		options.MaxZeroCrossingRate = 0.45
+	}
+	if options.MinSpeech <= 0 {
+		options.MinSpeech = 100 * time.Millisecond
+	}
+	if options.MinSilence <= 0 {
+		options.MinSilence = 500 * time.Millisecond
+	}
+	if options.Padding < 0 {
+		options.Padding = 0
+	} else if options.Padding == 0 {
+		options.Padding = 200 * time.Millisecond
+	}
+	return options
+}
+
+// Segment is a region of speech, as offsets from the start of the stream
+type Segment struct {
+	Start time.Duration `json:"start"`
+	End   time.Duration `json:"end"`
+}
+
+// Duration returns the length of the segment
+func (s Segment) Duration() time.Duration {
+	return s.End - s.Start
+}
+
+// VADStats summarizes the audio a detector has seen
+type VADStats struct {
+	Duration       time.Duration `json:"duration"`
+	SpeechDuration time.Duration `json:"speechDuration"`
+	Segments       int           `json:"segments"`
+}
+
+// SpeechRatio returns the fraction of the audio that is speech
+func (s VADStats) SpeechRatio() float64 {
+	if s.Duration <= 0 {
+		return 0
+	}
+	return float64(s.SpeechDuration) / float64(s.Duration)
+}
+
+func (s VADStats) String() string {
+	return fmt.Sprintf("%d segment(s), %s of speech in %s (%.1f%%)", s.Segments, s.SpeechDuration.Round(time.Millisecond), s.Duration.Round(time.Millisecond), s.SpeechRatio()*100)
+}
+
+/*
+VAD segments a PCM stream written to it in chunks of any size into speech regions.
+Frames are counted from the start of the stream, so segment offsets are positions in the source audio.
+*/
+type VAD struct {
+	format  Format
+	options VADOptions
+
+	frameSize    int // Bytes per analysis frame
+	minSpeech    int64
+	minSilence   int64
+	padding      int64
+	noiseFloor   float64
+	pending      []byte
+	frames       int64 // Frames classified so far
+	inSpeech     bool
+	run          int64 // Consecutive voiced frames while waiting for speech
+	lastVoiced   int64
+	segmentStart int64
+	lastEnd      int64 // End frame of the previous segment
+	segments     []Segment
+	speechFrames int64
+}
+
+// NewVAD creates a detector for PCM in format
+func NewVAD(format Format, opts *VADOptions) (*VAD, error) {
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+
+	options := opts.withDefaults()
+	frameFrames := max(int(int64(format.SampleRate)*int64(options.FrameDuration)/int64(time.Second)), 1)
+	v := &VAD{
+		format:     format,
+		options:    options,
+		frameSize:  frameFrames * format.BytesPerFrame(),
+		noiseFloor: math.Inf(1),
+	}
+	v.minSpeech = v.framesIn(options.MinSpeech)
+	v.minSilence = v.framesIn(options.MinSilence)
+	v.padding = int64(options.Padding / options.FrameDuration)
+	return v, nil
+}
+
+// framesIn returns the number of analysis frames covering d, at least one
+func (v *VAD) framesIn(d time.Duration) int64 {
+	return max(int64(math.Ceil(float64(d)/float64(v.options.FrameDuration))), 1)
+}
+
+// Format returns the PCM format the detector analyzes
+func (v *VAD) Format() Format {
+	return v.format
+}
+
+// Write analyzes a chunk and returns the segments it completed
+func (v *VAD) Write(pcm []byte) ([]Segment, error) {
+	var completed []Segment
+	err := v.each(pcm, func(frame []byte, index int64, voiced bool, segment *Segment) {
+		if segment != nil {
+			completed = append(completed, *segment)
+		}
+	})
+	return completed, err
+}
+
+// Flush closes the segment in progress at the end of the stream and returns it, if any
+func (v *VAD) Flush() []Segment {
+	if !v.inSpeech {
+		return nil
+	}
+	segment := v.close(min(v.lastVoiced+1+v.padding, v.frames))
+	return []Segment{segment}
+}
+
+// InSpeech reports whether the stream is currently inside a speech segment
+func (v *VAD) InSpeech() bool {
+	return v.inSpeech
+}
+
+// Segments returns every segment completed so far
+func (v *VAD) Segments() []Segment {
+	return append([]Segment(nil), v.segments...)
+}
+
+// Stats returns the stream and speech durations seen so far, counting the open segment up to now
+func (v *VAD) Stats() VADStats {
+	speech := v.speechFrames
+	segments := len(v.segments)
+	if v.inSpeech {
+		speech += min(v.lastVoiced+1+v.padding, v.frames) - v.segmentStart
+		segments++
+	}
+	return VADStats{
+		Duration:       v.frameTime(v.frames),
+		SpeechDuration: v.frameTime(speech),
+		Segments:       segments,
+	}
+}
+
+// each classifies every whole frame of pcm, carrying a partial frame to the next call
+func (v *VAD) each(pcm []byte, handle func(frame []byte, index int64, voiced bool, segment *Segment)) error {
+	data := append(v.pending, pcm...)
+	offset := 0
+	for ; offset+v.frameSize <= len(data); offset += v.frameSize {
+		frame := data[offset : offset+v.frameSize]
+		voiced, err := v.classify(frame)
+		if err != nil {
+			return err
+		}
+		index := v.frames
+		segment := v.step(voiced)
+		handle(frame, index, voiced, segment)
+	}
+	v.pending = append([]byte(nil), data[offset:]...)
+	return nil
+}
+
+// classify decides whether one frame is voiced and updates the noise floor
+func (v *VAD) classify(frame []byte) (bool, error) {
+	samples, err := DecodeSamples(frame, v.format.BitsPerSample)
+	if err != nil {
+		return false, err
+	}
+	samples, err = ConvertChannels(samples, v.format.Channels, 1)
+	if err != nil {
+		return false, err
+	}
+
+	energy, crossings := 0.0, 0
+	for i, sample := range samples {
+		energy += sample * sample
+		if i > 0 && (sample >= 0) != (samples[i-1] >= 0) {
+			crossings++
+		}
+	}
+	level := 10 * math.Log10(energy/float64(len(samples))+1e-12)
+	zeroCrossingRate := float64(crossings) / float64(len(samples))
+
+	// The floor drops to quiet frames at once and rises slowly, so speech barely lifts it
+	if level < v.noiseFloor {
+		v.noiseFloor = level
+	} else {
+		v.noiseFloor += (level - v.noiseFloor) * 0.005
+	}
+
+	threshold := math.Max(v.options.Threshold, v.noiseFloor+v.options.NoiseMargin)
+	return level >= threshold && zeroCrossingRate <= v.options.MaxZeroCrossingRate, nil
+}
+
+// step advances the state machine by one frame and returns the segment it closed, if any
+func (v *VAD) step(voiced bool) *Segment {
+	index := v.frames
+	v.frames++
+
+	if !v.inSpeech {
+		if !voiced {
+			v.run = 0
+			return nil
+		}
+		v.run++
+		if v.run >= v.minSpeech {
+			v.inSpeech = true
+			v.run = 0
+			v.lastVoiced = index
+			v.segmentStart = max(index-v.minSpeech+1-v.padding, v.lastEnd, 0)
+		}
+		return nil
+	}
+
+	if voiced {
+		v.lastVoiced = index
+		return nil
+	}
+	if index-v.lastVoiced >= v.minSilence {
+		segment := v.close(v.lastVoiced + 1 + v.padding)
+		return &segment
+	}
+	return nil
+}
+
+// close ends the segment in progress at end, a frame index
+func (v *VAD) close(end int64) Segment {
+	v.inSpeech = false
+	v.lastEnd = end
+	v.speechFrames += end - v.segmentStart
+	segment := Segment{Start: v.frameTime(v.segmentStart), End: v.frameTime(end)}
+	v.segments = append(v.segments, segment)
+	return segment
+}
+
+// speechFrame reports whether a frame already classified belongs to the open segment's audio
+func (v *VAD) speechFrame(index int64) bool {
+	return v.inSpeech && index >= v.segmentStart && index <= v.lastVoiced+v.padding
+}
+
+func (v *VAD) frameTime(frames int64) time.Duration {
+	return time.Duration(frames) * v.options.FrameDuration
+}
+
+// gateFrame is an analysis frame held back in case it becomes the lead-in of a segment
+type gateFrame struct {
+	index int64
+	data  []byte
+}
+
+// gateSpan maps a run of passed audio back to its position in the source stream
+type gateSpan struct {
+	source time.Duration
+	gated  time.Duration
+}
+
+/*
+Gate passes only the speech of a PCM stream, such as audio on its way to a push stream,
+and keeps the lead-in of each segment so it starts with its padding. Removing silence shifts
+later audio earlier; SourceOffset maps offsets in the gated stream back to the source.
+*/
+type Gate struct {
+	vad     *VAD
+	history []gateFrame
+
+	mu     sync.Mutex
+	passed int64 // Frames passed so far
+	spans  []gateSpan
+	stats  VADStats
+}
+
+// NewGate creates a gate for PCM in format
+func NewGate(format Format, opts *VADOptions) (*Gate, error) {
+	vad, err := NewVAD(format, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Gate{vad: vad}, nil
+}
+
+// Write returns the speech in a chunk, including lead-in held back from earlier chunks
+func (g *Gate) Write(pcm []byte) ([]byte, error) {
+	var out []byte
+	// Hold enough for the lead-in of a new segment and for a pause inside an open one
+	lookback := max(g.vad.minSpeech+g.vad.padding, g.vad.minSilence)
+	err := g.vad.each(pcm, func(frame []byte, index int64, voiced bool, segment *Segment) {
+		if g.vad.speechFrame(index) || (segment != nil && index < g.vad.lastEnd) {
+			// Frames of the open segment that arrived before it opened are in the history
+			for _, held := range g.history {
+				if held.index >= g.vad.segmentStart && held.index < index {
+					out = g.pass(out, held.index, held.data)
+				}
+			}
+			g.history = g.history[:0]
+			out = g.pass(out, index, frame)
+		} else {
+			g.history = append(g.history, gateFrame{index: index, data: append([]byte(nil), frame...)})
+			if int64(len(g.history)) > lookback {
+				g.history = g.history[1:]
+			}
+		}
+	})
+
+	g.mu.Lock()
+	g.stats = g.vad.Stats()
+	g.mu.Unlock()
+	return out, err
+}
+
+// pass appends a frame to the output and records where the gated stream jumps ahead in the source
+func (g *Gate) pass(out []byte, index int64, frame []byte) []byte {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	source, gated := g.vad.frameTime(index), g.vad.frameTime(g.passed)
+	if len(g.spans) == 0 || source-gated != g.spans[len(g.spans)-1].source-g.spans[len(g.spans)-1].gated {
+		g.spans = append(g.spans, gateSpan{source: source, gated: gated})
+	}
+	g.passed++
+	return append(out, frame...)
+}
+
+// Flush ends the stream; held-back audio is silence and is dropped
+func (g *Gate) Flush() {
+	g.vad.Flush()
+	g.history = nil
+	g.mu.Lock()
+	g.stats = g.vad.Stats()
+	g.mu.Unlock()
+}
+
+// Stats returns the detector statistics; it is safe to call while another goroutine writes
+func (g *Gate) Stats() VADStats {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.stats
+}
+
+// SourceOffset maps an offset in the gated stream to the same audio in the source stream
+func (g *Gate) SourceOffset(offset time.Duration) time.Duration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	i := sort.Search(len(g.spans), func(i int) bool { return g.spans[i].gated > offset }) - 1
+	if i < 0 {
+		return offset
+	}
+	return g.spans[i].source + offset - g.spans[i].gated
+}
+
+// gatedReader passes only the speech read from an underlying reader
+type gatedReader struct {
+	source io.Reader
+	gate   *Gate
+	buffer []byte
+	chunk  []byte
+	done   bool
+}
+
+// NewGatedReader returns a reader of the speech in r, and the gate reporting its statistics
+func NewGatedReader(r io.Reader, format Format, opts *VADOptions) (io.Reader, *Gate, error) {
+	gate, err := NewGate(format, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	chunk := make([]byte, max(format.ByteRate()/10/gate.vad.frameSize, 1)*gate.vad.frameSize)
+	return &gatedReader{source: r, gate: gate, chunk: chunk}, gate, nil
+}
+
+func (r *gatedReader) Read(p []byte) (int, error) {
+	for len(r.buffer) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+
+		n, err := r.source.Read(r.chunk)
+		if n > 0 {
+			speech, gateErr := r.gate.Write(r.chunk[:n])
+			if gateErr != nil {
+				return 0, gateErr
+			}
+			r.buffer = append(r.buffer, speech...)
+		}
+		if err == io.EOF {
+			r.gate.Flush()
+			r.done = true
+		} else if err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.buffer)
+	r.buffer = r.buffer[n:]
+	return n, nil
+}
+
+// DetectSpeech returns the speech segments of PCM held in memory
+func DetectSpeech(pcm []byte, format Format, opts *VADOptions) ([]Segment, VADStats, error) {
+	vad, err := NewVAD(format, opts)
+	if err != nil {
+		return nil, VADStats{}, err
+	}
+	if _, err := vad.Write(pcm); err != nil {
+		return nil, VADStats{}, err
+	}
+	vad.Flush()
+	return vad.Segments(), vad.Stats(), nil
+}
+
+// TrimSilence removes the silence before the first and after the last speech segment; audio without speech trims to nothing
+func TrimSilence(pcm []byte, format Format, opts *VADOptions) ([]byte, error) {
+	segments, _, err := DetectSpeech(pcm, format, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(segments) == 0 {
+		return nil, nil
+	}
+	start := timeToByte(segments[0].Start, format)
+	end := min(timeToByte(segments[len(segments)-1].End, format), len(pcm)-len(pcm)%format.BytesPerFrame())
+	return pcm[start:end], nil
+}
+
+// Silence returns d of silent PCM in format
+func Silence(format Format, d time.Duration) []byte {
+	pcm := make([]byte, timeToByte(d, format))
+	if format.BitsPerSample == 8 {
+		for i := range pcm {
+			pcm[i] = 128
+		}
+	}
+	return pcm
+}
+
+// StitchWAV joins WAV clips, such as one synthesized utterance each, into one WAV file in the format
+// of the first clip; each clip is trimmed of leading and trailing silence and separated by gap
+func StitchWAV(clips [][]byte, gap time.Duration, opts *VADOptions) ([]byte, error) {
+	if len(clips) == 0 {
+		return nil, fmt.Errorf("no clips to stitch")
+	}
+
+	var format Format
+	var stitched []byte
+	for i, clip := range clips {
+		clipFormat, pcm, err := DecodeWAV(clip)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode clip %d: %v", i, err)
+		}
+		if i == 0 {
+			format = clipFormat
+		} else if pcm, err = Convert(pcm, clipFormat, format); err != nil {
+			return nil, fmt.Errorf("failed to convert clip %d: %v", i, err)
+		}
+		if pcm, err = TrimSilence(pcm, format, opts); err != nil {
+			return nil, err
+		}
+		if len(pcm) == 0 {
+			continue
+		}
+		if len(stitched) > 0 {
+			stitched = append(stitched, Silence(format, gap)...)
+		}
+		stitched = append(stitched, pcm...)
+	}
+	return EncodeWAV(format, stitched)
+}
+
+// timeToByte returns the byte offset of whole frames at d
+func timeToByte(d time.Duration, format Format) int {
+	return int(int64(d)*int64(format.SampleRate)/int64(time.Second)) * format.BytesPerFrame()
+}
@@ -0,0 +1,96 @@
+package audioutil
+
+import (
+	"testing"
+	"time"
+)
+
+// silenceToneSilence returns 1s of silence, 1s of a 440 Hz tone and 1s of silence
+func silenceToneSilence(t *testing.T, format Format) []byte {
+	pcm, err := EncodeSamples(tone(440, format.SampleRate, format.Channels, 1), format.BitsPerSample)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	silence := Silence(format, time.Second)
+	return append(append(append([]byte(nil), silence...), pcm...), silence...)
+}
+
+func TestDetectSpeech(t *testing.T) {
+	format := SpeechInputFormat
+	pcm := silenceToneSilence(t, format)
+	frame := 20 * time.Millisecond
+
+	tests := []struct {
+		name       string
+		opts       *VADOptions
+		start, end time.Duration
+	}{
+		{"default padding", nil, 800 * time.Millisecond, 2200 * time.Millisecond},
+		{"no padding", &VADOptions{Padding: NoPadding}, time.Second, 2 * time.Second},
+		{"custom padding", &VADOptions{Padding: 100 * time.Millisecond}, 900 * time.Millisecond, 2100 * time.Millisecond},
+	}
+	for _, test := range tests {
+		segments, stats, err := DetectSpeech(pcm, format, test.opts)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if len(segments) != 1 {
+			t.Errorf("%s: segments = %v, want one", test.name, segments)
+			continue
+		}
+		if diff := (segments[0].Start - test.start).Abs(); diff > frame {
+			t.Errorf("%s: start = %s, want %s", test.name, segments[0].Start, test.start)
+		}
+		if diff := (segments[0].End - test.end).Abs(); diff > frame {
+			t.Errorf("%s: end = %s, want %s", test.name, segments[0].End, test.end)
+		}
+		if stats.Duration != 3*time.Second || stats.Segments != 1 || stats.SpeechDuration != segments[0].Duration() {
+			t.Errorf("%s: stats = %+v", test.name, stats)
+		}
+	}
+}
+
+func TestDetectSpeechSilence(t *testing.T) {
+	segments, stats, err := DetectSpeech(Silence(SpeechInputFormat, 2*time.Second), SpeechInputFormat, nil)
+	if err != nil {
+		t.Fatalf("detect: %v", err)
+	}
+	if len(segments) != 0 || stats.SpeechDuration != 0 {
+		t.Errorf("segments = %v, stats = %+v, want no speech", segments, stats)
+	}
+}
+
+func TestVADChunks(t *testing.T) {
+	format := SpeechInputFormat
+	pcm := silenceToneSilence(t, format)
+	whole, _, err := DetectSpeech(pcm, format, nil)
+	if err != nil {
+		t.Fatalf("detect: %v", err)
+	}
+
+	// Writes that split frames find the same segments
+	vad, err := NewVAD(format, nil)
+	if err != nil {
+		t.Fatalf("new VAD: %v", err)
+	}
+	for start := 0; start < len(pcm); start += 777 {
+		if _, err := vad.Write(pcm[start:min(start+777, len(pcm))]); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	vad.Flush()
+	if segments := vad.Segments(); len(segments) != len(whole) || segments[0] != whole[0] {
+		t.Errorf("chunked segments = %v, want %v", segments, whole)
+	}
+}
+
+func TestTrimSilence(t *testing.T) {
+	format := SpeechInputFormat
+	trimmed, err := TrimSilence(silenceToneSilence(t, format), format, &VADOptions{Padding: NoPadding})
+	if err != nil {
+		t.Fatalf("trim: %v", err)
+	}
+	if duration := format.Duration(int64(len(trimmed))); (duration - time.Second).Abs() > 20*time.Millisecond {
+		t.Errorf("trimmed to %s, want about 1s", duration)
+	}
+}
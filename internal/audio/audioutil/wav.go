@@ -0,0 +1,201 @@
+package audioutil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+/*
+Reference: http://soundfile.sapp.org/doc/WaveFormat/
+Package audioutil handles integer PCM and RIFF/WAVE files in pure Go, without cgo or external tools:
+✅ Reading and writing WAV headers, for whole files and streams of unknown length
+✅ Converting between 8, 16, 24 and 32-bit samples and between channel layouts
+✅ Resampling, such as to the 8, 16, 24 and 48 kHz rates of the Speech service
+✅ Splitting interleaved channels into separate mono streams
+*/
+
+const (
+	wavFormatPCM        = 1
+	wavFormatExtensible = 0xFFFE
+
+	// UnknownDataSize marks a streamed WAV whose length was unknown when the header was written
+	UnknownDataSize = 0xFFFFFFFF
+)
+
+// Format describes interleaved, little-endian integer PCM; 8-bit samples are unsigned, wider ones signed
+type Format struct {
+	SampleRate    int `json:"sampleRate"`
+	Channels      int `json:"channels"`
+	BitsPerSample int `json:"bitsPerSample"`
+}
+
+// SpeechInputFormat is the Speech SDK's default input: 16 kHz, 16-bit, mono
+var SpeechInputFormat = Format{SampleRate: 16000, Channels: 1, BitsPerSample: 16}
+
+// Validate checks that the format is supported
+func (f Format) Validate() error {
+	if f.SampleRate <= 0 {
+		return fmt.Errorf("invalid sample rate: %d", f.SampleRate)
+	}
+	if f.Channels <= 0 {
+		return fmt.Errorf("invalid channel count: %d", f.Channels)
+	}
+	switch f.BitsPerSample {
+	case 8, 16, 24, 32:
+		return nil
+	}
+	return fmt.Errorf("unsupported sample size: %d bits", f.BitsPerSample)
+}
+
+// BytesPerSample returns the size of one sample of one channel
+func (f Format) BytesPerSample() int {
+	return f.BitsPerSample / 8
+}
+
+// BytesPerFrame returns the size of one sample of every channel
+func (f Format) BytesPerFrame() int {
+	return f.BytesPerSample() * f.Channels
+}
+
+// ByteRate returns the bytes per second of audio
+func (f Format) ByteRate() int {
+	return f.SampleRate * f.BytesPerFrame()
+}
+
+// Duration returns the playing time of size bytes of PCM
+func (f Format) Duration(size int64) time.Duration {
+	if f.ByteRate() == 0 {
+		return 0
+	}
+	return time.Duration(size) * time.Second / time.Duration(f.ByteRate())
+}
+
+func (f Format) String() string {
+	return fmt.Sprintf("%d Hz, %d-bit, %d channel(s)", f.SampleRate, f.BitsPerSample, f.Channels)
+}
+
+// WAVHeader is the format and data size of a WAV file
+type WAVHeader struct {
+	Format
+	DataSize int64 // Bytes of PCM in the data chunk, UnknownDataSize for streams
+}
+
+// ReadWAVHeader consumes a RIFF/WAVE header up to the start of the "data" chunk, leaving r at the first sample
+func ReadWAVHeader(r io.Reader) (*WAVHeader, error) {
+	var riff [12]byte
+	if _, err := io.ReadFull(r, riff[:]); err != nil {
+		return nil, fmt.Errorf("failed to read WAV header: %v", err)
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return nil, fmt.Errorf("not a RIFF/WAVE stream")
+	}
+
+	var header *WAVHeader
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			return nil, fmt.Errorf("failed to read WAV chunk: %v", err)
+		}
+		chunkID := string(chunk[0:4])
+		chunkSize := binary.LittleEndian.Uint32(chunk[4:8])
+
+		switch chunkID {
+		case "fmt ":
+			if chunkSize < 16 {
+				return nil, fmt.Errorf("WAV format chunk too short: %d bytes", chunkSize)
+			}
+			data := make([]byte, chunkSize+chunkSize%2)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return nil, fmt.Errorf("failed to read WAV format chunk: %v", err)
+			}
+			if encoding := binary.LittleEndian.Uint16(data[0:2]); encoding != wavFormatPCM && encoding != wavFormatExtensible {
+				return nil, fmt.Errorf("unsupported WAV encoding: %d", encoding)
+			}
+			header = &WAVHeader{Format: Format{
+				Channels:      int(binary.LittleEndian.Uint16(data[2:4])),
+				SampleRate:    int(binary.LittleEndian.Uint32(data[4:8])),
+				BitsPerSample: int(binary.LittleEndian.Uint16(data[14:16])),
+			}}
+			if err := header.Format.Validate(); err != nil {
+				return nil, err
+			}
+		case "data":
+			if header == nil {
+				return nil, fmt.Errorf("WAV data chunk found before format chunk")
+			}
+			header.DataSize = int64(chunkSize)
+			return header, nil
+		default:
+			if _, err := io.CopyN(io.Discard, r, int64(chunkSize+chunkSize%2)); err != nil {
+				return nil, fmt.Errorf("failed to skip WAV chunk %q: %v", chunkID, err)
+			}
+		}
+	}
+}
+
+// WriteWAVHeader writes a canonical 44-byte header; pass UnknownDataSize when streaming
+func WriteWAVHeader(w io.Writer, format Format, dataSize int64) error {
+	if err := format.Validate(); err != nil {
+		return err
+	}
+
+	riffSize := uint32(UnknownDataSize)
+	if dataSize != UnknownDataSize {
+		if dataSize < 0 || dataSize > UnknownDataSize-36 {
+			return fmt.Errorf("WAV data too large: %d bytes", dataSize)
+		}
+		riffSize = uint32(36 + dataSize + dataSize%2)
+	}
+
+	header := make([]byte, 44)
+	copy(header[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:8], riffSize)
+	copy(header[8:12], "WAVE")
+	copy(header[12:16], "fmt ")
+	binary.LittleEndian.PutUint32(header[16:20], 16)
+	binary.LittleEndian.PutUint16(header[20:22], wavFormatPCM)
+	binary.LittleEndian.PutUint16(header[22:24], uint16(format.Channels))
+	binary.LittleEndian.PutUint32(header[24:28], uint32(format.SampleRate))
+	binary.LittleEndian.PutUint32(header[28:32], uint32(format.ByteRate()))
+	binary.LittleEndian.PutUint16(header[32:34], uint16(format.BytesPerFrame()))
+	binary.LittleEndian.PutUint16(header[34:36], uint16(format.BitsPerSample))
+	copy(header[36:40], "data")
+	binary.LittleEndian.PutUint32(header[40:44], uint32(dataSize))
+
+	if _, err := w.Write(header); err != nil {
+		return fmt.Errorf("failed to write WAV header: %v", err)
+	}
+	return nil
+}
+
+// DecodeWAV returns the format and PCM of a WAV file held in memory
+func DecodeWAV(data []byte) (Format, []byte, error) {
+	reader := bytes.NewReader(data)
+	header, err := ReadWAVHeader(reader)
+	if err != nil {
+		return Format{}, nil, err
+	}
+
+	pcm := data[len(data)-reader.Len():]
+	if header.DataSize != UnknownDataSize && header.DataSize < int64(len(pcm)) {
+		pcm = pcm[:header.DataSize]
+	}
+	return header.Format, pcm[:len(pcm)-len(pcm)%header.BytesPerFrame()], nil
+}
+
+// EncodeWAV wraps PCM in a WAV file
+func EncodeWAV(format Format, pcm []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.Grow(44 + len(pcm) + 1)
+	if err := WriteWAVHeader(&buffer, format, int64(len(pcm))); err != nil {
+		return nil, err
+	}
+	buffer.Write(pcm)
+	if len(pcm)%2 == 1 {
+		buffer.WriteByte(0)
+	}
+	return buffer.Bytes(), nil
+}
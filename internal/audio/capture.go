@@ -0,0 +1,100 @@
+package audio
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/gordonklaus/portaudio"
+)
+
+// SpeechSampleRate is the rate of the Speech SDK's default 16 kHz, 16-bit, mono PCM input
+const SpeechSampleRate = 16000
+
+// CaptureOptions configures a capture; zero values capture 16 kHz mono in 100 ms chunks
+type CaptureOptions struct {
+	SampleRate float64
+	Channels   int
+	ChunkSize  time.Duration // Audio per delivered chunk
+}
+
+// Capture records 16-bit PCM from an input device and hands it to a sink, such as a push stream
+type Capture struct {
+	device  *Device
+	options CaptureOptions
+}
+
+// NewCapture prepares a capture on device; open the stream with Run
+func NewCapture(device *Device, opts *CaptureOptions) (*Capture, error) {
+	if device == nil || !device.IsInput() {
+		return nil, fmt.Errorf("an input device is required")
+	}
+
+	options := CaptureOptions{}
+	if opts != nil {
+		options = *opts
+	}
+	if options.SampleRate <= 0 {
+		options.SampleRate = SpeechSampleRate
+	}
+	if options.Channels <= 0 {
+		options.Channels = 1
+	}
+	if options.Channels > device.MaxInputChannels {
+		return nil, fmt.Errorf("device %s has %d input channels, %d requested", device.Name, device.MaxInputChannels, options.Channels)
+	}
+	if options.ChunkSize <= 0 {
+		options.ChunkSize = 100 * time.Millisecond
+	}
+
+	return &Capture{device: device, options: options}, nil
+}
+
+// Device returns the capture device
+func (c *Capture) Device() *Device {
+	return c.device
+}
+
+// Run records until ctx is done or sink fails; each chunk holds little-endian interleaved samples
+func (c *Capture) Run(ctx context.Context, sink func(pcm []byte) error) error {
+	frames := int(c.options.SampleRate * c.options.ChunkSize.Seconds())
+	buffer := make([]int16, frames*c.options.Channels)
+
+	params := streamParameters(c.device.info, true, c.options.Channels, c.options.SampleRate)
+	params.FramesPerBuffer = frames
+	stream, err := portaudio.OpenStream(params, buffer)
+	if err != nil {
+		return fmt.Errorf("failed to open input stream on %s: %v", c.device.Name, err)
+	}
+	defer stream.Close()
+
+	if err := stream.Start(); err != nil {
+		return fmt.Errorf("failed to start input stream on %s: %v", c.device.Name, err)
+	}
+	defer stream.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		if err := stream.Read(); err != nil && err != portaudio.InputOverflowed {
+			return fmt.Errorf("failed to read from %s: %v", c.device.Name, err)
+		}
+		if err := sink(int16ToBytes(buffer)); err != nil {
+			return err
+		}
+	}
+}
+
+// int16ToBytes encodes samples as little-endian PCM
+func int16ToBytes(samples []int16) []byte {
+	data := make([]byte, len(samples)*2)
+	for i, sample := range samples {
+		binary.LittleEndian.PutUint16(data[i*2:], uint16(sample))
+	}
+	return data
+}
@@ -0,0 +1,210 @@
+package audio
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gordonklaus/portaudio"
+)
+
+/*
+Reference: http://www.portaudio.com/docs/v19-doxydocs/api_overview.html
+Package audio wraps PortAudio device enumeration, microphone capture and speaker playback.
+All audio is 16-bit little-endian PCM, the layout the Speech SDK push and pull streams use.
+PortAudio must be initialized once per process with Initialize before any other call.
+*/
+
+// commonSampleRates are probed for every device, covering the Speech service input and output rates
+var commonSampleRates = []float64{8000, 16000, 22050, 24000, 44100, 48000}
+
+// Initialize starts PortAudio; calls nest, and every successful call must be paired with Terminate
+func Initialize() error {
+	if err := portaudio.Initialize(); err != nil {
+		return fmt.Errorf("failed to initialize PortAudio: %v", err)
+	}
+	return nil
+}
+
+// Terminate releases PortAudio
+func Terminate() error {
+	if err := portaudio.Terminate(); err != nil {
+		return fmt.Errorf("failed to terminate PortAudio: %v", err)
+	}
+	return nil
+}
+
+// Device describes a PortAudio device
+type Device struct {
+	Index             int       `json:"index"`
+	Name              string    `json:"name"`
+	HostAPI           string    `json:"hostApi"`
+	MaxInputChannels  int       `json:"maxInputChannels"`
+	MaxOutputChannels int       `json:"maxOutputChannels"`
+	DefaultSampleRate float64   `json:"defaultSampleRate"`
+	InputSampleRates  []float64 `json:"inputSampleRates,omitempty"`  // Common rates supported for mono 16-bit capture
+	OutputSampleRates []float64 `json:"outputSampleRates,omitempty"` // Common rates supported for mono 16-bit playback
+	DefaultInput      bool      `json:"defaultInput"`
+	DefaultOutput     bool      `json:"defaultOutput"`
+	info              *portaudio.DeviceInfo
+}
+
+// IsInput reports whether the device can capture
+func (d *Device) IsInput() bool {
+	return d.MaxInputChannels > 0
+}
+
+// IsOutput reports whether the device can play
+func (d *Device) IsOutput() bool {
+	return d.MaxOutputChannels > 0
+}
+
+// Devices lists every device with the common sample rates it supports
+func Devices() ([]*Device, error) {
+	infos, err := portaudio.Devices()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audio devices: %v", err)
+	}
+
+	var defaultInput, defaultOutput *portaudio.DeviceInfo
+	if info, err := portaudio.DefaultInputDevice(); err == nil {
+		defaultInput = info
+	}
+	if info, err := portaudio.DefaultOutputDevice(); err == nil {
+		defaultOutput = info
+	}
+
+	devices := make([]*Device, 0, len(infos))
+	for _, info := range infos {
+		device := &Device{
+			Index:             info.Index,
+			Name:              info.Name,
+			MaxInputChannels:  info.MaxInputChannels,
+			MaxOutputChannels: info.MaxOutputChannels,
+			DefaultSampleRate: info.DefaultSampleRate,
+			DefaultInput:      defaultInput != nil && info.Index == defaultInput.Index,
+			DefaultOutput:     defaultOutput != nil && info.Index == defaultOutput.Index,
+			info:              info,
+		}
+		if info.HostApi != nil {
+			device.HostAPI = info.HostApi.Name
+		}
+		if device.IsInput() {
+			device.InputSampleRates = supportedSampleRates(info, true)
+		}
+		if device.IsOutput() {
+			device.OutputSampleRates = supportedSampleRates(info, false)
+		}
+		devices = append(devices, device)
+	}
+	return devices, nil
+}
+
+// InputDevices lists the devices that can capture
+func InputDevices() ([]*Device, error) {
+	return filterDevices((*Device).IsInput)
+}
+
+// OutputDevices lists the devices that can play
+func OutputDevices() ([]*Device, error) {
+	return filterDevices((*Device).IsOutput)
+}
+
+func filterDevices(keep func(*Device) bool) ([]*Device, error) {
+	devices, err := Devices()
+	if err != nil {
+		return nil, err
+	}
+	var filtered []*Device
+	for _, device := range devices {
+		if keep(device) {
+			filtered = append(filtered, device)
+		}
+	}
+	return filtered, nil
+}
+
+// FindInputDevice selects a capture device by index or name; an empty selector picks the default input
+func FindInputDevice(selector string) (*Device, error) {
+	return findDevice(selector, true)
+}
+
+// FindOutputDevice selects a playback device by index or name; an empty selector picks the default output
+func FindOutputDevice(selector string) (*Device, error) {
+	return findDevice(selector, false)
+}
+
+// findDevice matches an index, then an exact name, then a unique case-insensitive name fragment
+func findDevice(selector string, input bool) (*Device, error) {
+	kind, list := "output", OutputDevices
+	if input {
+		kind, list = "input", InputDevices
+	}
+	devices, err := list()
+	if err != nil {
+		return nil, err
+	}
+
+	selector = strings.TrimSpace(selector)
+	if selector == "" {
+		for _, device := range devices {
+			if (input && device.DefaultInput) || (!input && device.DefaultOutput) {
+				return device, nil
+			}
+		}
+		return nil, fmt.Errorf("no default %s device", kind)
+	}
+
+	if index, err := strconv.Atoi(selector); err == nil {
+		for _, device := range devices {
+			if device.Index == index {
+				return device, nil
+			}
+		}
+		return nil, fmt.Errorf("no %s device with index %d", kind, index)
+	}
+
+	var matches []*Device
+	for _, device := range devices {
+		if device.Name == selector {
+			return device, nil
+		}
+		if strings.Contains(strings.ToLower(device.Name), strings.ToLower(selector)) {
+			matches = append(matches, device)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no %s device matches %q", kind, selector)
+	case 1:
+		return matches[0], nil
+	default:
+		names := make([]string, len(matches))
+		for i, device := range matches {
+			names[i] = fmt.Sprintf("%d: %s", device.Index, device.Name)
+		}
+		return nil, fmt.Errorf("%q matches several %s devices: %s", selector, kind, strings.Join(names, ", "))
+	}
+}
+
+// supportedSampleRates probes mono 16-bit streams at the common rates
+func supportedSampleRates(info *portaudio.DeviceInfo, input bool) []float64 {
+	var rates []float64
+	for _, rate := range commonSampleRates {
+		if portaudio.IsFormatSupported(streamParameters(info, input, 1, rate), make([]int16, 1)) == nil {
+			rates = append(rates, rate)
+		}
+	}
+	return rates
+}
+
+// streamParameters describes a blocking mono or multichannel stream on one side of a device
+func streamParameters(info *portaudio.DeviceInfo, input bool, channels int, sampleRate float64) portaudio.StreamParameters {
+	params := portaudio.StreamParameters{SampleRate: sampleRate, FramesPerBuffer: portaudio.FramesPerBufferUnspecified}
+	if input {
+		params.Input = portaudio.StreamDeviceParameters{Device: info, Channels: channels, Latency: info.DefaultLowInputLatency}
+	} else {
+		params.Output = portaudio.StreamDeviceParameters{Device: info, Channels: channels, Latency: info.DefaultHighOutputLatency}
+	}
+	return params
+}
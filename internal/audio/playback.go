@@ -0,0 +1,98 @@
+package audio
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/gordonklaus/portaudio"
+	"github.com/ngothientuong/tngo-ai-svcs/internal/audio/audioutil"
+)
+
+// Player plays 16-bit PCM on an output device, one clip at a time
+type Player struct {
+	device *Device
+	mu     sync.Mutex // Serializes clips so utterances never overlap
+}
+
+// NewPlayer prepares playback on device
+func NewPlayer(device *Device) (*Player, error) {
+	if device == nil || !device.IsOutput() {
+		return nil, fmt.Errorf("an output device is required")
+	}
+	return &Player{device: device}, nil
+}
+
+// Device returns the playback device
+func (p *Player) Device() *Device {
+	return p.device
+}
+
+// Play blocks until pcm, little-endian interleaved samples, has been played or ctx is done
+func (p *Player) Play(ctx context.Context, pcm []byte, sampleRate float64, channels int) error {
+	if channels <= 0 || channels > p.device.MaxOutputChannels {
+		return fmt.Errorf("device %s cannot play %d channels", p.device.Name, channels)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// 50 ms blocks keep cancellation responsive
+	frames := int(sampleRate / 20)
+	block := make([]int16, frames*channels)
+	params := streamParameters(p.device.info, false, channels, sampleRate)
+	params.FramesPerBuffer = frames
+	stream, err := portaudio.OpenStream(params, &block)
+	if err != nil {
+		return fmt.Errorf("failed to open output stream on %s: %v", p.device.Name, err)
+	}
+	defer stream.Close()
+
+	if err := stream.Start(); err != nil {
+		return fmt.Errorf("failed to start output stream on %s: %v", p.device.Name, err)
+	}
+	defer stream.Stop()
+
+	samples := bytesToInt16(pcm)
+	for offset := 0; offset < len(samples); offset += len(block) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		block = block[:cap(block)]
+		n := copy(block, samples[offset:])
+		block = block[:n-n%channels]
+		if len(block) == 0 {
+			break
+		}
+		if err := stream.Write(); err != nil && err != portaudio.OutputUnderflowed {
+			return fmt.Errorf("failed to write to %s: %v", p.device.Name, err)
+		}
+	}
+	return nil
+}
+
+// PlayWAV plays a PCM WAV file such as the output of the Speech synthesizers; samples are played as 16-bit
+func (p *Player) PlayWAV(ctx context.Context, data []byte) error {
+	format, pcm, err := audioutil.DecodeWAV(data)
+	if err != nil {
+		return err
+	}
+	pcm, err = audioutil.ConvertBitDepth(pcm, format.BitsPerSample, 16)
+	if err != nil {
+		return err
+	}
+	return p.Play(ctx, pcm, float64(format.SampleRate), format.Channels)
+}
+
+// bytesToInt16 decodes little-endian PCM, ignoring a trailing odd byte
+func bytesToInt16(data []byte) []int16 {
+	samples := make([]int16, len(data)/2)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(data[i*2:]))
+	}
+	return samples
+}
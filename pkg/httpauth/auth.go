@@ -0,0 +1,110 @@
+package httpauth
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+/*
+Authenticator protects HTTP and WebSocket endpoints with static API keys, HS256 JWTs, or both.
+Credentials are read from, in order:
+✅ Authorization: Bearer <api key or JWT>
+✅ X-API-Key: <api key>
+✅ ?access_token=<api key or JWT>, for browser WebSockets that cannot set headers
+An authenticator without API keys or a JWT validator rejects every request, unless Disabled opts out explicitly.
+*/
+type Authenticator struct {
+	APIKeys []string
+	JWT     *JWTValidator // Nil disables JWT authentication
+
+	// Disabled lets every request through; it must be set on purpose, missing credentials never disable authentication
+	Disabled bool
+}
+
+// Enabled reports whether requests are authenticated
+func (a *Authenticator) Enabled() bool {
+	return a != nil && !a.Disabled
+}
+
+// Validate reports a configuration that would reject every request: no credentials and no explicit opt-out
+func (a *Authenticator) Validate() error {
+	if a == nil || a.Disabled || len(a.APIKeys) > 0 || a.JWT != nil {
+		return nil
+	}
+	return fmt.Errorf("no API keys or JWT secret configured and authentication is not explicitly disabled")
+}
+
+// Authenticate checks the credentials of r
+func (a *Authenticator) Authenticate(r *http.Request) error {
+	if a == nil {
+		return fmt.Errorf("authentication is not configured")
+	}
+	if a.Disabled {
+		return nil
+	}
+	if err := a.Validate(); err != nil {
+		return err
+	}
+
+	credential := requestCredential(r)
+	if credential == "" {
+		return fmt.Errorf("missing credentials")
+	}
+	for _, key := range a.APIKeys {
+		if subtle.ConstantTimeCompare([]byte(credential), []byte(key)) == 1 {
+			return nil
+		}
+	}
+	if a.JWT != nil && strings.Count(credential, ".") == 2 {
+		if _, err := a.JWT.Validate(credential); err != nil {
+			return fmt.Errorf("invalid token: %v", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("invalid credentials")
+}
+
+// Middleware rejects unauthenticated requests with 401 before they reach next
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := a.Authenticate(r); err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func requestCredential(r *http.Request) string {
+	if scheme, credential, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(credential)
+	}
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+	return r.URL.Query().Get("access_token")
+}
+
+// OriginChecker allows requests without an Origin header and those whose origin is in allowed.
+// An empty list allows only same-host origins; "*" allows every origin.
+func OriginChecker(allowed []string) func(r *http.Request) bool {
+	origins := make(map[string]bool, len(allowed))
+	for _, origin := range allowed {
+		origins[strings.TrimRight(strings.ToLower(strings.TrimSpace(origin)), "/")] = true
+	}
+
+	return func(r *http.Request) bool {
+		origin := strings.ToLower(r.Header.Get("Origin"))
+		if origin == "" || origins["*"] || origins[origin] {
+			return true
+		}
+		if len(origins) == 0 {
+			_, host, _ := strings.Cut(origin, "://")
+			return strings.EqualFold(host, r.Host)
+		}
+		return false
+	}
+}
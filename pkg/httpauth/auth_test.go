@@ -0,0 +1,153 @@
+package httpauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func request(target string, header map[string]string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	for name, value := range header {
+		r.Header.Set(name, value)
+	}
+	return r
+}
+
+func TestAuthenticateCredentialSources(t *testing.T) {
+	jwt := &JWTValidator{Secret: testSecret}
+	token := sign(t, jwt, Claims{ExpiresAt: time.Now().Add(time.Hour).Unix()})
+	a := &Authenticator{APIKeys: []string{"key-1", "key-2"}, JWT: jwt}
+
+	tests := []struct {
+		name  string
+		r     *http.Request
+		valid bool
+	}{
+		{"bearer API key", request("/ws", map[string]string{"Authorization": "Bearer key-1"}), true},
+		{"lowercase bearer scheme", request("/ws", map[string]string{"Authorization": "bearer key-2"}), true},
+		{"bearer JWT", request("/ws", map[string]string{"Authorization": "Bearer " + token}), true},
+		{"X-API-Key", request("/ws", map[string]string{"X-API-Key": "key-2"}), true},
+		{"access_token API key", request("/ws?access_token=key-1", nil), true},
+		{"access_token JWT", request("/ws?access_token="+token, nil), true},
+		{"missing credentials", request("/ws", nil), false},
+		{"unknown API key", request("/ws", map[string]string{"X-API-Key": "key-3"}), false},
+		{"API key prefix", request("/ws", map[string]string{"X-API-Key": "key-"}), false},
+		{"API key with suffix", request("/ws", map[string]string{"X-API-Key": "key-11"}), false},
+		{"basic scheme", request("/ws", map[string]string{"Authorization": "Basic key-1"}), false},
+		{"expired JWT", request("/ws", map[string]string{"Authorization": "Bearer " + sign(t, jwt, Claims{ExpiresAt: time.Now().Add(-time.Hour).Unix()})}), false},
+		// The Authorization header takes precedence over X-API-Key and access_token
+		{"invalid bearer with a valid X-API-Key", request("/ws?access_token=key-1", map[string]string{"Authorization": "Bearer wrong", "X-API-Key": "key-1"}), false},
+		{"invalid X-API-Key with a valid access_token", request("/ws?access_token=key-1", map[string]string{"X-API-Key": "wrong"}), false},
+	}
+	for _, test := range tests {
+		err := a.Authenticate(test.r)
+		if test.valid && err != nil {
+			t.Errorf("%s: rejected: %v", test.name, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%s: accepted", test.name)
+		}
+	}
+}
+
+func TestAuthenticateJWTOnly(t *testing.T) {
+	jwt := &JWTValidator{Secret: testSecret}
+	a := &Authenticator{JWT: jwt}
+
+	// Without API keys a non-JWT credential is never compared as a key
+	if err := a.Authenticate(request("/ws", map[string]string{"X-API-Key": "key-1"})); err == nil {
+		t.Error("API key accepted by a JWT-only authenticator")
+	}
+	token := sign(t, jwt, Claims{ExpiresAt: time.Now().Add(time.Hour).Unix()})
+	if err := a.Authenticate(request("/ws", map[string]string{"Authorization": "Bearer " + token})); err != nil {
+		t.Errorf("JWT rejected: %v", err)
+	}
+}
+
+func TestAuthenticatorFailsClosed(t *testing.T) {
+	unconfigured := &Authenticator{}
+	if err := unconfigured.Validate(); err == nil {
+		t.Error("Validate accepted an authenticator without credentials")
+	}
+	if !unconfigured.Enabled() {
+		t.Error("an authenticator without credentials reports authentication disabled")
+	}
+	if err := unconfigured.Authenticate(request("/ws", map[string]string{"X-API-Key": "anything"})); err == nil {
+		t.Error("an authenticator without credentials accepted a request")
+	}
+
+	var missing *Authenticator
+	if err := missing.Authenticate(request("/ws", nil)); err == nil {
+		t.Error("a nil authenticator accepted a request")
+	}
+
+	disabled := &Authenticator{Disabled: true}
+	if err := disabled.Validate(); err != nil {
+		t.Errorf("Validate rejected an explicitly disabled authenticator: %v", err)
+	}
+	if disabled.Enabled() {
+		t.Error("a disabled authenticator reports authentication enabled")
+	}
+	if err := disabled.Authenticate(request("/ws", nil)); err != nil {
+		t.Errorf("a disabled authenticator rejected a request: %v", err)
+	}
+
+	if err := (&Authenticator{APIKeys: []string{"key-1"}}).Validate(); err != nil {
+		t.Errorf("Validate rejected API keys: %v", err)
+	}
+	if err := (&Authenticator{JWT: &JWTValidator{Secret: testSecret}}).Validate(); err != nil {
+		t.Errorf("Validate rejected a JWT validator: %v", err)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	a := &Authenticator{APIKeys: []string{"key-1"}}
+	handler := a.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request("/status", nil))
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+	if recorder.Header().Get("WWW-Authenticate") == "" {
+		t.Error("missing WWW-Authenticate header")
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request("/status", map[string]string{"X-API-Key": "key-1"}))
+	if recorder.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", recorder.Code, http.StatusNoContent)
+	}
+}
+
+func TestOriginChecker(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		host    string
+		origin  string
+		want    bool
+	}{
+		{"no origin header", nil, "example.com", "", true},
+		{"same host", nil, "example.com:8080", "https://example.com:8080", true},
+		{"same host, other case", nil, "example.com", "HTTPS://Example.COM", true},
+		{"other host", nil, "example.com", "https://evil.example", false},
+		{"other port", nil, "example.com:8080", "https://example.com:9090", false},
+		{"listed origin", []string{"https://app.example"}, "api.example", "https://app.example", true},
+		{"listed origin with trailing slash", []string{" https://App.example/ "}, "api.example", "https://app.example", true},
+		{"unlisted origin", []string{"https://app.example"}, "api.example", "https://evil.example", false},
+		{"same host not listed", []string{"https://app.example"}, "api.example", "https://api.example", false},
+		{"wildcard", []string{"*"}, "api.example", "https://anything.example", true},
+	}
+	for _, test := range tests {
+		r := request("/ws", map[string]string{"Origin": test.origin})
+		r.Host = test.host
+		if got := OriginChecker(test.allowed)(r); got != test.want {
+			t.Errorf("%s: allowed = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package httpauth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSecret = []byte("test-secret")
+
+// signRaw signs arbitrary header and claims, for tokens Sign cannot produce
+func signRaw(t *testing.T, secret []byte, header, claims interface{}) string {
+	encode := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	unsigned := encode(header) + "." + encode(claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func sign(t *testing.T, v *JWTValidator, claims Claims) string {
+	token, err := v.Sign(claims)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	return token
+}
+
+func TestJWTRoundTrip(t *testing.T) {
+	v := &JWTValidator{Secret: testSecret, Issuer: "issuer", Audience: "translation"}
+	want := Claims{
+		Subject:   "user-1",
+		Issuer:    "issuer",
+		Audience:  Audience{"other", "translation"},
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		IssuedAt:  time.Now().Unix(),
+	}
+
+	claims, err := v.Validate(sign(t, v, want))
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if !reflect.DeepEqual(*claims, want) {
+		t.Errorf("claims = %+v, want %+v", *claims, want)
+	}
+}
+
+func TestJWTSignature(t *testing.T) {
+	v := &JWTValidator{Secret: testSecret}
+	token := sign(t, v, Claims{ExpiresAt: time.Now().Add(time.Hour).Unix()})
+
+	// Flip one character of the signature
+	tampered := []byte(token)
+	last := len(tampered) - 2
+	if tampered[last] == 'A' {
+		tampered[last] = 'B'
+	} else {
+		tampered[last] = 'A'
+	}
+	if _, err := v.Validate(string(tampered)); err == nil {
+		t.Error("tampered signature accepted")
+	}
+
+	// Claims changed after signing
+	parts := strings.Split(token, ".")
+	forged := signRaw(t, testSecret, map[string]string{"alg": "HS256"}, Claims{Subject: "admin", ExpiresAt: time.Now().Add(time.Hour).Unix()})
+	if _, err := v.Validate(parts[0] + "." + strings.Split(forged, ".")[1] + "." + parts[2]); err == nil {
+		t.Error("modified claims accepted")
+	}
+
+	other := &JWTValidator{Secret: []byte("other-secret")}
+	if _, err := other.Validate(token); err == nil {
+		t.Error("token signed with another secret accepted")
+	}
+
+	for _, malformed := range []string{"", "a.b", "a.b.c.d", "!!.e30.sig"} {
+		if _, err := v.Validate(malformed); err == nil {
+			t.Errorf("malformed token %q accepted", malformed)
+		}
+	}
+}
+
+func TestJWTAlgorithm(t *testing.T) {
+	v := &JWTValidator{Secret: testSecret}
+	claims := Claims{ExpiresAt: time.Now().Add(time.Hour).Unix()}
+
+	for _, alg := range []string{"none", "HS512", "RS256", ""} {
+		token := signRaw(t, testSecret, map[string]string{"alg": alg, "typ": "JWT"}, claims)
+		if _, err := v.Validate(token); err == nil {
+			t.Errorf("alg %q accepted", alg)
+		}
+	}
+
+	// alg none with an empty signature
+	unsigned := strings.Join(strings.Split(signRaw(t, testSecret, map[string]string{"alg": "none"}, claims), ".")[:2], ".") + "."
+	if _, err := v.Validate(unsigned); err == nil {
+		t.Error("unsigned token accepted")
+	}
+}
+
+func TestJWTTimeClaims(t *testing.T) {
+	v := &JWTValidator{Secret: testSecret, Leeway: time.Minute}
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		claims Claims
+		valid  bool
+	}{
+		{"missing exp", Claims{}, false},
+		{"valid exp", Claims{ExpiresAt: now.Add(time.Hour).Unix()}, true},
+		{"expired", Claims{ExpiresAt: now.Add(-time.Hour).Unix()}, false},
+		{"expired within leeway", Claims{ExpiresAt: now.Add(-30 * time.Second).Unix()}, true},
+		{"nbf in the past", Claims{ExpiresAt: now.Add(time.Hour).Unix(), NotBefore: now.Add(-time.Hour).Unix()}, true},
+		{"nbf in the future", Claims{ExpiresAt: now.Add(time.Hour).Unix(), NotBefore: now.Add(time.Hour).Unix()}, false},
+		{"nbf within leeway", Claims{ExpiresAt: now.Add(time.Hour).Unix(), NotBefore: now.Add(30 * time.Second).Unix()}, true},
+	}
+	for _, test := range tests {
+		_, err := v.Validate(sign(t, v, test.claims))
+		if test.valid && err != nil {
+			t.Errorf("%s: rejected: %v", test.name, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%s: accepted", test.name)
+		}
+	}
+
+	strict := &JWTValidator{Secret: testSecret}
+	if _, err := strict.Validate(sign(t, strict, Claims{ExpiresAt: now.Add(-30 * time.Second).Unix()})); err == nil {
+		t.Error("expired token accepted without leeway")
+	}
+}
+
+func TestJWTIssuerAndAudience(t *testing.T) {
+	v := &JWTValidator{Secret: testSecret, Issuer: "issuer", Audience: "translation"}
+	exp := time.Now().Add(time.Hour).Unix()
+	header := map[string]string{"alg": "HS256", "typ": "JWT"}
+
+	tests := []struct {
+		name   string
+		claims map[string]interface{}
+		valid  bool
+	}{
+		{"string audience", map[string]interface{}{"iss": "issuer", "aud": "translation", "exp": exp}, true},
+		{"array audience", map[string]interface{}{"iss": "issuer", "aud": []string{"other", "translation"}, "exp": exp}, true},
+		{"other string audience", map[string]interface{}{"iss": "issuer", "aud": "other", "exp": exp}, false},
+		{"other array audience", map[string]interface{}{"iss": "issuer", "aud": []string{"other"}, "exp": exp}, false},
+		{"missing audience", map[string]interface{}{"iss": "issuer", "exp": exp}, false},
+		{"invalid audience", map[string]interface{}{"iss": "issuer", "aud": 42, "exp": exp}, false},
+		{"other issuer", map[string]interface{}{"iss": "other", "aud": "translation", "exp": exp}, false},
+		{"missing issuer", map[string]interface{}{"aud": "translation", "exp": exp}, false},
+	}
+	for _, test := range tests {
+		_, err := v.Validate(signRaw(t, testSecret, header, test.claims))
+		if test.valid && err != nil {
+			t.Errorf("%s: rejected: %v", test.name, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%s: accepted", test.name)
+		}
+	}
+
+	// Without Issuer and Audience any values are accepted
+	open := &JWTValidator{Secret: testSecret}
+	if _, err := open.Validate(signRaw(t, testSecret, header, map[string]interface{}{"iss": "anyone", "aud": "anything", "exp": exp})); err != nil {
+		t.Errorf("unchecked issuer and audience rejected: %v", err)
+	}
+}
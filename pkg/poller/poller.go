@@ -0,0 +1,39 @@
+package poller
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// CheckFunc reports whether a long-running operation has finished
+type CheckFunc func() (done bool, err error)
+
+// Poll calls check every interval until it reports done, returns an error or ctx ends
+func Poll(ctx context.Context, interval time.Duration, check CheckFunc) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		done, err := check()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("polling stopped: %v", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// PollWithTimeout is Poll bounded by timeout
+func PollWithTimeout(interval, timeout time.Duration, check CheckFunc) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return Poll(ctx, interval, check)
+}